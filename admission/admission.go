@@ -0,0 +1,150 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/metrics"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/search"
+)
+
+// ErrRateLimited dikembalikan Admit jika token klien tidak cukup untuk biaya pencarian.
+var ErrRateLimited = errors.New("batas request klien tercapai")
+
+// RejectedError dikembalikan Admit saat pencarian ditolak. Reason adalah ErrRateLimited,
+// ErrQueueFull, ErrQueueTimeout, atau error context request.
+type RejectedError struct {
+	Reason     error
+	RetryAfter time.Duration
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%v, coba lagi dalam %s", e.Reason, e.RetryAfter.Round(time.Second))
+}
+
+func (e *RejectedError) Unwrap() error {
+	return e.Reason
+}
+
+// Options mengatur Controller. Rate <= 0 mematikan rate limiting per klien, MaxConcurrent <= 0
+// mematikan batas pencarian bersamaan.
+type Options struct {
+	// Rate adalah token per detik per klien; Burst adalah kapasitas bucket.
+	Rate  float64
+	Burst float64
+	// MaxConcurrent adalah jumlah pencarian yang boleh berjalan bersamaan. Pencarian lain
+	// menunggu di antrian berukuran MaxQueue paling lama QueueTimeout.
+	MaxConcurrent int
+	MaxQueue      int
+	QueueTimeout  time.Duration
+	// AlgorithmWeights adalah faktor biaya per algoritma; algoritma yang tidak ada bernilai 1.
+	AlgorithmWeights map[string]float64
+}
+
+// DefaultOptions: 10 token/detik dengan burst 60 per klien, satu pencarian per CPU, dan
+// antrian dua kali jumlah CPU yang menunggu paling lama 10 detik.
+func DefaultOptions() Options {
+	return Options{
+		Rate:          10,
+		Burst:         60,
+		MaxConcurrent: runtime.NumCPU(),
+		MaxQueue:      2 * runtime.NumCPU(),
+		QueueTimeout:  10 * time.Second,
+		// BFS menyimpan seluruh frontier jadi paling mahal; DFS single recipe berhenti di
+		// resep pertama.
+		AlgorithmWeights: map[string]float64{
+			search.AlgorithmBFS: 2,
+			search.AlgorithmBiS: 1.5,
+			search.AlgorithmDFS: 1,
+		},
+	}
+}
+
+// Controller menggabungkan Limiter, Gate, dan estimasi biaya pencarian.
+type Controller struct {
+	opts    Options
+	limiter *Limiter
+	gate    *Gate
+
+	depthsMutex sync.Mutex
+	depths      map[string]map[string]int
+	// depthsOrder adalah urutan versi graf yang masuk depths, untuk membuang yang tertua.
+	depthsOrder []string
+}
+
+// maxCachedDepths adalah jumlah versi graf (dataset dan kombinasi pack) yang kedalaman
+// resepnya disimpan Controller.
+const maxCachedDepths = 32
+
+// New membuat Controller dari opts.
+func New(opts Options) *Controller {
+	c := &Controller{opts: opts, depths: make(map[string]map[string]int)}
+	if opts.Rate > 0 {
+		c.limiter = NewLimiter(opts.Rate, math.Max(opts.Burst, 1))
+	}
+	if opts.MaxConcurrent > 0 {
+		c.gate = NewGate(opts.MaxConcurrent, max(opts.MaxQueue, 0), opts.QueueTimeout)
+	}
+	return c
+}
+
+// Cost memperkirakan biaya pencarian target di graph dalam token. Biaya naik dengan
+// kedalaman resep minimal target (dihitung sekali per versi graf) dan logaritma maxPaths,
+// dikali bobot algoritma. Pencarian termurah (elemen dasar, satu resep, DFS) berbiaya 1.
+func (c *Controller) Cost(graph *loadrecipes.BiGraphAlchemy, algorithm, target string, maxPaths int) float64 {
+	weight, ok := c.opts.AlgorithmWeights[algorithm]
+	if !ok {
+		weight = 1
+	}
+	depth := c.minimalDepths(graph)[target]
+	return weight * (1 + float64(depth)/2) * (1 + math.Log2(float64(max(maxPaths, 1))))
+}
+
+func (c *Controller) minimalDepths(graph *loadrecipes.BiGraphAlchemy) map[string]int {
+	c.depthsMutex.Lock()
+	defer c.depthsMutex.Unlock()
+	depths, cached := c.depths[graph.Version]
+	metrics.CacheLookup("element_depths", cached)
+	if !cached {
+		depths = loadrecipes.ComputeMinimalDepths(graph)
+		if len(c.depthsOrder) >= maxCachedDepths {
+			delete(c.depths, c.depthsOrder[0])
+			c.depthsOrder = c.depthsOrder[1:]
+		}
+		c.depths[graph.Version] = depths
+		c.depthsOrder = append(c.depthsOrder, graph.Version)
+	}
+	return depths
+}
+
+// Admit mengambil cost token dari klien lalu menunggu slot pencarian. Jika berhasil,
+// release harus dipanggil setelah pencarian selesai. Jika ditolak, error berupa *RejectedError.
+func (c *Controller) Admit(ctx context.Context, client string, cost float64) (func(), error) {
+	if c.limiter != nil {
+		if ok, wait := c.limiter.Take(client, cost, time.Now()); !ok {
+			return nil, &RejectedError{Reason: ErrRateLimited, RetryAfter: wait}
+		}
+	}
+	if c.gate == nil {
+		return func() {}, nil
+	}
+	release, err := c.gate.Acquire(ctx)
+	if err != nil {
+		return nil, &RejectedError{Reason: err, RetryAfter: c.opts.QueueTimeout}
+	}
+	return release, nil
+}
+
+// InFlight mengembalikan jumlah pencarian yang sedang berjalan dan yang sedang menunggu.
+func (c *Controller) InFlight() (running, queued int) {
+	if c.gate == nil {
+		return 0, 0
+	}
+	return c.gate.InFlight()
+}
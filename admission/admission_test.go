@@ -0,0 +1,27 @@
+package admission
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+func TestControllerDepthCacheIsBounded(t *testing.T) {
+	c := New(Options{})
+	for i := 0; i < maxCachedDepths+10; i++ {
+		graph := &loadrecipes.BiGraphAlchemy{
+			ChildToParents: map[string][]loadrecipes.PairMats{},
+			BaseElements:   map[string]bool{"Air": true},
+			AllElements:    map[string]bool{"Air": true},
+			Version:        fmt.Sprintf("v%d", i),
+		}
+		c.Cost(graph, "bfs", "Air", 1)
+	}
+	if len(c.depths) != maxCachedDepths || len(c.depthsOrder) != maxCachedDepths {
+		t.Errorf("cache berisi %d versi (%d di urutan), want %d", len(c.depths), len(c.depthsOrder), maxCachedDepths)
+	}
+	if _, ok := c.depths["v0"]; ok {
+		t.Error("versi tertua seharusnya sudah dibuang")
+	}
+}
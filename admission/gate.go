@@ -0,0 +1,70 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Alasan Gate menolak pencarian.
+var (
+	ErrQueueFull    = errors.New("antrian pencarian penuh")
+	ErrQueueTimeout = errors.New("terlalu lama menunggu di antrian pencarian")
+)
+
+// Gate membatasi jumlah pencarian yang berjalan bersamaan. Pencarian yang tidak langsung
+// mendapat slot menunggu di antrian berukuran MaxQueue paling lama QueueTimeout.
+type Gate struct {
+	MaxConcurrent int
+	MaxQueue      int
+	QueueTimeout  time.Duration
+
+	slots chan struct{}
+	queue chan struct{}
+}
+
+// NewGate membuat Gate dengan maxConcurrent slot dan antrian maxQueue.
+func NewGate(maxConcurrent, maxQueue int, queueTimeout time.Duration) *Gate {
+	return &Gate{
+		MaxConcurrent: maxConcurrent,
+		MaxQueue:      maxQueue,
+		QueueTimeout:  queueTimeout,
+		slots:         make(chan struct{}, maxConcurrent),
+		queue:         make(chan struct{}, maxQueue),
+	}
+}
+
+// Acquire menunggu slot pencarian. release harus dipanggil setelah pencarian selesai.
+// Jika antrian penuh, Acquire langsung gagal dengan ErrQueueFull; jika menunggu lebih dari
+// QueueTimeout, gagal dengan ErrQueueTimeout. Error ctx dikembalikan apa adanya.
+func (g *Gate) Acquire(ctx context.Context) (func(), error) {
+	release := func() { <-g.slots }
+	select {
+	case g.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	select {
+	case g.queue <- struct{}{}:
+	default:
+		return nil, ErrQueueFull
+	}
+	defer func() { <-g.queue }()
+
+	timer := time.NewTimer(g.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case g.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, ErrQueueTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// InFlight mengembalikan jumlah pencarian yang sedang berjalan dan yang sedang menunggu.
+func (g *Gate) InFlight() (running, queued int) {
+	return len(g.slots), len(g.queue)
+}
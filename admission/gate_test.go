@@ -0,0 +1,85 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitQueued menunggu sampai g punya queued pencarian di antrian.
+func waitQueued(t *testing.T, g *Gate, queued int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, q := g.InFlight(); q == queued {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("antrian tidak mencapai %d", queued)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGateAcquireQueueFull(t *testing.T) {
+	g := NewGate(1, 1, time.Minute)
+	release, err := g.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire pertama: %v", err)
+	}
+
+	waiterDone := make(chan error, 1)
+	go func() {
+		waiterRelease, err := g.Acquire(context.Background())
+		if err == nil {
+			waiterRelease()
+		}
+		waiterDone <- err
+	}()
+	waitQueued(t, g, 1)
+
+	if _, err := g.Acquire(context.Background()); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Acquire saat antrian penuh = %v, want ErrQueueFull", err)
+	}
+
+	// Pencarian yang mengantri mendapat slot setelah slot pertama dilepas.
+	release()
+	if err := <-waiterDone; err != nil {
+		t.Errorf("pencarian di antrian = %v, want berhasil", err)
+	}
+	if running, queued := g.InFlight(); running != 0 || queued != 0 {
+		t.Errorf("InFlight = %d, %d; want 0, 0", running, queued)
+	}
+}
+
+func TestGateAcquireTimeout(t *testing.T) {
+	g := NewGate(1, 1, 10*time.Millisecond)
+	release, err := g.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire pertama: %v", err)
+	}
+	defer release()
+
+	if _, err := g.Acquire(context.Background()); !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("Acquire = %v, want ErrQueueTimeout", err)
+	}
+	if _, queued := g.InFlight(); queued != 0 {
+		t.Errorf("antrian tidak dikosongkan setelah timeout: %d", queued)
+	}
+}
+
+func TestGateAcquireContextCanceled(t *testing.T) {
+	g := NewGate(1, 1, time.Minute)
+	release, err := g.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire pertama: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Package admission membatasi beban pencarian: token bucket per klien, batas pencarian yang
+// berjalan bersamaan dengan antrian terbatas, dan estimasi biaya tiap pencarian.
+package admission
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter adalah token bucket per klien. Setiap klien mulai dengan Burst token dan mendapat
+// Rate token per detik sampai kembali ke Burst.
+type Limiter struct {
+	Rate  float64
+	Burst float64
+
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiterSweepInterval adalah jarak minimal antar pembersihan bucket klien yang sudah penuh.
+const limiterSweepInterval = time.Minute
+
+// NewLimiter membuat Limiter dengan rate token per detik dan kapasitas burst.
+func NewLimiter(rate, burst float64) *Limiter {
+	return &Limiter{Rate: rate, Burst: burst, buckets: make(map[string]*bucket)}
+}
+
+// Take mengambil cost token dari bucket client. Jika token tidak cukup, tidak ada token yang
+// diambil dan Take mengembalikan false beserta waktu tunggu sampai token cukup. Cost di atas
+// Burst dianggap Burst, supaya pencarian mahal tetap bisa lolos saat bucket penuh.
+func (l *Limiter) Take(client string, cost float64, now time.Time) (bool, time.Duration) {
+	cost = math.Min(cost, l.Burst)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Sweep dulu: jika bucket client dibuang, ia dibuat ulang penuh di bawah, bukan dipotong
+	// token-nya setelah dibuang.
+	l.sweep(now)
+	b, exists := l.buckets[client]
+	if !exists {
+		b = &bucket{tokens: l.Burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	if b.tokens >= cost {
+		b.tokens -= cost
+		return true, 0
+	}
+	if l.Rate <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	wait := time.Duration((cost - b.tokens) / l.Rate * float64(time.Second))
+	return false, wait
+}
+
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	return math.Min(l.Burst, b.tokens+elapsed*l.Rate)
+}
+
+// sweep membuang bucket yang sudah terisi penuh lagi; klien itu sama saja dengan klien baru.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for client, b := range l.buckets {
+		if l.refill(b, now) >= l.Burst {
+			delete(l.buckets, client)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package admission
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLimiterTake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	type take struct {
+		after    time.Duration // sejak start
+		cost     float64
+		wantOK   bool
+		wantWait time.Duration
+	}
+	tests := []struct {
+		name  string
+		rate  float64
+		burst float64
+		takes []take
+	}{
+		{
+			name: "burst lalu habis", rate: 1, burst: 3,
+			takes: []take{
+				{cost: 2, wantOK: true},
+				{cost: 1, wantOK: true},
+				{cost: 1, wantOK: false, wantWait: time.Second},
+			},
+		},
+		{
+			name: "refill sesuai rate", rate: 2, burst: 4,
+			takes: []take{
+				{cost: 4, wantOK: true},
+				{after: 500 * time.Millisecond, cost: 2, wantOK: false, wantWait: 500 * time.Millisecond},
+				{after: time.Second, cost: 2, wantOK: true},
+			},
+		},
+		{
+			name: "refill tidak melebihi burst", rate: 10, burst: 2,
+			takes: []take{
+				{cost: 2, wantOK: true},
+				{after: time.Hour, cost: 2, wantOK: true},
+				{after: time.Hour, cost: 1, wantOK: false, wantWait: 100 * time.Millisecond},
+			},
+		},
+		{
+			name: "cost di atas burst dijepit ke burst", rate: 1, burst: 2,
+			takes: []take{
+				{cost: 50, wantOK: true},
+				{cost: 50, wantOK: false, wantWait: 2 * time.Second},
+				{after: 2 * time.Second, cost: 50, wantOK: true},
+			},
+		},
+		{
+			name: "gagal tidak mengambil token", rate: 1, burst: 3,
+			takes: []take{
+				{cost: 2, wantOK: true},
+				{cost: 2, wantOK: false, wantWait: time.Second},
+				{cost: 1, wantOK: true},
+			},
+		},
+		{
+			name: "rate nol tidak pernah refill", rate: 0, burst: 1,
+			takes: []take{
+				{cost: 1, wantOK: true},
+				{after: time.Hour, cost: 1, wantOK: false, wantWait: time.Duration(math.MaxInt64)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.rate, tt.burst)
+			for i, step := range tt.takes {
+				ok, wait := l.Take("client", step.cost, start.Add(step.after))
+				if ok != step.wantOK || wait != step.wantWait {
+					t.Errorf("Take #%d = %v, %v; want %v, %v", i, ok, wait, step.wantOK, step.wantWait)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterClientsAreIndependent(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(1, 1)
+	if ok, _ := l.Take("a", 1, now); !ok {
+		t.Fatal("klien a ditolak")
+	}
+	if ok, _ := l.Take("a", 1, now); ok {
+		t.Error("klien a lolos tanpa token")
+	}
+	if ok, _ := l.Take("b", 1, now); !ok {
+		t.Error("klien b ikut dibatasi oleh bucket klien a")
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(1, 10)
+
+	// Sweep pertama terjadi di Take pertama; setelah itu baru lagi setelah limiterSweepInterval.
+	l.Take("idle", 10, start)
+	l.Take("busy", 1, start)
+	if len(l.buckets) != 2 {
+		t.Fatalf("buckets = %d, want 2", len(l.buckets))
+	}
+
+	// "idle" butuh 10 detik untuk penuh lagi, tapi sweep belum jatuh tempo.
+	l.Take("busy", 1, start.Add(30*time.Second))
+	if len(l.buckets) != 2 {
+		t.Errorf("bucket dibuang sebelum limiterSweepInterval: %d bucket", len(l.buckets))
+	}
+
+	// Setelah interval, "idle" sudah penuh dan dibuang; "busy" baru saja mengambil token.
+	l.Take("busy", 10, start.Add(limiterSweepInterval+30*time.Second))
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("bucket yang sudah penuh tidak dibuang")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("bucket yang belum penuh ikut dibuang")
+	}
+}
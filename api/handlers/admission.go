@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/admission"
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+// Admission membatasi pencarian per klien dan secara global. nil berarti tanpa batas.
+var Admission *admission.Controller
+
+// TrustProxyHeaders membuat klien diidentifikasi dari X-Forwarded-For (alamat pertama), untuk
+// server di belakang reverse proxy. Jangan diaktifkan jika server diakses langsung, karena
+// header itu bisa diisi bebas oleh klien.
+var TrustProxyHeaders bool
+
+// clientKey mengembalikan identitas klien untuk rate limiting: alamat IP-nya.
+func clientKey(r *http.Request) string {
+	if TrustProxyHeaders {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// admitSearch meminta izin Admission untuk pencarian target di graph, dan mencatat algorithm
+// sebagai label metrik request. Jika ditolak, respons 429 (rate limit klien) atau 503 (server
+// penuh) dengan Retry-After sudah ditulis. Jika diizinkan, release harus dipanggil setelah
+// pencarian selesai.
+func admitSearch(w http.ResponseWriter, r *http.Request, graph *loadrecipes.BiGraphAlchemy, algorithm, target string, maxPaths int) (func(), bool) {
+	setRequestAlgorithm(r, algorithm)
+	if Admission == nil {
+		return func() {}, true
+	}
+	cost := Admission.Cost(graph, algorithm, target, maxPaths)
+	release, err := Admission.Admit(r.Context(), clientKey(r), cost)
+	if err == nil {
+		return release, true
+	}
+
+	var rejected *admission.RejectedError
+	if !errors.As(err, &rejected) {
+		respondWithError(w, http.StatusInternalServerError, CodeInternal, err.Error(), nil)
+		return nil, false
+	}
+	retryAfter := int(math.Ceil(rejected.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	details := map[string]interface{}{"cost": math.Round(cost*100) / 100, "retryAfterSeconds": max(retryAfter, 1)}
+	if errors.Is(err, admission.ErrRateLimited) {
+		searchRejections.Inc(CodeRateLimited)
+		respondWithError(w, http.StatusTooManyRequests, CodeRateLimited, err.Error(), details)
+		return nil, false
+	}
+	searchRejections.Inc(CodeOverloaded)
+	respondWithError(w, http.StatusServiceUnavailable, CodeOverloaded, err.Error(), details)
+	return nil, false
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/admission"
+)
+
+// searchFrom menjalankan SearchHandler untuk Brick dari alamat remoteAddr.
+func searchFrom(remoteAddr string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/search?target=Brick", nil)
+	req.RemoteAddr = remoteAddr
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	SearchHandler(rec, req)
+	return rec
+}
+
+func TestSearchHandlerRateLimit(t *testing.T) {
+	useTestDatasets(t)
+	// Satu pencarian per klien; token baru baru ada setelah 1000 detik.
+	Admission = admission.New(admission.Options{Rate: 0.001, Burst: 1})
+
+	if rec := searchFrom("192.0.2.1:1000", nil); rec.Code != http.StatusOK {
+		t.Fatalf("request pertama: status = %d, want 200", rec.Code)
+	}
+
+	rec := searchFrom("192.0.2.1:2000", nil)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request kedua: status = %d, want 429", rec.Code)
+	}
+	body := decodeError(t, rec)
+	if body.Code != CodeRateLimited {
+		t.Errorf("code = %q, want %q", body.Code, CodeRateLimited)
+	}
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 {
+		t.Errorf("Retry-After = %q, want detik positif", rec.Header().Get("Retry-After"))
+	}
+	if body.Details["retryAfterSeconds"] != float64(retryAfter) {
+		t.Errorf("details.retryAfterSeconds = %v, want %d", body.Details["retryAfterSeconds"], retryAfter)
+	}
+
+	// Klien lain punya bucket sendiri.
+	if rec := searchFrom("192.0.2.2:1000", nil); rec.Code != http.StatusOK {
+		t.Errorf("klien lain: status = %d, want 200", rec.Code)
+	}
+
+	// X-Forwarded-For hanya dipakai jika TrustProxyHeaders aktif.
+	forwarded := http.Header{"X-Forwarded-For": {"198.51.100.7, 192.0.2.1"}}
+	if rec := searchFrom("192.0.2.1:3000", forwarded); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("X-Forwarded-For tanpa TrustProxyHeaders: status = %d, want 429", rec.Code)
+	}
+	TrustProxyHeaders = true
+	t.Cleanup(func() { TrustProxyHeaders = false })
+	if rec := searchFrom("192.0.2.1:3000", forwarded); rec.Code != http.StatusOK {
+		t.Errorf("X-Forwarded-For dengan TrustProxyHeaders: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestSearchHandlerOverloaded(t *testing.T) {
+	useTestDatasets(t)
+	Admission = admission.New(admission.Options{MaxConcurrent: 1, MaxQueue: 0})
+
+	release, err := Admission.Admit(context.Background(), "other", 1)
+	if err != nil {
+		t.Fatalf("Admit: %v", err)
+	}
+	rec := searchFrom("192.0.2.1:1000", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+	if body := decodeError(t, rec); body.Code != CodeOverloaded {
+		t.Errorf("code = %q, want %q", body.Code, CodeOverloaded)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("503 tanpa Retry-After")
+	}
+
+	release()
+	if rec := searchFrom("192.0.2.1:1000", nil); rec.Code != http.StatusOK {
+		t.Errorf("setelah slot dilepas: status = %d, want 200", rec.Code)
+	}
+}
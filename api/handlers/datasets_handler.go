@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+// Datasets adalah registry dataset yang dipakai semua handler.
+var Datasets = newDatasetRegistry()
+
+func newDatasetRegistry() *loadrecipes.Registry {
+	registry, err := loadrecipes.NewDefaultRegistry()
+	if err != nil {
+		log.Printf("[WARNING] Gagal memuat konfigurasi dataset tambahan: %v. Hanya dataset default yang tersedia.", err)
+	}
+	return registry
+}
+
+type DatasetsResponse struct {
+	Default  string                      `json:"default"`
+	Datasets []loadrecipes.DatasetConfig `json:"datasets"`
+}
+
+// loadGraph mengambil graf dataset dari registry dan menulis respons error jika gagal.
+func loadGraph(w http.ResponseWriter, dataset string) (*loadrecipes.BiGraphAlchemy, bool) {
+	graph, err := Datasets.Get(dataset)
+	if err != nil {
+		respondWithDatasetError(w, err)
+		return nil, false
+	}
+	return graph, true
+}
+
+// handling daftar dataset yang tersedia
+func DatasetsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(DatasetsResponse{
+		Default:  Datasets.DefaultName(),
+		Datasets: Datasets.Datasets(),
+	})
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// Kode error di level HTTP, melengkapi pathfinding.Code* untuk kegagalan pencarian.
+const (
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeUnknownDataset   = "unknown_dataset"
+	CodeNotFound         = "not_found"
+	CodeRateLimited      = "rate_limited"
+	CodeOverloaded       = "overloaded"
+	CodeNotReady         = "not_ready"
+	CodeInternal         = "internal"
+)
+
+// ErrorResponse adalah bentuk tunggal semua respons error API.
+type ErrorResponse struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// respondWithError menulis ErrorResponse dengan status code yang diberikan.
+func respondWithError(w http.ResponseWriter, statusCode int, code, message string, details map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Code:    code,
+		Message: message,
+		Details: details,
+	})
+}
+
+// respondMethodNotAllowed menolak request dengan method selain allowed.
+func respondMethodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	respondWithError(w, http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Method not allowed", nil)
+}
+
+// respondInvalidBody menolak body request yang bukan JSON valid.
+func respondInvalidBody(w http.ResponseWriter, err error) {
+	respondWithError(w, http.StatusBadRequest, pathfinding.CodeInvalidParam, "Invalid request body",
+		map[string]interface{}{"reason": err.Error()})
+}
+
+// respondInvalidParam menolak parameter request yang tidak valid.
+func respondInvalidParam(w http.ResponseWriter, param string, value interface{}, message string) {
+	respondWithError(w, http.StatusBadRequest, pathfinding.CodeInvalidParam, message,
+		map[string]interface{}{"param": param, "value": value})
+}
+
+// searchErrorStatus memetakan jenis error pencarian ke status HTTP.
+func searchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, pathfinding.ErrUnknownElement), errors.Is(err, pathfinding.ErrNoRecipe):
+		return http.StatusNotFound
+	case errors.Is(err, pathfinding.ErrInvalidParam):
+		return http.StatusBadRequest
+	case errors.Is(err, pathfinding.ErrBudgetExceeded):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// respondWithSearchError menulis error dari algoritma pencarian dengan status dan kode
+// sesuai jenisnya. Error yang tidak dikenal menjadi 500 "internal". Jika diagnosis tidak
+// nil, diagnosis disertakan di details.diagnosis.
+func respondWithSearchError(w http.ResponseWriter, err error, diagnosis *pathfinding.Diagnosis) {
+	code := pathfinding.ErrorCode(err)
+	if code == "" {
+		code = CodeInternal
+	}
+	details := make(map[string]interface{})
+	var searchErr *pathfinding.SearchError
+	if errors.As(err, &searchErr) {
+		for key, value := range searchErr.Details {
+			details[key] = value
+		}
+	}
+	if diagnosis != nil {
+		details["diagnosis"] = diagnosis
+	}
+	if len(details) == 0 {
+		details = nil
+	}
+	respondWithError(w, searchErrorStatus(err), code, err.Error(), details)
+}
+
+// respondWithDatasetError menulis error dari registry dataset: 404 untuk dataset yang tidak
+// terdaftar, 500 untuk kegagalan memuat graf.
+func respondWithDatasetError(w http.ResponseWriter, err error) {
+	var unknown *loadrecipes.UnknownDatasetError
+	if errors.As(err, &unknown) {
+		respondWithError(w, http.StatusNotFound, CodeUnknownDataset, err.Error(),
+			map[string]interface{}{"dataset": unknown.Name})
+		return
+	}
+	respondWithError(w, http.StatusInternalServerError, CodeInternal, "Failed to load graph", nil)
+}
+
+// NotFoundHandler menjawab route yang tidak terdaftar dengan ErrorResponse.
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithError(w, http.StatusNotFound, CodeNotFound, "route tidak ditemukan",
+		map[string]interface{}{"path": r.URL.Path})
+}
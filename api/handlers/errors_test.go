@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// decodeError memastikan rec berisi ErrorResponse JSON dan mengembalikannya.
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) ErrorResponse {
+	t.Helper()
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	var body ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("body bukan ErrorResponse: %v", err)
+	}
+	if body.Code == "" || body.Message == "" {
+		t.Errorf("ErrorResponse tanpa code/message: %+v", body)
+	}
+	return body
+}
+
+func TestSearchHandlerErrorEnvelope(t *testing.T) {
+	useTestDatasets(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		query       string
+		wantStatus  int
+		wantCode    string
+		wantDetails map[string]interface{}
+	}{
+		{name: "elemen tidak dikenal", query: "target=Unobtainium", wantStatus: http.StatusNotFound, wantCode: pathfinding.CodeUnknownElement},
+		{name: "tanpa resep", query: "target=Lonely", wantStatus: http.StatusNotFound, wantCode: pathfinding.CodeNoRecipe},
+		{name: "maxPaths tidak valid", query: "target=Brick&maxPaths=0", wantStatus: http.StatusBadRequest, wantCode: pathfinding.CodeInvalidParam, wantDetails: map[string]interface{}{"param": "maxPaths"}},
+		{name: "parameter tidak dikenal", query: "target=Brick&depth=2", wantStatus: http.StatusBadRequest, wantCode: pathfinding.CodeInvalidParam},
+		{name: "pack tidak dikenal", query: "target=Brick&packs=Nope", wantStatus: http.StatusBadRequest, wantCode: pathfinding.CodeInvalidParam, wantDetails: map[string]interface{}{"param": "packs"}},
+		{name: "dataset tidak dikenal", query: "target=Brick&dataset=nope", wantStatus: http.StatusNotFound, wantCode: CodeUnknownDataset, wantDetails: map[string]interface{}{"dataset": "nope"}},
+		{name: "method salah", method: http.MethodPost, query: "target=Brick", wantStatus: http.StatusMethodNotAllowed, wantCode: CodeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			rec := httptest.NewRecorder()
+			SearchHandler(rec, httptest.NewRequest(method, "/api/search?"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := decodeError(t, rec)
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q (%s)", body.Code, tt.wantCode, body.Message)
+			}
+			for key, want := range tt.wantDetails {
+				if body.Details[key] != want {
+					t.Errorf("details[%s] = %v, want %v", key, body.Details[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRespondWithSearchError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "unknown element", err: pathfinding.UnknownElementError("X"), wantStatus: http.StatusNotFound, wantCode: pathfinding.CodeUnknownElement},
+		{name: "no recipe", err: pathfinding.NoRecipeError("X", 3), wantStatus: http.StatusNotFound, wantCode: pathfinding.CodeNoRecipe},
+		{name: "invalid param", err: pathfinding.InvalidParamError("maxPaths", 0, "harus positif"), wantStatus: http.StatusBadRequest, wantCode: pathfinding.CodeInvalidParam},
+		{name: "budget exceeded", err: pathfinding.BudgetExceededError("X", 10, 11), wantStatus: http.StatusUnprocessableEntity, wantCode: pathfinding.CodeBudgetExceeded},
+		{name: "error lain", err: errors.New("rusak"), wantStatus: http.StatusInternalServerError, wantCode: CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			diagnosis := &pathfinding.Diagnosis{Element: "X", Reason: pathfinding.ReasonNoRecipes}
+			respondWithSearchError(rec, tt.err, diagnosis)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			body := decodeError(t, rec)
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", body.Code, tt.wantCode)
+			}
+			if _, ok := body.Details["diagnosis"]; !ok {
+				t.Errorf("details tanpa diagnosis: %+v", body.Details)
+			}
+		})
+	}
+}
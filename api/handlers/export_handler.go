@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+// exportContentTypes memetakan format export ke Content-Type dan ekstensi file unduhan.
+var exportContentTypes = map[string][2]string{
+	loadrecipes.ExportGraphML:  {"application/graphml+xml", "graphml"},
+	loadrecipes.ExportDOT:      {"text/vnd.graphviz", "dot"},
+	loadrecipes.ExportCSVNodes: {"text/csv", "nodes.csv"},
+	loadrecipes.ExportCSVEdges: {"text/csv", "edges.csv"},
+}
+
+// handling export seluruh graf resep (?format=graphml|dot|csv-nodes|csv-edges&dataset=...)
+func ExportGraphHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = loadrecipes.ExportGraphML
+	}
+	contentType, known := exportContentTypes[format]
+	if !known {
+		respondInvalidParam(w, "format", format, (&loadrecipes.UnknownExportFormatError{Format: format}).Error())
+		return
+	}
+
+	graph, ok := loadGraph(w, r.URL.Query().Get("dataset"))
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType[0])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"alchemy-%s.%s\"", graph.Version, contentType[1]))
+	if err := loadrecipes.WriteExport(w, graph, format); err != nil {
+		log.Printf("[WARNING] Gagal menulis export %s: %v", format, err)
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+func TestExportGraphHandlerRoundTrip(t *testing.T) {
+	useTestDatasets(t)
+	graph, err := Datasets.Get("test")
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+
+	tests := []struct {
+		query           string
+		wantType        string
+		wantDisposition string
+	}{
+		{query: "", wantType: "application/graphml+xml", wantDisposition: "graphml"},
+		{query: "?format=graphml&dataset=test", wantType: "application/graphml+xml", wantDisposition: "graphml"},
+		{query: "?format=csv-edges", wantType: "text/csv", wantDisposition: "edges.csv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ExportGraphHandler(rec, httptest.NewRequest(http.MethodGet, "/api/export"+tt.query, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			wantDisposition := `attachment; filename="alchemy-` + graph.Version + "." + tt.wantDisposition + `"`
+			if got := rec.Header().Get("Content-Disposition"); got != wantDisposition {
+				t.Errorf("Content-Disposition = %q, want %q", got, wantDisposition)
+			}
+
+			if tt.wantDisposition == "edges.csv" {
+				if got := recipesFromCSVEdges(t, rec.Body.Bytes()); !reflect.DeepEqual(got, graph.ChildToParents) {
+					t.Errorf("resep dari CSV = %v, want %v", got, graph.ChildToParents)
+				}
+				return
+			}
+			got, err := loadrecipes.ReadGraphML(rec.Body)
+			if err != nil {
+				t.Fatalf("ReadGraphML: %v", err)
+			}
+			if got.Version != graph.Version {
+				t.Errorf("Version = %q, want %q", got.Version, graph.Version)
+			}
+			if !reflect.DeepEqual(got.AllElements, graph.AllElements) || !reflect.DeepEqual(got.BaseElements, graph.BaseElements) {
+				t.Errorf("elemen = %v (dasar %v), want %v (dasar %v)", got.AllElements, got.BaseElements, graph.AllElements, graph.BaseElements)
+			}
+			for name := range graph.AllElements {
+				if got.Tiers[name] != graph.Tiers[name] {
+					t.Errorf("Tiers[%s] = %d, want %d", name, got.Tiers[name], graph.Tiers[name])
+				}
+			}
+			if !reflect.DeepEqual(got.ChildToParents, graph.ChildToParents) {
+				t.Errorf("ChildToParents = %v, want %v", got.ChildToParents, graph.ChildToParents)
+			}
+		})
+	}
+}
+
+// recipesFromCSVEdges menyusun child -> pasangan parent dari output csv-edges.
+func recipesFromCSVEdges(t *testing.T, body []byte) map[string][]loadrecipes.PairMats {
+	t.Helper()
+	rows, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if want := []string{"Source", "Target", "Role", "Type"}; !reflect.DeepEqual(rows[0], want) {
+		t.Fatalf("header = %v, want %v", rows[0], want)
+	}
+	recipes := make(map[string][]loadrecipes.PairMats)
+	var parents []string
+	for _, row := range rows[1:] {
+		if row[2] == "ingredient" {
+			parents = append(parents, row[0])
+			continue
+		}
+		if len(parents) != 2 {
+			t.Fatalf("resep %s memiliki %d bahan, want 2", row[0], len(parents))
+		}
+		recipes[row[1]] = append(recipes[row[1]], loadrecipes.ConstructPair(parents[0], parents[1]))
+		parents = nil
+	}
+	return recipes
+}
+
+func TestExportGraphHandlerRejectsRequest(t *testing.T) {
+	useTestDatasets(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "format tidak dikenal", method: http.MethodGet, query: "?format=pdf", wantStatus: http.StatusBadRequest, wantCode: pathfinding.CodeInvalidParam},
+		{name: "dataset tidak dikenal", method: http.MethodGet, query: "?dataset=missing", wantStatus: http.StatusNotFound, wantCode: CodeUnknownDataset},
+		{name: "method POST", method: http.MethodPost, wantStatus: http.StatusMethodNotAllowed, wantCode: CodeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ExportGraphHandler(rec, httptest.NewRequest(tt.method, "/api/export"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if body := decodeError(t, rec); body.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", body.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+// testDatasetJSON adalah dataset kecil untuk test handler: Brick butuh dua langkah, Lonely
+// tidak punya resep.
+const testDatasetJSON = `[
+	{"name": "Air", "recipes": [], "tier": 0},
+	{"name": "Earth", "recipes": [], "tier": 0},
+	{"name": "Fire", "recipes": [], "tier": 0},
+	{"name": "Water", "recipes": [], "tier": 0},
+	{"name": "Mud", "recipes": [["Earth", "Water"]], "tier": 1},
+	{"name": "Steam", "recipes": [["Fire", "Water"]], "tier": 1},
+	{"name": "Brick", "recipes": [["Mud", "Fire"]], "tier": 2},
+	{"name": "Lonely", "recipes": [], "tier": 3}
+]`
+
+// useTestDatasets mengganti Datasets dengan registry berisi testDatasetJSON dua kali: sebagai
+// "test" (default) dan "copy", isi yang sama dengan nama berbeda. Admission dimatikan.
+func useTestDatasets(t *testing.T) {
+	t.Helper()
+	fsys := fstest.MapFS{"elements.json": {Data: []byte(testDatasetJSON)}}
+	registry := loadrecipes.NewRegistry("test")
+	registry.Register(loadrecipes.DatasetConfig{Name: "test", Path: "elements.json", FS: fsys})
+	registry.Register(loadrecipes.DatasetConfig{Name: "copy", Path: "elements.json", FS: fsys})
+
+	previousDatasets, previousAdmission := Datasets, Admission
+	Datasets, Admission = registry, nil
+	t.Cleanup(func() {
+		Datasets, Admission = previousDatasets, previousAdmission
+	})
+}
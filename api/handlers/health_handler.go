@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+)
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+type ReadinessResponse struct {
+	Status         string `json:"status"`
+	Dataset        string `json:"dataset"`
+	DatasetVersion string `json:"datasetVersion"`
+	Elements       int    `json:"elements"`
+	Recipes        int    `json:"recipes"`
+}
+
+type VersionResponse struct {
+	Module    string `json:"module"`
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	// Revision, RevisionTime, dan Modified diisi dari info VCS saat build (kosong jika build
+	// dilakukan tanpa git, mis. dari source snapshot).
+	Revision       string `json:"revision,omitempty"`
+	RevisionTime   string `json:"revisionTime,omitempty"`
+	Modified       bool   `json:"modified"`
+	Dataset        string `json:"dataset"`
+	DatasetVersion string `json:"datasetVersion,omitempty"`
+}
+
+// handling liveness check: proses hidup dan bisa menjawab request
+func HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		respondMethodNotAllowed(w, "GET, HEAD")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(HealthResponse{Status: "ok"})
+}
+
+// handling readiness check: dataset default bisa dimuat dan grafnya konsisten. Jika tidak,
+// 503 supaya load balancer atau orchestrator tidak mengirim traffic ke instance ini.
+func ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		respondMethodNotAllowed(w, "GET, HEAD")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	dataset := Datasets.DefaultName()
+	graph, err := Datasets.Get(dataset)
+	if err == nil {
+		err = graph.CheckLoaded()
+	}
+	if err != nil {
+		respondWithError(w, http.StatusServiceUnavailable, CodeNotReady, "dataset default belum siap",
+			map[string]interface{}{"dataset": dataset, "reason": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReadinessResponse{
+		Status:         "ready",
+		Dataset:        dataset,
+		DatasetVersion: graph.Version,
+		Elements:       len(graph.AllElements),
+		Recipes:        graph.RecipeCount(),
+	})
+}
+
+// handling info build server dan versi dataset default
+func VersionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		respondMethodNotAllowed(w, "GET, HEAD")
+		return
+	}
+
+	response := VersionResponse{Dataset: Datasets.DefaultName()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		response.Module = info.Main.Path
+		response.Version = info.Main.Version
+		response.GoVersion = info.GoVersion
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				response.Revision = setting.Value
+			case "vcs.time":
+				response.RevisionTime = setting.Value
+			case "vcs.modified":
+				response.Modified = setting.Value == "true"
+			}
+		}
+	}
+	// Versi dataset hanya diisi jika dataset bisa dimuat; kegagalannya dilaporkan /readyz.
+	if graph, err := Datasets.Get(response.Dataset); err == nil {
+		response.DatasetVersion = graph.Version
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+func TestHealthzHandler(t *testing.T) {
+	tests := []struct {
+		method     string
+		wantStatus int
+	}{
+		{method: http.MethodGet, wantStatus: http.StatusOK},
+		{method: http.MethodHead, wantStatus: http.StatusOK},
+		{method: http.MethodPost, wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		HealthzHandler(rec, httptest.NewRequest(tt.method, "/healthz", nil))
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.method, rec.Code, tt.wantStatus)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	HealthzHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var body HealthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Status != "ok" {
+		t.Errorf("body = %+v, %v; want status ok", body, err)
+	}
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", rec.Header().Get("Cache-Control"))
+	}
+}
+
+func TestReadyzHandler(t *testing.T) {
+	useTestDatasets(t)
+
+	rec := httptest.NewRecorder()
+	ReadyzHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body ReadinessResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	// testDatasetJSON: 8 elemen, 3 resep.
+	if body.Status != "ready" || body.Dataset != "test" || body.DatasetVersion == "" || body.Elements != 8 || body.Recipes != 3 {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestReadyzHandlerNotReady(t *testing.T) {
+	tests := []struct {
+		name   string
+		config loadrecipes.DatasetConfig
+	}{
+		{name: "file tidak ada", config: loadrecipes.DatasetConfig{Name: "broken", Path: "missing.json", FS: fstest.MapFS{}}},
+		{name: "JSON rusak", config: loadrecipes.DatasetConfig{Name: "broken", Path: "elements.json", FS: fstest.MapFS{"elements.json": {Data: []byte("[{")}}}},
+		{name: "tanpa elemen", config: loadrecipes.DatasetConfig{Name: "broken", Path: "elements.json", BaseElements: []string{"Air"}, FS: fstest.MapFS{"elements.json": {Data: []byte("[]")}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestDatasets(t)
+			Datasets = loadrecipes.NewRegistry("broken")
+			Datasets.Register(tt.config)
+
+			rec := httptest.NewRecorder()
+			ReadyzHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Fatalf("status = %d, want 503", rec.Code)
+			}
+			body := decodeError(t, rec)
+			if body.Code != CodeNotReady || body.Details["dataset"] != "broken" || body.Details["reason"] == "" {
+				t.Errorf("body = %+v", body)
+			}
+		})
+	}
+}
+
+func TestVersionHandler(t *testing.T) {
+	useTestDatasets(t)
+
+	rec := httptest.NewRecorder()
+	VersionHandler(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	var body VersionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Dataset != "test" || body.DatasetVersion == "" || body.GoVersion == "" {
+		t.Errorf("body = %+v", body)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"runtime"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/metrics"
+)
+
+// RequestInfo berisi label metrik yang baru diketahui di dalam handler, seperti algoritma
+// pencarian. Middleware metrik memasangnya di context lewat WithRequestInfo lalu membacanya
+// setelah handler selesai.
+type RequestInfo struct {
+	Algorithm string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo memasang RequestInfo kosong di ctx.
+func WithRequestInfo(ctx context.Context) (context.Context, *RequestInfo) {
+	info := &RequestInfo{}
+	return context.WithValue(ctx, requestInfoKey{}, info), info
+}
+
+// setRequestAlgorithm mencatat algoritma pencarian request untuk label metrik.
+func setRequestAlgorithm(r *http.Request, algorithm string) {
+	if info, ok := r.Context().Value(requestInfoKey{}).(*RequestInfo); ok {
+		info.Algorithm = algorithm
+	}
+}
+
+var searchRejections = metrics.Default.NewCounterVec("alchemy_search_rejections_total",
+	"Jumlah pencarian yang ditolak admission control, per kode error.", "code")
+
+func init() {
+	metrics.Default.NewGaugeFunc("go_goroutines", "Jumlah goroutine yang sedang ada.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+	metrics.Default.NewGaugeFunc("alchemy_searches_running", "Jumlah pencarian yang sedang berjalan.", func() float64 {
+		if Admission == nil {
+			return 0
+		}
+		running, _ := Admission.InFlight()
+		return float64(running)
+	})
+	metrics.Default.NewGaugeFunc("alchemy_searches_queued", "Jumlah pencarian yang menunggu slot.", func() float64 {
+		if Admission == nil {
+			return 0
+		}
+		_, queued := Admission.InFlight()
+		return float64(queued)
+	})
+}
+
+// handling metrik dalam format teks Prometheus
+func MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	if err := metrics.Default.WriteText(w); err != nil {
+		log.Printf("[WARNING] Gagal menulis metrik: %v", err)
+	}
+}
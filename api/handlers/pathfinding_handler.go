@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
@@ -9,22 +11,45 @@ import (
 	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
 	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/bfs"
 	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/dfs"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/search"
+	"github.com/Starath/Tubes2_BE_SayMyName/render"
 )
 
 type BFSRequest struct {
 	TargetElementName string `json:"targetElementName"`
 	MaxPaths          int    `json:"maxPaths"`
+	Diagnostics       bool   `json:"diagnostics"`
+	Dataset           string `json:"dataset"`
+	// Format respons: "json" (default), "dot", "mermaid", "guide", atau "guide-text".
+	// Bisa juga lewat ?format=. Inventory (atau ?inventory=a,b) menandai elemen yang sudah
+	// dimiliki di format guide.
+	Format    string   `json:"format"`
+	Inventory []string `json:"inventory"`
+	// BaseGameOnly membatasi pencarian ke elemen base game; Packs menambahkan expansion
+	// pack yang dimiliki pemain. Jika keduanya kosong, semua elemen dipakai.
+	BaseGameOnly bool     `json:"baseGameOnly"`
+	Packs        []string `json:"packs"`
 }
 
 type BFSResponse struct {
 	Results       *pathfinding.MultipleResult `json:"results"`
-	Error         string                      `json:"error,omitempty"`
 	ExecutionTime float64                     `json:"executionTimeMs"`
 }
 
 type DFSRequest struct {
 	TargetElementName string `json:"targetElementName"`
 	MaxPaths          int    `json:"maxPaths"`
+	Diagnostics       bool   `json:"diagnostics"`
+	Dataset           string `json:"dataset"`
+	// Format respons: "json" (default), "dot", "mermaid", "guide", atau "guide-text".
+	// Bisa juga lewat ?format=. Inventory (atau ?inventory=a,b) menandai elemen yang sudah
+	// dimiliki di format guide.
+	Format    string   `json:"format"`
+	Inventory []string `json:"inventory"`
+	// BaseGameOnly membatasi pencarian ke elemen base game; Packs menambahkan expansion
+	// pack yang dimiliki pemain. Jika keduanya kosong, semua elemen dipakai.
+	BaseGameOnly bool     `json:"baseGameOnly"`
+	Packs        []string `json:"packs"`
 }
 
 type DFSSingleResponse struct {
@@ -40,42 +65,54 @@ type DFSMultipleResponse struct {
 
 // handling dfs single recipee
 func DFSPathfindingHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
 	// Method harus POST
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondMethodNotAllowed(w, http.MethodPost)
 		return
 	}
 
 	var req DFSRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		respondInvalidBody(w, err)
 		return
 	}
 
-	graph, err := loadrecipes.LoadBiGraph("elements_filtered.json")
-	if err != nil {
-		respondWithError(w, "Failed to load graph", http.StatusInternalServerError)
+	format, formatOK := requestFormat(r, req.Format)
+	if !formatOK {
+		respondInvalidParam(w, "format", format, (&render.UnknownFormatError{Format: format}).Error())
+		return
+	}
+
+	fullGraph, ok := loadGraph(w, req.Dataset)
+	if !ok {
+		return
+	}
+	graph, ok := restrictToPacks(w, fullGraph, req.BaseGameOnly, req.Packs)
+	if !ok {
 		return
 	}
 
+	release, admitted := admitSearch(w, r, graph, search.AlgorithmDFS, req.TargetElementName, 1)
+	if !admitted {
+		return
+	}
+	defer release()
+
 	start := time.Now()
 	result, err := dfs.DFSFindPathString(graph, req.TargetElementName)
 
 	if err != nil {
-		respondWithError(w, "Failed to find paths: "+err.Error(), http.StatusInternalServerError)
+		respondWithSearchFailure(w, fullGraph, graph, req.TargetElementName, req.Diagnostics, err)
 		return
 	}
 
 	executionTime := time.Since(start).Seconds() * 1000
 
+	if format != FormatJSON {
+		writeResultsAs(w, r, format, req.TargetElementName, req.Inventory, []pathfinding.Result{*result})
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(DFSSingleResponse{
 		Results:       result,
@@ -85,37 +122,48 @@ func DFSPathfindingHandler(w http.ResponseWriter, r *http.Request) {
 
 // handling dfs multi recipis
 func DFSMultiplePathfindingHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
 
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
+	var req DFSRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
 		return
 	}
 
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	format, formatOK := requestFormat(r, req.Format)
+	if !formatOK {
+		respondInvalidParam(w, "format", format, (&render.UnknownFormatError{Format: format}).Error())
 		return
 	}
 
-	var req DFSRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	fullGraph, ok := loadGraph(w, req.Dataset)
+	if !ok {
+		return
+	}
+	graph, ok := restrictToPacks(w, fullGraph, req.BaseGameOnly, req.Packs)
+	if !ok {
 		return
 	}
 
-	graph, err := loadrecipes.LoadBiGraph("elements_filtered.json")
-	if err != nil {
-		respondWithError(w, "Failed to load graph", http.StatusInternalServerError)
+	release, admitted := admitSearch(w, r, graph, search.AlgorithmDFS, req.TargetElementName, req.MaxPaths)
+	if !admitted {
 		return
 	}
+	defer release()
 
 	start := time.Now()
 	result, nodesVisited, err := dfs.DFSFindMultiplePaths(graph, req.TargetElementName, req.MaxPaths)
 	executionTime := time.Since(start).Seconds() * 1000
 	if err != nil {
-		respondWithError(w, "Failed to find paths: "+err.Error(), http.StatusInternalServerError)
+		respondWithSearchFailure(w, fullGraph, graph, req.TargetElementName, req.Diagnostics, err)
+		return
+	}
+
+	if format != FormatJSON {
+		writeResultsAs(w, r, format, req.TargetElementName, req.Inventory, result.Results)
 		return
 	}
 
@@ -129,53 +177,147 @@ func DFSMultiplePathfindingHandler(w http.ResponseWriter, r *http.Request) {
 
 // bfs handler
 func BFSPathfindingHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
 
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
+	var req BFSRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
 		return
 	}
 
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	format, formatOK := requestFormat(r, req.Format)
+	if !formatOK {
+		respondInvalidParam(w, "format", format, (&render.UnknownFormatError{Format: format}).Error())
 		return
 	}
 
-	var req BFSRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	fullGraph, ok := loadGraph(w, req.Dataset)
+	if !ok {
+		return
+	}
+	graph, ok := restrictToPacks(w, fullGraph, req.BaseGameOnly, req.Packs)
+	if !ok {
 		return
 	}
 
-	graph, err := loadrecipes.LoadBiGraph("elements_filtered.json")
-	if err != nil {
-		respondWithError(w, "Failed to load graph", http.StatusInternalServerError)
+	release, admitted := admitSearch(w, r, graph, search.AlgorithmBFS, req.TargetElementName, req.MaxPaths)
+	if !admitted {
 		return
 	}
+	defer release()
 
 	start := time.Now()
 	result, err := bfs.BFSFindMultiplePaths(graph, req.TargetElementName, req.MaxPaths)
 	executionTime := time.Since(start).Seconds() * 1000
 
 	if err != nil {
-		respondWithError(w, "Failed to find paths: "+err.Error(), http.StatusInternalServerError)
+		respondWithSearchFailure(w, fullGraph, graph, req.TargetElementName, req.Diagnostics, err)
+		return
+	}
+
+	if format != FormatJSON {
+		writeResultsAs(w, r, format, req.TargetElementName, req.Inventory, result.Results)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(BFSResponse{
 		Results:       result,
-		Error:         "",
 		ExecutionTime: float64(executionTime),
 	})
 }
 
-func respondWithError(w http.ResponseWriter, errorMsg string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(BFSResponse{
-		Error: errorMsg,
-	})
+// restrictToPacks mengembalikan graf yang dibatasi ke base game + packs jika diminta.
+// Jika ada pack yang tidak dikenal, respons 400 sudah ditulis dan ok = false.
+func restrictToPacks(w http.ResponseWriter, graph *loadrecipes.BiGraphAlchemy, baseGameOnly bool, packs []string) (*loadrecipes.BiGraphAlchemy, bool) {
+	if !baseGameOnly && len(packs) == 0 {
+		return graph, true
+	}
+	view, err := graph.RestrictToPacks(packs)
+	var unknown *loadrecipes.UnknownPackError
+	if errors.As(err, &unknown) {
+		respondWithSearchError(w, pathfinding.InvalidParamError("packs", unknown.Pack, "berisi "+err.Error()), nil)
+		return nil, false
+	}
+	if err != nil {
+		respondWithSearchError(w, err, nil)
+		return nil, false
+	}
+	return view, true
+}
+
+// respondWithSearchFailure sama dengan respondWithSearchError, tapi menyertakan diagnosis
+// jika klien meminta mode diagnostics.
+func respondWithSearchFailure(w http.ResponseWriter, fullGraph, graph *loadrecipes.BiGraphAlchemy, target string, withDiagnostics bool, err error) {
+	var diagnosis *pathfinding.Diagnosis
+	if withDiagnostics && !errors.Is(err, pathfinding.ErrInvalidParam) {
+		diagnosis = diagnoseFailure(fullGraph, graph, target)
+	}
+	respondWithSearchError(w, err, diagnosis)
+}
+
+// diagnoseFailure menjelaskan kenapa pencarian gagal. graph adalah graf yang dipakai
+// pencarian (mungkin sudah dibatasi pack), fullGraph adalah dataset lengkapnya.
+// Jika elemen bisa dibuat di graph, berarti pencarian berhenti karena batas iterasi/budget;
+// jika hanya bisa dibuat di fullGraph, berarti elemen tersingkir oleh batasan pack.
+func diagnoseFailure(fullGraph, graph *loadrecipes.BiGraphAlchemy, target string) *pathfinding.Diagnosis {
+	diagnosis, err := dfs.DiagnoseElement(graph, target)
+	if err != nil {
+		return &pathfinding.Diagnosis{Element: target, Message: err.Error()}
+	}
+	if diagnosis.Craftable {
+		diagnosis.Reason = pathfinding.ReasonBudgetExceeded
+		diagnosis.Message = "elemen '" + target + "' dapat dibuat, tetapi pencarian berhenti karena batas iterasi atau parameter pencarian"
+		return diagnosis
+	}
+	if fullGraph != graph {
+		if full, err := dfs.DiagnoseElement(fullGraph, target); err == nil && full.Craftable {
+			diagnosis.Reason = pathfinding.ReasonExcludedByFilter
+			diagnosis.Message = "elemen '" + target + "' hanya bisa dibuat dengan elemen dari expansion pack yang tidak dipilih"
+			if pack := fullGraph.ElementPack(target); pack != loadrecipes.BaseGamePack {
+				diagnosis.Message = "elemen '" + target + "' termasuk expansion pack '" + pack + "' yang tidak dipilih"
+			}
+		}
+	}
+	return diagnosis
+}
+
+// FormatJSON adalah format respons default endpoint pencarian.
+const FormatJSON = "json"
+
+// requestFormat mengambil format respons dari ?format= (diutamakan) atau field "format" di
+// body. Mengembalikan false jika format tidak didukung.
+func requestFormat(r *http.Request, bodyFormat string) (string, bool) {
+	format := bodyFormat
+	if value := r.URL.Query().Get("format"); value != "" {
+		format = value
+	}
+	return parseFormat(format)
+}
+
+// parseFormat menormalkan nama format respons ("" berarti JSON) dan mengembalikan false jika
+// format tidak didukung.
+func parseFormat(format string) (string, bool) {
+	if format == "" || format == FormatJSON {
+		return FormatJSON, true
+	}
+	return format, loadrecipes.ContainsString(render.Formats, format)
+}
+
+// writeResultsAs menulis resep-resep target dalam format teks (DOT, Mermaid, guide) sebagai
+// respons. ?inventory=a,b ditambahkan ke inventory dari body.
+func writeResultsAs(w http.ResponseWriter, r *http.Request, format, target string, inventory []string, results []pathfinding.Result) {
+	inventory = append(inventory, splitList(r.URL.Query().Get("inventory"))...)
+	w.Header().Set("Content-Type", render.ContentTypes[format])
+	if err := render.WriteResults(w, format, target, results, render.Options{Inventory: inventory}); err != nil {
+		log.Printf("[WARNING] Gagal menulis hasil pencarian sebagai %s: %v", format, err)
+		// Resep dicek sebelum apa pun ditulis, jadi respons error masih bisa dikirim.
+		var conflict *render.ConflictingStepError
+		if errors.As(err, &conflict) {
+			respondWithError(w, http.StatusInternalServerError, CodeInternal, err.Error(), nil)
+		}
+	}
 }
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/search"
+	"github.com/Starath/Tubes2_BE_SayMyName/render"
+)
+
+// RenderPathPrefix adalah prefix route RenderRecipeHandler: /api/render/{element}.svg
+const RenderPathPrefix = "/api/render/"
+
+// Icons berisi ikon elemen ("<Nama Elemen>.svg") yang di-embed ke SVG hasil render.
+var Icons fs.FS = os.DirFS("img")
+
+// handling render resep sebagai SVG (/api/render/{element}.svg?algorithm=bfs&index=0&dataset=...)
+func RenderRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	target, isSVG := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, RenderPathPrefix), ".svg")
+	if !isSVG || target == "" || strings.Contains(target, "/") {
+		respondWithError(w, http.StatusNotFound, CodeNotFound, "path harus berbentuk "+RenderPathPrefix+"{element}.svg",
+			map[string]interface{}{"path": r.URL.Path})
+		return
+	}
+
+	query := r.URL.Query()
+	algorithm := query.Get("algorithm")
+	if algorithm == "" {
+		algorithm = search.AlgorithmBFS
+	}
+	if !loadrecipes.ContainsString(search.Algorithms, algorithm) {
+		respondInvalidParam(w, "algorithm", algorithm, (&search.UnknownAlgorithmError{Algorithm: algorithm}).Error())
+		return
+	}
+	index := 0
+	if value := query.Get("index"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		// index+1 menjadi maxPaths pencarian, jadi batasnya sama dengan maxPaths /api/search.
+		if err != nil || parsed < 0 || parsed >= MaxSearchMaxPaths {
+			respondInvalidParam(w, "index", value, fmt.Sprintf("index harus integer 0..%d", MaxSearchMaxPaths-1))
+			return
+		}
+		index = parsed
+	}
+
+	graph, ok := loadGraph(w, query.Get("dataset"))
+	if !ok {
+		return
+	}
+	release, admitted := admitSearch(w, r, graph, algorithm, target, index+1)
+	if !admitted {
+		return
+	}
+	results, err := search.Find(graph, algorithm, target, index+1)
+	release()
+	if err != nil {
+		respondWithSearchError(w, err, nil)
+		return
+	}
+	if index >= len(results) {
+		respondWithError(w, http.StatusNotFound, pathfinding.CodeNoRecipe,
+			fmt.Sprintf("resep ke-%d untuk '%s' tidak ditemukan (%d resep ditemukan)", index, target, len(results)),
+			map[string]interface{}{"element": target, "index": index, "found": len(results)})
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	if err := render.WriteSVG(w, target, results[index], render.SVGOptions{Icons: Icons}); err != nil {
+		log.Printf("[WARNING] Gagal menulis SVG untuk '%s': %v", target, err)
+		var conflict *render.ConflictingStepError
+		if errors.As(err, &conflict) {
+			respondWithError(w, http.StatusInternalServerError, CodeInternal, err.Error(), nil)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenderRecipeHandlerRejectsIndex(t *testing.T) {
+	for _, index := range []string{"-1", "abc", "100", "1000000000"} {
+		rec := httptest.NewRecorder()
+		RenderRecipeHandler(rec, httptest.NewRequest(http.MethodGet, RenderPathPrefix+"Steam.svg?index="+index, nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("index=%s: status = %d, want 400", index, rec.Code)
+			continue
+		}
+		var body ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("index=%s: body bukan ErrorResponse: %v", index, err)
+		}
+		if body.Code != "invalid_param" || body.Details["param"] != "index" {
+			t.Errorf("index=%s: error = %+v, want invalid_param untuk index", index, body)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/metrics"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/search"
+	"github.com/Starath/Tubes2_BE_SayMyName/render"
+)
+
+// Default dan batas parameter SearchHandler.
+const (
+	DefaultSearchMaxPaths = 1
+	MaxSearchMaxPaths     = 100
+)
+
+// SearchCacheMaxAge adalah max-age Cache-Control untuk hasil SearchHandler. URL tidak memuat
+// versi dataset, jadi setelah max-age klien memvalidasi ulang dengan ETag.
+var SearchCacheMaxAge = 5 * time.Minute
+
+// searchParams adalah parameter query SearchHandler yang sudah divalidasi.
+type searchParams struct {
+	Target       string
+	Algorithm    string
+	MaxPaths     int
+	Dataset      string
+	Format       string
+	Inventory    []string
+	BaseGameOnly bool
+	Packs        []string
+	Diagnostics  bool
+}
+
+// searchQueryParams adalah semua parameter query yang diterima SearchHandler.
+var searchQueryParams = []string{"target", "algorithm", "maxPaths", "dataset", "format", "inventory", "baseGameOnly", "packs", "diagnostics"}
+
+type SearchResponse struct {
+	Target         string               `json:"target"`
+	Algorithm      string               `json:"algorithm"`
+	MaxPaths       int                  `json:"maxPaths"`
+	DatasetVersion string               `json:"datasetVersion"`
+	Results        []pathfinding.Result `json:"results"`
+	ExecutionTime  float64              `json:"executionTimeMs"`
+}
+
+// handling pencarian lewat query string (/api/search?target=Human&algorithm=bfs&maxPaths=5)
+// supaya hasilnya bisa di-bookmark dan di-cache proxy.
+func SearchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		respondMethodNotAllowed(w, "GET, HEAD")
+		return
+	}
+
+	params, ok := parseSearchParams(w, r.URL.Query())
+	if !ok {
+		return
+	}
+
+	fullGraph, ok := loadGraph(w, params.Dataset)
+	if !ok {
+		return
+	}
+	graph, ok := restrictToPacks(w, fullGraph, params.BaseGameOnly, params.Packs)
+	if !ok {
+		return
+	}
+
+	// Hasil hanya bergantung pada versi graf (sudah memuat pack) dan parameter, jadi ETag bisa
+	// dicek sebelum pencarian dijalankan.
+	dataset := params.Dataset
+	if dataset == "" {
+		dataset = Datasets.DefaultName()
+	}
+	etag := searchETag(dataset, graph.Version, params)
+	revalidated := etagMatches(r.Header.Get("If-None-Match"), etag)
+	metrics.CacheLookup("search_etag", revalidated)
+	if revalidated {
+		setSearchCacheHeaders(w, etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	release, admitted := admitSearch(w, r, graph, params.Algorithm, params.Target, params.MaxPaths)
+	if !admitted {
+		return
+	}
+	defer release()
+
+	start := time.Now()
+	results, err := search.Find(graph, params.Algorithm, params.Target, params.MaxPaths)
+	executionTime := time.Since(start).Seconds() * 1000
+	if err != nil {
+		respondWithSearchFailure(w, fullGraph, graph, params.Target, params.Diagnostics, err)
+		return
+	}
+
+	setSearchCacheHeaders(w, etag)
+	if params.Format != FormatJSON {
+		// writeResultsAs sendiri membaca ?inventory=.
+		writeResultsAs(w, r, params.Format, params.Target, nil, results)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SearchResponse{
+		Target:         params.Target,
+		Algorithm:      params.Algorithm,
+		MaxPaths:       params.MaxPaths,
+		DatasetVersion: graph.Version,
+		Results:        results,
+		ExecutionTime:  float64(executionTime),
+	})
+}
+
+// parseSearchParams memvalidasi query SearchHandler dan mengisi default. Parameter yang tidak
+// dikenal atau ditulis lebih dari sekali ditolak, supaya salah ketik tidak diam-diam diabaikan.
+// Jika tidak valid, respons error sudah ditulis.
+func parseSearchParams(w http.ResponseWriter, query url.Values) (searchParams, bool) {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := query[name]
+		if !loadrecipes.ContainsString(searchQueryParams, name) {
+			respondWithError(w, http.StatusBadRequest, pathfinding.CodeInvalidParam,
+				fmt.Sprintf("parameter '%s' tidak dikenal", name),
+				map[string]interface{}{"param": name, "allowed": searchQueryParams})
+			return searchParams{}, false
+		}
+		if len(values) > 1 {
+			respondInvalidParam(w, name, values, fmt.Sprintf("parameter '%s' hanya boleh diisi sekali", name))
+			return searchParams{}, false
+		}
+	}
+
+	params := searchParams{
+		Target:    strings.TrimSpace(query.Get("target")),
+		Algorithm: query.Get("algorithm"),
+		MaxPaths:  DefaultSearchMaxPaths,
+		Dataset:   query.Get("dataset"),
+		Inventory: splitList(query.Get("inventory")),
+		Packs:     splitList(query.Get("packs")),
+	}
+	if params.Target == "" {
+		respondInvalidParam(w, "target", query.Get("target"), "parameter target wajib diisi")
+		return searchParams{}, false
+	}
+	if params.Algorithm == "" {
+		params.Algorithm = search.AlgorithmBFS
+	}
+	if !loadrecipes.ContainsString(search.Algorithms, params.Algorithm) {
+		respondInvalidParam(w, "algorithm", params.Algorithm, (&search.UnknownAlgorithmError{Algorithm: params.Algorithm}).Error())
+		return searchParams{}, false
+	}
+	if value, set := query["maxPaths"]; set {
+		maxPaths, err := strconv.Atoi(value[0])
+		if err != nil || maxPaths < 1 || maxPaths > MaxSearchMaxPaths {
+			respondInvalidParam(w, "maxPaths", value[0], fmt.Sprintf("maxPaths harus integer 1..%d", MaxSearchMaxPaths))
+			return searchParams{}, false
+		}
+		params.MaxPaths = maxPaths
+	}
+	format, formatOK := parseFormat(query.Get("format"))
+	if !formatOK {
+		respondInvalidParam(w, "format", format, (&render.UnknownFormatError{Format: format}).Error())
+		return searchParams{}, false
+	}
+	params.Format = format
+	for _, flag := range []struct {
+		name  string
+		value *bool
+	}{{"baseGameOnly", &params.BaseGameOnly}, {"diagnostics", &params.Diagnostics}} {
+		value, set := query[flag.name]
+		if !set {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value[0])
+		if err != nil {
+			respondInvalidParam(w, flag.name, value[0], fmt.Sprintf("parameter %s harus true atau false", flag.name))
+			return searchParams{}, false
+		}
+		*flag.value = parsed
+	}
+	return params, true
+}
+
+// splitList memecah daftar dipisah koma, membuang spasi dan item kosong.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// searchETag membuat ETag dari nama dataset, versi graf dan parameter yang sudah dinormalisasi,
+// sehingga ?target=Brick dan ?target=Brick&algorithm=bfs&maxPaths=1 berbagi cache. ETag-nya
+// weak: BFS paralel dan DFS multiple bisa mengembalikan urutan atau pilihan resep yang berbeda
+// untuk parameter yang sama, jadi respons hanya setara secara semantik, tidak identik per byte.
+func searchETag(dataset, version string, params searchParams) string {
+	canonical := url.Values{
+		"target":      {params.Target},
+		"algorithm":   {params.Algorithm},
+		"maxPaths":    {strconv.Itoa(params.MaxPaths)},
+		"format":      {params.Format},
+		"inventory":   {strings.Join(params.Inventory, ",")},
+		"diagnostics": {strconv.FormatBool(params.Diagnostics)},
+	}
+	sum := sha256.Sum256([]byte(dataset + "\n" + version + "\n" + canonical.Encode()))
+	return `W/"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// etagMatches mengecek apakah header If-None-Match memuat etag (atau "*").
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == strings.TrimPrefix(etag, "W/") || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func setSearchCacheHeaders(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(SearchCacheMaxAge.Seconds())))
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func getSearch(t *testing.T, query string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/search?"+query, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	SearchHandler(rec, req)
+	return rec
+}
+
+func TestSearchHandlerETag(t *testing.T) {
+	useTestDatasets(t)
+
+	first := getSearch(t, "target=Brick", nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", first.Code, first.Body)
+	}
+	etag := first.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("ETag = %q, want weak ETag", etag)
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "etag sama", query: "target=Brick", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "parameter default eksplisit", query: "target=Brick&algorithm=bfs&maxPaths=1", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "dataset default eksplisit", query: "target=Brick&dataset=test", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "tanpa prefix weak", query: "target=Brick", ifNoneMatch: strings.TrimPrefix(etag, "W/"), wantStatus: http.StatusNotModified},
+		{name: "daftar etag", query: "target=Brick", ifNoneMatch: `"lain", ` + etag, wantStatus: http.StatusNotModified},
+		{name: "wildcard", query: "target=Brick", ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "parameter berbeda", query: "target=Brick&maxPaths=2", ifNoneMatch: etag, wantStatus: http.StatusOK},
+		{name: "dataset lain dengan isi sama", query: "target=Brick&dataset=copy", ifNoneMatch: etag, wantStatus: http.StatusOK},
+		{name: "etag lain", query: "target=Brick", ifNoneMatch: `W/"lain"`, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getSearch(t, tt.query, http.Header{"If-None-Match": {tt.ifNoneMatch}})
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 berisi body: %q", rec.Body)
+			}
+			if rec.Header().Get("ETag") == "" || rec.Header().Get("Cache-Control") == "" {
+				t.Errorf("header cache tidak di-set: %v", rec.Header())
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/metrics"
+)
+
+type GraphStatsResponse struct {
+	Stats         *loadrecipes.GraphStats `json:"stats"`
+	Cached        bool                    `json:"cached"`
+	ExecutionTime float64                 `json:"executionTimeMs"`
+}
+
+// Statistik dihitung sekali per versi dataset.
+var (
+	graphStatsMutex  sync.Mutex
+	cachedGraphStats = make(map[string]*loadrecipes.GraphStats)
+)
+
+// handling statistik dataset untuk dashboard
+func GraphStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	graph, ok := loadGraph(w, r.URL.Query().Get("dataset"))
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	graphStatsMutex.Lock()
+	stats, cached := cachedGraphStats[graph.Version]
+	metrics.CacheLookup("graph_stats", cached)
+	if !cached {
+		stats = loadrecipes.ComputeGraphStats(graph, loadrecipes.DefaultStatsTopN)
+		cachedGraphStats[graph.Version] = stats
+	}
+	graphStatsMutex.Unlock()
+	executionTime := time.Since(start).Seconds() * 1000
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GraphStatsResponse{
+		Stats:         stats,
+		Cached:        cached,
+		ExecutionTime: float64(executionTime),
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// VerifyRequest menerima resep dalam bentuk daftar step atau tree bertingkat.
+// Jika Tree diisi, Steps diabaikan.
+type VerifyRequest struct {
+	TargetElementName string                  `json:"targetElementName"`
+	Steps             []pathfinding.PathStep  `json:"steps"`
+	Tree              *pathfinding.RecipeNode `json:"tree"`
+	Dataset           string                  `json:"dataset"`
+}
+
+type VerifyResponse struct {
+	Result        *pathfinding.VerificationResult `json:"result"`
+	ExecutionTime float64                         `json:"executionTimeMs"`
+}
+
+// handling verifikasi resep buatan pengguna
+func VerifyRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req VerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	graph, ok := loadGraph(w, req.Dataset)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	steps := req.Steps
+	var treeIssues []pathfinding.StepIssue
+	target := req.TargetElementName
+	if req.Tree != nil {
+		steps, treeIssues = pathfinding.FlattenRecipeTree(req.Tree)
+		if target == "" {
+			target = req.Tree.Name
+		}
+	}
+
+	result := pathfinding.VerifyRecipe(graph, target, steps)
+	if len(treeIssues) > 0 {
+		result.Issues = append(treeIssues, result.Issues...)
+		result.Valid = false
+		result.RecipeID = ""
+	}
+	executionTime := time.Since(start).Seconds() * 1000
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(VerifyResponse{
+		Result:        result,
+		ExecutionTime: float64(executionTime),
+	})
+}
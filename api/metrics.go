@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/api/handlers"
+	"github.com/Starath/Tubes2_BE_SayMyName/metrics"
+)
+
+// Metrik HTTP per route (pola yang didaftarkan di SetupRouter, bukan path mentah, supaya
+// /api/render/{element}.svg tidak membuat satu series per elemen) dan algoritma.
+var (
+	httpRequests = metrics.Default.NewCounterVec("alchemy_http_requests_total",
+		"Jumlah request HTTP per endpoint, algoritma, dan status code.", "endpoint", "algorithm", "status")
+	httpRequestDuration = metrics.Default.NewHistogramVec("alchemy_http_request_duration_seconds",
+		"Latency request HTTP per endpoint dan algoritma, dalam detik.", nil, "endpoint", "algorithm")
+)
+
+// instrument membungkus handler route endpoint dengan metrik request dan latency. Label
+// algorithm diisi handler pencarian lewat handlers.RequestInfo, atau "none".
+func instrument(endpoint string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx, info := handlers.WithRequestInfo(r.Context())
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		algorithm := info.Algorithm
+		if algorithm == "" {
+			algorithm = "none"
+		}
+		httpRequests.Inc(endpoint, algorithm, strconv.Itoa(recorder.status))
+		httpRequestDuration.Observe(time.Since(start).Seconds(), endpoint, algorithm)
+	})
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/api/handlers"
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/metrics"
+)
+
+const testDatasetJSON = `[
+	{"name": "Mud", "recipes": [["Earth", "Water"]], "tier": 1},
+	{"name": "Brick", "recipes": [["Mud", "Fire"]], "tier": 2}
+]`
+
+// useTestDataset mengganti handlers.Datasets dengan dataset kecil bernama "test".
+func useTestDataset(t *testing.T) {
+	t.Helper()
+	registry := loadrecipes.NewRegistry("test")
+	registry.Register(loadrecipes.DatasetConfig{
+		Name: "test", Path: "elements.json",
+		FS: fstest.MapFS{"elements.json": {Data: []byte(testDatasetJSON)}},
+	})
+	previousDatasets, previousAdmission := handlers.Datasets, handlers.Admission
+	handlers.Datasets, handlers.Admission = registry, nil
+	t.Cleanup(func() {
+		handlers.Datasets, handlers.Admission = previousDatasets, previousAdmission
+	})
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	useTestDataset(t)
+	server := httptest.NewServer(SetupRouter(RouterOptions{DisableRequestLog: true}))
+	defer server.Close()
+
+	for _, path := range []string{"/api/search?target=Brick&algorithm=dfs", "/api/render/Brick.svg?index=500", "/nope"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != metrics.ContentType {
+		t.Fatalf("status = %d, Content-Type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"# TYPE alchemy_http_requests_total counter",
+		`alchemy_http_requests_total{endpoint="/api/search",algorithm="dfs",status="200"} `,
+		// Route render dicatat dengan polanya, bukan path per elemen.
+		`alchemy_http_requests_total{endpoint="/api/render/",algorithm="none",status="400"} `,
+		`alchemy_http_requests_total{endpoint="/",algorithm="none",status="404"} `,
+		`alchemy_http_request_duration_seconds_count{endpoint="/api/search",algorithm="dfs"} `,
+		`alchemy_cache_requests_total{cache="search_etag",result="miss"} `,
+		`alchemy_graph_elements{dataset="test"} 6`,
+		"# TYPE go_goroutines gauge",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("/metrics tidak berisi %q", want)
+		}
+	}
+	if strings.Contains(string(body), "Brick.svg") {
+		t.Error("/metrics berisi label per elemen")
+	}
+}
+
+func TestMetricsEndpointRejectsPost(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SetupRouter(RouterOptions{DisableRequestLog: true}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodGet {
+		t.Errorf("status = %d, Allow = %q; want 405 dengan Allow: GET", rec.Code, rec.Header().Get("Allow"))
+	}
+}
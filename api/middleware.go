@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/api/handlers"
+)
+
+// Middleware membungkus http.Handler, mis. untuk menambah header atau logging.
+type Middleware func(http.Handler) http.Handler
+
+// Chain membungkus handler dengan middlewares; middleware pertama menjadi lapisan terluar.
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// CORSOptions mengatur middleware CORS.
+type CORSOptions struct {
+	// AllowedOrigins berisi origin yang boleh memanggil API; "*" berarti semua origin.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// DefaultCORSOptions sama dengan header yang dulu ditulis tiap handler: semua origin boleh.
+func DefaultCORSOptions() CORSOptions {
+	return CORSOptions{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+		AllowedHeaders: []string{"Content-Type"},
+	}
+}
+
+// CORS menulis header Access-Control-* untuk origin yang diizinkan dan menjawab preflight
+// OPTIONS tanpa meneruskannya ke handler.
+func CORS(opts CORSOptions) Middleware {
+	allowAll := false
+	allowed := make(map[string]bool, len(opts.AllowedOrigins))
+	for _, origin := range opts.AllowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if allowAll {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				// Respons berbeda per origin, jadi cache harus membedakannya.
+				w.Header().Add("Vary", "Origin")
+				if origin := r.Header.Get("Origin"); origin != "" && allowed[origin] {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+			}
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestIDHeader adalah header yang membawa ID request dari klien dan ke respons.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// RequestIDFromContext mengembalikan ID request yang dipasang middleware RequestID, atau "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID memakai header X-Request-ID dari klien (atau membuat ID acak), menyimpannya di
+// context request, dan menuliskannya di header respons.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" || len(id) > 128 {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+		})
+	}
+}
+
+func newRequestID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// statusRecorder mencatat status code dan ukuran respons untuk logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(data)
+	r.bytes += n
+	return n, err
+}
+
+// Logging menulis satu baris log per request: method, path, status, ukuran, durasi, dan ID request.
+func Logging(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+			logger.Printf("[HTTP] %s %s %d %dB %s id=%s", r.Method, r.URL.RequestURI(), recorder.status, recorder.bytes,
+				time.Since(start).Round(time.Microsecond), RequestIDFromContext(r.Context()))
+		})
+	}
+}
+
+// Recover menangkap panic di handler, mencatat stack trace, dan membalas 500 alih-alih
+// memutus koneksi.
+func Recover(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					if recovered == http.ErrAbortHandler {
+						panic(recovered)
+					}
+					logger.Printf("[PANIC] %s %s id=%s: %v\n%s", r.Method, r.URL.Path, RequestIDFromContext(r.Context()), recovered, debug.Stack())
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusInternalServerError)
+					json.NewEncoder(w).Encode(handlers.ErrorResponse{
+						Code:    handlers.CodeInternal,
+						Message: "Internal server error",
+						Details: map[string]interface{}{"requestId": RequestIDFromContext(r.Context())},
+					})
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
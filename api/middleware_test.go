@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/api/handlers"
+)
+
+// okHandler membalas 204 dan mencatat bahwa request sampai ke handler.
+func okHandler(reached *bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*reached = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func TestCORS(t *testing.T) {
+	restricted := CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com/", "http://localhost:3000"},
+		AllowedMethods: []string{http.MethodGet, http.MethodOptions},
+		AllowedHeaders: []string{"Content-Type"},
+	}
+	tests := []struct {
+		name        string
+		opts        CORSOptions
+		method      string
+		origin      string
+		wantStatus  int
+		wantReached bool
+		wantOrigin  string
+		wantVary    bool
+	}{
+		{name: "semua origin", opts: DefaultCORSOptions(), method: http.MethodGet, origin: "https://evil.example", wantStatus: http.StatusNoContent, wantReached: true, wantOrigin: "*"},
+		{name: "origin diizinkan", opts: restricted, method: http.MethodGet, origin: "http://localhost:3000", wantStatus: http.StatusNoContent, wantReached: true, wantOrigin: "http://localhost:3000", wantVary: true},
+		{name: "origin diizinkan tanpa slash", opts: restricted, method: http.MethodGet, origin: "https://app.example.com", wantStatus: http.StatusNoContent, wantReached: true, wantOrigin: "https://app.example.com", wantVary: true},
+		{name: "origin ditolak", opts: restricted, method: http.MethodGet, origin: "https://evil.example", wantStatus: http.StatusNoContent, wantReached: true, wantVary: true},
+		{name: "tanpa origin", opts: restricted, method: http.MethodGet, wantStatus: http.StatusNoContent, wantReached: true, wantVary: true},
+		{name: "preflight semua origin", opts: DefaultCORSOptions(), method: http.MethodOptions, origin: "https://evil.example", wantStatus: http.StatusOK, wantOrigin: "*"},
+		{name: "preflight origin diizinkan", opts: restricted, method: http.MethodOptions, origin: "http://localhost:3000", wantStatus: http.StatusOK, wantOrigin: "http://localhost:3000", wantVary: true},
+		{name: "preflight origin ditolak", opts: restricted, method: http.MethodOptions, origin: "https://evil.example", wantStatus: http.StatusOK, wantVary: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			req := httptest.NewRequest(tt.method, "/api/search", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			CORS(tt.opts)(okHandler(&reached)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if reached != tt.wantReached {
+				t.Errorf("handler dipanggil = %v, want %v", reached, tt.wantReached)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := rec.Header().Get("Vary") == "Origin"; got != tt.wantVary {
+				t.Errorf("Vary = %q, want Origin: %v", rec.Header().Get("Vary"), tt.wantVary)
+			}
+			if got, want := rec.Header().Get("Access-Control-Allow-Methods"), strings.Join(tt.opts.AllowedMethods, ", "); got != want {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	generated := regexp.MustCompile(`^[0-9a-f]{16}$`)
+	tests := []struct {
+		name     string
+		incoming string
+		wantKept bool
+	}{
+		{name: "dari klien", incoming: "trace-123", wantKept: true},
+		{name: "tepat 128 karakter", incoming: strings.Repeat("a", 128), wantKept: true},
+		{name: "lebih dari 128 karakter", incoming: strings.Repeat("a", 129)},
+		{name: "kosong"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromContext = RequestIDFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			if id != fromContext {
+				t.Errorf("ID di header = %q, di context = %q", id, fromContext)
+			}
+			if tt.wantKept {
+				if id != tt.incoming {
+					t.Errorf("ID = %q, want %q", id, tt.incoming)
+				}
+			} else if !generated.MatchString(id) {
+				t.Errorf("ID = %q, want ID acak 16 hex", id)
+			}
+		})
+	}
+
+	if id := RequestIDFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); id != "" {
+		t.Errorf("RequestIDFromContext tanpa middleware = %q, want kosong", id)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var logs bytes.Buffer
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, "abc")
+	}), RequestID(), Logging(log.New(&logs, "", 0)))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/verify?target=Brick", nil)
+	req.Header.Set(RequestIDHeader, "trace-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := logs.String()
+	for _, want := range []string{"[HTTP] POST /api/verify?target=Brick 201 3B ", "id=trace-123"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log = %q, want berisi %q", line, want)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), RequestID(), Recover(logger))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	req.Header.Set(RequestIDHeader, "trace-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var body handlers.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("body bukan ErrorResponse: %v", err)
+	}
+	if body.Code != handlers.CodeInternal || body.Details["requestId"] != "trace-123" {
+		t.Errorf("error = %+v, want %s dengan requestId trace-123", body, handlers.CodeInternal)
+	}
+	if !strings.Contains(logs.String(), "[PANIC] GET /api/search id=trace-123: boom") {
+		t.Errorf("log panic = %q", logs.String())
+	}
+}
+
+func TestRecoverRepanicsErrAbortHandler(t *testing.T) {
+	var logs bytes.Buffer
+	handler := Recover(log.New(&logs, "", 0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	rec := httptest.NewRecorder()
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("panic = %v, want http.ErrAbortHandler", recovered)
+		}
+		// Koneksi diputus oleh server, jadi tidak ada respons 500 maupun log panic.
+		if rec.Body.Len() != 0 || logs.Len() != 0 {
+			t.Errorf("ErrAbortHandler menulis respons %q atau log %q", rec.Body.String(), logs.String())
+		}
+	}()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/search", nil))
+}
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	layer := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}), layer("luar"), layer("dalam"))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := strings.Join(order, " > "); got != "luar > dalam > handler" {
+		t.Errorf("urutan = %s, want luar > dalam > handler", got)
+	}
+}
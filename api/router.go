@@ -1,15 +1,65 @@
 package api
 
 import (
+	"log"
 	"net/http"
+	"strings"
+
 	"github.com/Starath/Tubes2_BE_SayMyName/api/handlers"
 )
 
-func SetupRouter() *http.ServeMux {
+// RouterOptions mengatur SetupRouter.
+type RouterOptions struct {
+	// Prefix dipasang di depan semua route, mis. "/alchemy" -> /alchemy/api/pathfinding/bfs.
+	// Berguna saat API di-mount di dalam server Go lain.
+	Prefix string
+	CORS   CORSOptions
+	// Logger dipakai middleware logging dan recovery; nil berarti log.Default().
+	Logger *log.Logger
+	// DisableRequestLog mematikan log satu baris per request.
+	DisableRequestLog bool
+	// Middleware tambahan, dijalankan setelah middleware bawaan dan sebelum handler.
+	Middleware []Middleware
+}
+
+// DefaultRouterOptions mengembalikan opsi tanpa prefix dengan CORS terbuka untuk semua origin.
+func DefaultRouterOptions() RouterOptions {
+	return RouterOptions{CORS: DefaultCORSOptions()}
+}
+
+// SetupRouter membuat handler berisi semua route API (masing-masing dengan metrik request
+// di /metrics), dibungkus middleware request ID, logging, panic recovery, CORS, lalu
+// opts.Middleware.
+func SetupRouter(opts RouterOptions) http.Handler {
 	router := http.NewServeMux()
-	router.HandleFunc("/api/pathfinding/bfs", handlers.BFSPathfindingHandler)
-	router.HandleFunc("/api/pathfinding/dfs-single", handlers.DFSPathfindingHandler)
-	router.HandleFunc("/api/pathfinding/dfs-multiple", handlers.DFSMultiplePathfindingHandler)
+	handle := func(pattern string, handler http.HandlerFunc) {
+		router.Handle(pattern, instrument(pattern, handler))
+	}
+	handle("/api/pathfinding/bfs", handlers.BFSPathfindingHandler)
+	handle("/api/pathfinding/dfs-single", handlers.DFSPathfindingHandler)
+	handle("/api/pathfinding/dfs-multiple", handlers.DFSMultiplePathfindingHandler)
+	handle("/api/search", handlers.SearchHandler)
+	handle("/api/recipes/verify", handlers.VerifyRecipeHandler)
+	handle("/api/stats/graph", handlers.GraphStatsHandler)
+	handle("/api/datasets", handlers.DatasetsHandler)
+	handle("/api/export", handlers.ExportGraphHandler)
+	handle(handlers.RenderPathPrefix, handlers.RenderRecipeHandler)
+	handle("/metrics", handlers.MetricsHandler)
+	handle("/healthz", handlers.HealthzHandler)
+	handle("/readyz", handlers.ReadyzHandler)
+	handle("/version", handlers.VersionHandler)
+	handle("/", handlers.NotFoundHandler)
+
+	var handler http.Handler = router
+	if prefix := strings.TrimSuffix(opts.Prefix, "/"); prefix != "" {
+		handler = http.StripPrefix(prefix, handler)
+	}
 
-	return router
-}
\ No newline at end of file
+	middlewares := []Middleware{RequestID()}
+	if !opts.DisableRequestLog {
+		middlewares = append(middlewares, Logging(opts.Logger))
+	}
+	middlewares = append(middlewares, Recover(opts.Logger), CORS(opts.CORS))
+	middlewares = append(middlewares, opts.Middleware...)
+	return Chain(handler, middlewares...)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/imagedownloader"
+)
+
+func main() {
+	input := flag.String("input", imagedownloader.DefaultInputPath, "dataset JSON yang berisi imageUrl")
+	out := flag.String("out", imagedownloader.DefaultOutputDir, "folder tujuan gambar")
+	concurrency := flag.Int("concurrency", imagedownloader.DefaultConcurrency, "jumlah unduhan paralel")
+	retries := flag.Int("retries", imagedownloader.DefaultRetries, "jumlah percobaan ulang per gambar (error jaringan, 429, 5xx)")
+	backoff := flag.Duration("backoff", imagedownloader.DefaultInitialBackoff, "jeda sebelum percobaan ulang pertama, berlipat dua tiap percobaan")
+	timeout := flag.Duration("timeout", imagedownloader.DefaultRequestTimeout, "timeout per request")
+	dryRun := flag.Bool("dry-run", false, "hanya tampilkan gambar yang akan diunduh")
+	verify := flag.Bool("verify", false, "hanya cek elemen mana yang belum punya ikon SVG/PNG yang utuh")
+	format := flag.String("format", "text", "format ringkasan: text atau json")
+	flag.Parse()
+
+	opts := imagedownloader.Options{
+		InputPath:      *input,
+		OutputDir:      *out,
+		Concurrency:    *concurrency,
+		Retries:        *retries,
+		InitialBackoff: *backoff,
+		DryRun:         *dryRun,
+	}
+	if *timeout != imagedownloader.DefaultRequestTimeout {
+		opts.Client = imagedownloader.NewHTTPClient(*timeout)
+	}
+
+	if *verify {
+		runVerify(opts, *format)
+		return
+	}
+
+	start := time.Now()
+	report, err := imagedownloader.DownloadImages(opts)
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	case "text":
+		for _, name := range report.Repaired {
+			fmt.Printf("rusak, diunduh ulang: %s\n", name)
+		}
+		for _, name := range report.Planned {
+			fmt.Printf("akan diunduh: %s\n", name)
+		}
+		for _, failure := range report.Failed {
+			fmt.Printf("gagal: %s (%s) setelah %d percobaan: %s\n", failure.Name, failure.URL, failure.Attempts, failure.Error)
+		}
+		fmt.Printf("%s: %d diunduh, %d sudah ada, %d direncanakan, %d tanpa URL, %d gagal (%s)\n",
+			report.OutputDir, len(report.Downloaded), len(report.Skipped), len(report.Planned), len(report.NoURL), len(report.Failed), time.Since(start).Round(time.Millisecond))
+	default:
+		log.Fatalf("FATAL: format '%s' tidak dikenal", *format)
+	}
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	if len(report.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runVerify menjalankan mode -verify dan keluar dengan status 1 jika ada elemen tanpa ikon
+// yang bisa dipakai.
+func runVerify(opts imagedownloader.Options, format string) {
+	report, err := imagedownloader.VerifyImages(opts)
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	case "text":
+		for _, name := range report.Missing {
+			fmt.Printf("tidak ada: %s\n", name)
+		}
+		for _, invalid := range report.Invalid {
+			fmt.Printf("tidak bisa dipakai: %s (%s): %s\n", invalid.Name, invalid.File, invalid.Reason)
+		}
+		fmt.Printf("%s: %d dari %d elemen punya ikon, %d tidak ada, %d tidak bisa dipakai\n",
+			report.OutputDir, report.Usable, report.Elements, len(report.Missing), len(report.Invalid))
+	default:
+		log.Fatalf("FATAL: format '%s' tidak dikenal", format)
+	}
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
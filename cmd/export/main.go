@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+func main() {
+	file := flag.String("file", "elements_filtered.json", "dataset JSON yang di-export")
+	format := flag.String("format", loadrecipes.ExportGraphML, "format: graphml, dot, csv-nodes, csv-edges, atau csv (nodes + edges)")
+	out := flag.String("out", "", "file output (kosong = stdout); untuk -format csv dipakai sebagai prefix <out>_nodes.csv dan <out>_edges.csv")
+	baseElements := flag.String("base", strings.Join(loadrecipes.DefaultBaseElements, ","), "elemen dasar dipisah koma")
+	policy := flag.String("policy", "default", "filter policy saat memuat: default, none, atau daftar aturan (tier,unknown,self)")
+	flag.Parse()
+
+	filterPolicy, err := loadrecipes.ParseFilterPolicy(*policy)
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+	graph, err := loadrecipes.LoadBiGraphWithOptions(*file, loadrecipes.LoadOptions{
+		BaseElements: strings.Split(*baseElements, ","),
+		Policy:       filterPolicy,
+	})
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	if *format == "csv" {
+		if *out == "" {
+			log.Fatal("FATAL: -format csv membutuhkan -out sebagai prefix file")
+		}
+		for _, part := range []string{loadrecipes.ExportCSVNodes, loadrecipes.ExportCSVEdges} {
+			path := *out + "_" + strings.TrimPrefix(part, "csv-") + ".csv"
+			if err := writeFile(path, graph, part); err != nil {
+				log.Fatalf("FATAL: %s", err)
+			}
+			fmt.Printf("Menulis '%s'.\n", path)
+		}
+		return
+	}
+
+	if *out == "" {
+		err = loadrecipes.WriteExport(os.Stdout, graph, *format)
+	} else {
+		err = writeFile(*out, graph, *format)
+	}
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+}
+
+func writeFile(path string, graph *loadrecipes.BiGraphAlchemy, format string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return loadrecipes.WriteExport(file, graph, format)
+}
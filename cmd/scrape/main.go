@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/scrape"
+)
+
+func main() {
+	sourceURL := flag.String("url", scrape.DefaultSourceURL, "URL halaman wiki yang di-scrape")
+	inputFile := flag.String("input", "", "file HTML lokal (mengabaikan -url jika diisi)")
+	outputPath := flag.String("out", scrape.DefaultOutputPath, "path file JSON output")
+	auditPath := flag.String("audit", scrape.DefaultAuditPath, "path laporan audit filter (kosong = tidak ditulis)")
+	auditPolicy := flag.String("audit-policy", "default", "filter policy untuk audit: default, none, atau aturan dipisah koma (tier,unknown,self)")
+	compact := flag.Bool("compact", false, "tulis JSON tanpa indentasi")
+	layout := flag.String("layout", scrape.LayoutLA2, "layout tabel wiki: la2 atau la1")
+	baseElements := flag.String("base", strings.Join(scrape.DefaultBaseElements, ","), "elemen dasar dipisah koma")
+	flag.Parse()
+
+	elements, err := scrape.Run(scrape.Options{
+		SourceURL:    *sourceURL,
+		InputFile:    *inputFile,
+		OutputPath:   *outputPath,
+		AuditPath:    *auditPath,
+		AuditPolicy:  *auditPolicy,
+		Pretty:       !*compact,
+		Layout:       *layout,
+		BaseElements: strings.Split(*baseElements, ","),
+	})
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+	fmt.Printf("Selesai. %d elemen ditulis ke '%s'.\n", len(elements), *outputPath)
+}
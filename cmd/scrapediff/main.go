@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/bfs"
+	"github.com/Starath/Tubes2_BE_SayMyName/scrape"
+)
+
+// TargetChange menunjukkan resep minimal (hasil pertama BFS sekuensial) sebuah target
+// sebelum dan sesudah dataset berubah.
+type TargetChange struct {
+	Target      string `json:"target"`
+	OldRecipeID string `json:"oldRecipeId"`
+	NewRecipeID string `json:"newRecipeId"`
+	OldSteps    int    `json:"oldSteps"`
+	NewSteps    int    `json:"newSteps"`
+}
+
+type diffOutput struct {
+	*scrape.Changelog
+	TargetChanges []TargetChange `json:"targetChanges,omitempty"`
+}
+
+func main() {
+	oldPath := flag.String("old", "elements_filtered.json", "dataset lama")
+	newPath := flag.String("new", "", "dataset baru (wajib)")
+	format := flag.String("format", "text", "format output: text atau json")
+	targets := flag.String("targets", "", "daftar target dipisah koma; laporkan yang resep minimalnya berubah")
+	flag.Parse()
+
+	if *newPath == "" {
+		log.Fatal("FATAL: -new wajib diisi")
+	}
+
+	oldElements, err := scrape.ReadElementsFile(*oldPath)
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+	newElements, err := scrape.ReadElementsFile(*newPath)
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	output := diffOutput{Changelog: scrape.DiffElements(oldElements, newElements)}
+	if *targets != "" {
+		output.TargetChanges, err = compareTargets(*oldPath, *newPath, strings.Split(*targets, ","))
+		if err != nil {
+			log.Fatalf("FATAL: %s", err)
+		}
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(output)
+	case "text":
+		err = output.WriteText(os.Stdout)
+		if err == nil && *targets != "" {
+			fmt.Printf("Resep minimal yang berubah (%d):\n", len(output.TargetChanges))
+			for _, change := range output.TargetChanges {
+				fmt.Printf("  %s: %s (%d step) -> %s (%d step)\n", change.Target, change.OldRecipeID, change.OldSteps, change.NewRecipeID, change.NewSteps)
+			}
+		}
+	default:
+		log.Fatalf("FATAL: format '%s' tidak dikenal", *format)
+	}
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+}
+
+// compareTargets mencari resep minimal tiap target di kedua dataset dan mengembalikan
+// target yang RecipeID-nya berbeda (termasuk yang hanya bisa dibuat di salah satu dataset).
+func compareTargets(oldPath, newPath string, targets []string) ([]TargetChange, error) {
+	oldGraph, err := loadrecipes.LoadBiGraph(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newGraph, err := loadrecipes.LoadBiGraph(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []TargetChange{}
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		oldID, oldSteps := minimalRecipe(oldGraph, target)
+		newID, newSteps := minimalRecipe(newGraph, target)
+		if oldID != newID {
+			changes = append(changes, TargetChange{Target: target, OldRecipeID: oldID, NewRecipeID: newID, OldSteps: oldSteps, NewSteps: newSteps})
+		}
+	}
+	return changes, nil
+}
+
+// minimalRecipe mengembalikan RecipeID dan jumlah step resep pertama dari BFS sekuensial
+// (yang berurutan per level, jadi resep pertama adalah yang paling pendek).
+func minimalRecipe(graph *loadrecipes.BiGraphAlchemy, target string) (string, int) {
+	result, err := bfs.BFSFindPath(graph, target, 1)
+	if err != nil || result == nil || len(result.Results) == 0 {
+		return "", 0
+	}
+	return result.Results[0].RecipeID, len(result.Results[0].Path)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeDataset(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCompareTargets(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	oldPath := writeDataset(t, "old.json", `[
+		{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+		{"name": "Steam", "tier": 1, "recipes": [["Fire", "Water"]]},
+		{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"]]}
+	]`)
+	newPath := writeDataset(t, "new.json", `[
+		{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+		{"name": "Steam", "tier": 1, "recipes": [["Fire", "Water"]]},
+		{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"], ["Earth", "Fire"]]},
+		{"name": "Wall", "tier": 3, "recipes": [["Brick", "Brick"]]}
+	]`)
+
+	changes, err := compareTargets(oldPath, newPath, []string{"Brick", " Steam", "", "Wall"})
+	if err != nil {
+		t.Fatalf("compareTargets: %v", err)
+	}
+
+	var targets []string
+	byTarget := make(map[string]TargetChange)
+	for _, change := range changes {
+		targets = append(targets, change.Target)
+		byTarget[change.Target] = change
+	}
+	// Steam tidak berubah; Brick punya resep minimal baru, Wall hanya ada di dataset baru.
+	if want := []string{"Brick", "Wall"}; !reflect.DeepEqual(targets, want) {
+		t.Fatalf("target berubah = %v, want %v", targets, want)
+	}
+	if brick := byTarget["Brick"]; brick.OldSteps != 2 || brick.NewSteps != 1 || brick.OldRecipeID == "" || brick.NewRecipeID == "" {
+		t.Errorf("perubahan Brick = %+v, want 2 step -> 1 step", brick)
+	}
+	if wall := byTarget["Wall"]; wall.OldRecipeID != "" || wall.OldSteps != 0 || wall.NewSteps != 2 {
+		t.Errorf("perubahan Wall = %+v, want tidak ada -> 2 step", wall)
+	}
+
+	if _, err := compareTargets(filepath.Join(t.TempDir(), "missing.json"), newPath, []string{"Brick"}); err == nil {
+		t.Error("compareTargets dengan dataset yang tidak ada seharusnya error")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/search"
+	"github.com/Starath/Tubes2_BE_SayMyName/render"
+)
+
+func main() {
+	file := flag.String("file", "elements_filtered.json", "dataset JSON")
+	target := flag.String("target", "", "elemen yang dicari resepnya")
+	algorithm := flag.String("algorithm", search.AlgorithmBFS, "algoritma: "+strings.Join(search.Algorithms, ", "))
+	maxPaths := flag.Int("max", 1, "jumlah resep maksimum")
+	format := flag.String("format", "json", "format output: json, "+strings.Join(render.Formats, ", "))
+	inventory := flag.String("inventory", "", "elemen yang sudah dimiliki, dipisah koma (untuk -format guide)")
+	baseElements := flag.String("base", strings.Join(loadrecipes.DefaultBaseElements, ","), "elemen dasar dipisah koma")
+	flag.Parse()
+
+	if *target == "" {
+		log.Fatal("FATAL: -target wajib diisi")
+	}
+	if *format != "json" && !loadrecipes.ContainsString(render.Formats, *format) {
+		log.Fatalf("FATAL: %s", &render.UnknownFormatError{Format: *format})
+	}
+
+	graph, err := loadrecipes.LoadBiGraphWithOptions(*file, loadrecipes.LoadOptions{
+		BaseElements: strings.Split(*baseElements, ","),
+		Policy:       loadrecipes.DefaultFilterPolicy,
+	})
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	results, err := search.Find(graph, *algorithm, *target, *maxPaths)
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(results)
+	} else {
+		err = render.WriteResults(os.Stdout, *format, *target, results, render.Options{Inventory: splitList(*inventory)})
+	}
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+}
+
+// splitList memecah daftar dipisah koma, membuang spasi dan entri kosong.
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
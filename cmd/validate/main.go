@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+func main() {
+	file := flag.String("file", "elements_filtered.json", "dataset JSON yang dicek")
+	baseElements := flag.String("base", strings.Join(loadrecipes.DefaultBaseElements, ","), "elemen dasar dipisah koma")
+	imageDir := flag.String("images", "", "folder gambar lokal (nama file = nama elemen); kosong = hanya cek imageUrl")
+	format := flag.String("format", "text", "format output: text atau json")
+	strict := flag.Bool("strict", false, "anggap warning sebagai error untuk exit code")
+	flag.Parse()
+
+	elements, err := loadrecipes.ReadElementInputs(*file)
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	opts := loadrecipes.ValidationOptions{BaseElements: strings.Split(*baseElements, ",")}
+	if *imageDir != "" {
+		opts.ImageNames, err = readImageNames(*imageDir)
+		if err != nil {
+			log.Fatalf("FATAL: %s", err)
+		}
+	}
+	report := loadrecipes.ValidateElements(elements, opts)
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	case "text":
+		for _, issue := range report.Issues {
+			fmt.Printf("[%s] %s: %s\n", issue.Severity, issue.Code, issue.Message)
+		}
+		fmt.Printf("%s: %d elemen, %d resep, %d error, %d warning\n", *file, report.Elements, report.Recipes, report.Errors, report.Warnings)
+	default:
+		log.Fatalf("FATAL: format '%s' tidak dikenal", *format)
+	}
+	if err != nil {
+		log.Fatalf("FATAL: %s", err)
+	}
+
+	if !report.Valid || (*strict && report.Warnings > 0) {
+		os.Exit(1)
+	}
+}
+
+// readImageNames mengembalikan nama elemen (nama file tanpa ekstensi) untuk setiap file
+// tidak kosong di dir.
+func readImageNames(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() == 0 {
+			continue
+		}
+		names[strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))] = true
+	}
+	return names, nil
+}
@@ -4,13 +4,14 @@ package imagedownloader
 import (
 	"crypto/tls" // Dari referensi Anda
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time" // Dari referensi Anda
@@ -22,15 +23,73 @@ type Element struct {
 	Recipes  [][]string `json:"recipes"` // Mungkin tidak digunakan di sini tapi ada di JSON
 	Tier     int        `json:"tier"`    // Mungkin tidak digunakan di sini tapi ada di JSON
 	ImageURL string     `json:"imageUrl,omitempty"`
+	Pack     string     `json:"pack,omitempty"`
 }
 
 const (
-	// Sesuaikan path ini jika perlu, atau terima sebagai argumen fungsi
-	outputDirRoot          = "downloaded_images" // Nama folder output utama
-	maxConcurrentDownloads = 10
-	requestTimeout         = 30 * time.Second
+	// DefaultOutputDir adalah folder output utama jika Options.OutputDir kosong.
+	DefaultOutputDir = "downloaded_images"
+	// DefaultInputPath adalah dataset yang dibaca jika Options.InputPath kosong.
+	DefaultInputPath      = "elements_with_images.json"
+	DefaultConcurrency    = 10
+	DefaultRetries        = 3
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultRequestTimeout = 30 * time.Second
+
+	userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+	// partSuffix dipakai untuk file yang sedang diunduh; file ini diabaikan saat resume.
+	partSuffix = ".part"
 )
 
+// Options mengatur DownloadImages. Field yang kosong/nol memakai nilai Default*.
+type Options struct {
+	InputPath   string
+	OutputDir   string
+	Concurrency int
+	// Retries adalah jumlah percobaan ulang setelah percobaan pertama gagal.
+	// Jeda sebelum percobaan ulang ke-n adalah InitialBackoff * 2^(n-1).
+	Retries        int
+	InitialBackoff time.Duration
+	// DryRun hanya melaporkan gambar yang akan diunduh tanpa request HTTP dan tanpa menulis file.
+	DryRun bool
+	// Client dipakai untuk semua request; jika nil dibuat client dengan DefaultRequestTimeout.
+	// Bisa diisi httptest.Server.Client() untuk pengujian.
+	Client *http.Client
+}
+
+// DefaultOptions mengembalikan Options dengan nilai default.
+func DefaultOptions() Options {
+	return Options{
+		InputPath:      DefaultInputPath,
+		OutputDir:      DefaultOutputDir,
+		Concurrency:    DefaultConcurrency,
+		Retries:        DefaultRetries,
+		InitialBackoff: DefaultInitialBackoff,
+	}
+}
+
+// DownloadFailure adalah gambar yang tetap gagal diunduh setelah semua percobaan ulang.
+type DownloadFailure struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error"`
+}
+
+// Report adalah ringkasan satu kali DownloadImages. Semua daftar berisi nama elemen, urut nama.
+// Repaired berisi elemen yang file lamanya tidak bisa dipakai sehingga dijadwalkan unduh ulang
+// (elemen ini juga muncul di Downloaded, Planned, NoURL, atau Failed).
+type Report struct {
+	OutputDir  string            `json:"outputDir"`
+	Downloaded []string          `json:"downloaded"`
+	Skipped    []string          `json:"skipped"`
+	Repaired   []string          `json:"repaired"`
+	Planned    []string          `json:"planned,omitempty"`
+	NoURL      []string          `json:"noUrl"`
+	Failed     []DownloadFailure `json:"failed"`
+	Manifest   string            `json:"manifest,omitempty"`
+}
+
 // Fungsi sanitizeFilename dari referensi Anda
 func sanitizeFilename(name string) string {
 	replacer := strings.NewReplacer(
@@ -41,166 +100,373 @@ func sanitizeFilename(name string) string {
 	return replacer.Replace(name)
 }
 
-// Fungsi getFileExtension dari referensi Anda
-func getFileExtension(rawURL string, contentType string) string {
-	parsedURL, err := url.Parse(rawURL)
-	if err == nil {
-		ext := filepath.Ext(parsedURL.Path)
-		if ext != "" && len(ext) > 1 {
-			// Bersihkan parameter query dari ekstensi
-			cleanExt := strings.Split(strings.ToLower(ext), "?")[0]
-			// Validasi sederhana untuk panjang ekstensi
-			if len(cleanExt) > 1 && len(cleanExt) <= 5 { // e.g., .svg, .png
-				return cleanExt
-			}
-		}
+// retryableError menandai kegagalan yang layak dicoba ulang: error jaringan, respons kosong,
+// status 429, atau status 5xx.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+// scanExistingImages mengembalikan nama file dasar (tanpa ekstensi) -> path untuk setiap file
+// .svg/.png di dir, termasuk file kosong agar bisa diperbaiki. File .part dari run yang
+// terputus diabaikan sehingga diunduh ulang.
+func scanExistingImages(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
 	}
-	// Fallback ke Content-Type jika ekstensi dari URL tidak valid/ada
-	if contentType != "" {
-		switch strings.ToLower(strings.Split(contentType, ";")[0]) {
-		case "image/jpeg":
-			return ".jpg"
-		case "image/png":
-			return ".png"
-		case "image/gif":
-			return ".gif"
-		case "image/svg+xml":
-			return ".svg"
-		case "image/webp":
-			return ".webp"
-		case "image/avif":
-			return ".avif"
+	existing := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".svg" && ext != ".png") {
+			continue
 		}
+		existing[strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))] = filepath.Join(dir, entry.Name())
 	}
-	log.Printf("Tidak dapat menentukan ekstensi untuk URL: %s, Content-Type: '%s'. Menggunakan default .bin (binary)\n", rawURL, contentType)
-	return ".bin" // Default ke .bin jika tidak ada yang cocok
+	return existing, nil
 }
 
-func downloadIndividualFile(element Element, fullOutputDir string, client *http.Client, wg *sync.WaitGroup, sem chan struct{}) {
-	defer wg.Done()
-	defer func() { <-sem }() // Lepaskan slot semaphore
-
-	// Menggunakan element.Name untuk baseFileName, lalu disanitasi
-	baseFileName := sanitizeFilename(element.Name)
-
-	fmt.Printf("Mencoba mengunduh: '%s' dari %s\n", element.Name, element.ImageURL)
-
-	if element.ImageURL == "" {
-		log.Printf("URL gambar kosong untuk elemen: '%s'\n", element.Name)
-		return
+// findExistingImage mencari file gambar elemen, baik dengan nama yang sudah disanitasi
+// (hasil downloader) maupun nama elemen apa adanya (seperti file di img/).
+func findExistingImage(existing map[string]string, name string) (string, string, bool) {
+	for _, baseFileName := range []string{sanitizeFilename(name), name} {
+		if path, ok := existing[baseFileName]; ok {
+			return baseFileName, path, true
+		}
 	}
+	return "", "", false
+}
 
+// downloadOnce mengunduh satu gambar ke <outputDir>/<baseFileName><ext>, dengan ekstensi sesuai
+// isi (.svg atau .png). File ditulis ke .part dulu lalu di-rename, jadi file final tidak pernah
+// berisi unduhan yang terpotong. Jika replacePath berbeda dari path baru, file lama dihapus.
+func downloadOnce(client *http.Client, element Element, outputDir, baseFileName, replacePath string) (ManifestEntry, error) {
 	req, err := http.NewRequest("GET", element.ImageURL, nil)
 	if err != nil {
-		log.Printf("Gagal membuat request untuk '%s' (%s): %v\n", element.Name, element.ImageURL, err)
-		return
+		return ManifestEntry{}, fmt.Errorf("gagal membuat request: %w", err)
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	req.Header.Set("User-Agent", userAgent)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("Gagal mengunduh '%s' (%s): %v\n", element.Name, element.ImageURL, err)
-		return
+		return ManifestEntry{}, &retryableError{err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("Gagal mengunduh '%s' (%s): status code %d\n", element.Name, element.ImageURL, resp.StatusCode)
-		return
+		statusErr := fmt.Errorf("status code %d", resp.StatusCode)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return ManifestEntry{}, &retryableError{err: statusErr}
+		}
+		return ManifestEntry{}, statusErr
 	}
 
-	contentType := resp.Header.Get("Content-Type")
-	fileExt := getFileExtension(element.ImageURL, contentType)
-	finalFileName := baseFileName + fileExt // Nama file menggunakan nama elemen yang disanitasi
-	filePath := filepath.Join(fullOutputDir, finalFileName)
-
-	// Cek apakah file sudah ada, jika iya, lewati
-	if _, err := os.Stat(filePath); err == nil {
-		// fmt.Printf("INFO: File '%s' sudah ada. Melewati.\n", filePath)
-		return
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ManifestEntry{}, &retryableError{err: err}
 	}
-
-	out, err := os.Create(filePath)
+	if resp.ContentLength > 0 && int64(len(data)) != resp.ContentLength {
+		return ManifestEntry{}, &retryableError{err: fmt.Errorf("%w: %d dari %d byte", errImageTruncated, len(data), resp.ContentLength)}
+	}
+	contentType, fileExt, err := sniffImage(data)
 	if err != nil {
-		log.Printf("Gagal membuat file '%s' untuk '%s': %v\n", filePath, element.Name, err)
-		return
+		if errors.Is(err, errImageEmpty) || errors.Is(err, errImageTruncated) {
+			return ManifestEntry{}, &retryableError{err: err}
+		}
+		return ManifestEntry{}, fmt.Errorf("%w (Content-Type '%s')", err, resp.Header.Get("Content-Type"))
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		log.Printf("Gagal menyimpan gambar '%s' ke '%s': %v\n", element.Name, filePath, err)
-		os.Remove(filePath) // Hapus file jika gagal copy
-		return
+	filePath := filepath.Join(outputDir, baseFileName+fileExt)
+	partPath := filePath + partSuffix
+	if err := os.WriteFile(partPath, data, 0644); err != nil {
+		os.Remove(partPath)
+		return ManifestEntry{}, fmt.Errorf("gagal menulis file '%s': %w", partPath, err)
+	}
+	if err := os.Rename(partPath, filePath); err != nil {
+		os.Remove(partPath)
+		return ManifestEntry{}, fmt.Errorf("gagal memindahkan '%s': %w", partPath, err)
 	}
+	if replacePath != "" && replacePath != filePath {
+		os.Remove(replacePath)
+	}
+	return newManifestEntry(filePath, data, contentType, element.ImageURL), nil
+}
 
-	fmt.Printf("Berhasil mengunduh '%s' -> '%s'\n", element.Name, filePath)
+// downloadWithRetry memanggil downloadOnce sampai berhasil, gagal permanen, atau percobaan
+// ulang habis, dengan jeda exponential backoff. Mengembalikan path, jumlah percobaan, dan error.
+func downloadWithRetry(client *http.Client, element Element, outputDir, baseFileName, replacePath string, retries int, initialBackoff time.Duration) (ManifestEntry, int, error) {
+	var lastErr error
+	attempt := 0
+	for attempt <= retries {
+		if attempt > 0 {
+			time.Sleep(initialBackoff << (attempt - 1))
+		}
+		attempt++
+		entry, err := downloadOnce(client, element, outputDir, baseFileName, replacePath)
+		if err == nil {
+			return entry, attempt, nil
+		}
+		lastErr = err
+		var retryable *retryableError
+		if !errors.As(err, &retryable) {
+			break
+		}
+		log.Printf("Percobaan %d untuk '%s' gagal: %v\n", attempt, element.Name, err)
+	}
+	return ManifestEntry{}, attempt, lastErr
 }
 
-// DownloadAllImages membaca file JSON dan mengunduh semua gambar elemen.
-func DownloadAllImages(jsonInputPath string) {
-	log.Println("Mulai proses pengunduhan gambar...")
+// NewHTTPClient membuat client dengan timeout per request dan HTTP/2 dimatikan (CDN gambar
+// wiki kadang memutus koneksi HTTP/2 di tengah unduhan).
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	customTransport := &http.Transport{
+		TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: customTransport,
+	}
+}
 
-	jsonData, err := os.ReadFile(jsonInputPath)
+// normalize mengisi field Options yang kosong dengan nilai default.
+func (opts Options) normalize() Options {
+	if opts.InputPath == "" {
+		opts.InputPath = DefaultInputPath
+	}
+	if opts.OutputDir == "" {
+		opts.OutputDir = DefaultOutputDir
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	return opts
+}
+
+// readElements membaca dataset JSON input downloader.
+func readElements(path string) ([]Element, error) {
+	jsonData, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatalf("Gagal membaca file JSON '%s': %v\n", jsonInputPath, err)
+		return nil, fmt.Errorf("gagal membaca file JSON '%s': %w", path, err)
+	}
+	var elements []Element
+	if err := json.Unmarshal(jsonData, &elements); err != nil {
+		return nil, fmt.Errorf("gagal unmarshal data JSON '%s': %w", path, err)
 	}
+	return elements, nil
+}
 
-	var elements []Element // Menggunakan struct Element kita
-	err = json.Unmarshal(jsonData, &elements)
+// readOutputDir mengembalikan path absolut folder output, file gambar yang sudah ada di sana,
+// dan manifest-nya. Folder yang belum ada tidak dianggap error.
+func readOutputDir(outputDir string) (string, map[string]string, Manifest, error) {
+	absOutputDir, err := filepath.Abs(outputDir)
 	if err != nil {
-		log.Fatalf("Gagal unmarshal data JSON: %v\n", err)
+		return "", nil, nil, fmt.Errorf("gagal mendapatkan path absolut untuk direktori output '%s': %w", outputDir, err)
 	}
+	if _, err := os.Stat(absOutputDir); err != nil {
+		return absOutputDir, map[string]string{}, Manifest{}, nil
+	}
+	existing, err := scanExistingImages(absOutputDir)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("gagal membaca direktori output '%s': %w", absOutputDir, err)
+	}
+	manifest, err := ReadManifest(filepath.Join(absOutputDir, DefaultManifestName))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return absOutputDir, existing, manifest, nil
+}
 
-	if len(elements) == 0 {
-		log.Println("Tidak ada elemen ditemukan dalam file JSON.")
-		return
+// manifestEntryFor mengembalikan entri manifest lama untuk name, atau nil.
+func manifestEntryFor(manifest Manifest, name string) *ManifestEntry {
+	if entry, ok := manifest[name]; ok {
+		return &entry
 	}
-	fmt.Printf("Ditemukan %d elemen gambar untuk diunduh.\n", len(elements))
+	return nil
+}
 
-	// Buat direktori output
-	absFinalOutputDir, err := filepath.Abs(outputDirRoot)
-	if err != nil {
-		log.Fatalf("Gagal mendapatkan path absolut untuk direktori output '%s': %v\n", outputDirRoot, err)
+// DownloadImages membaca dataset JSON lalu mengunduh gambar semua elemen secara konkuren.
+// Gambar yang sudah ada dan utuh di OutputDir dilewati, sehingga run yang terputus bisa
+// dilanjutkan dengan menjalankan ulang perintah yang sama. File yang kosong, terpotong, bukan
+// SVG/PNG, atau tidak cocok dengan manifest diunduh ulang. Setelah selesai, manifest ditulis ke
+// <OutputDir>/manifest.json. Error hanya dikembalikan untuk masalah input atau folder output;
+// kegagalan per gambar dicatat di Report.Failed.
+func DownloadImages(opts Options) (*Report, error) {
+	opts = opts.normalize()
+	client := opts.Client
+	if client == nil {
+		client = NewHTTPClient(DefaultRequestTimeout)
 	}
 
-	if _, err := os.Stat(absFinalOutputDir); os.IsNotExist(err) {
-		errDir := os.MkdirAll(absFinalOutputDir, 0755)
-		if errDir != nil {
-			log.Fatalf("Gagal membuat direktori output '%s': %v\n", absFinalOutputDir, errDir)
+	elements, err := readElements(opts.InputPath)
+	if err != nil {
+		return nil, err
+	}
+	absFinalOutputDir, existing, oldManifest, err := readOutputDir(opts.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+	if _, statErr := os.Stat(absFinalOutputDir); statErr != nil && !opts.DryRun {
+		if err := os.MkdirAll(absFinalOutputDir, 0755); err != nil {
+			return nil, fmt.Errorf("gagal membuat direktori output '%s': %w", absFinalOutputDir, err)
 		}
 		fmt.Printf("Direktori output '%s' berhasil dibuat.\n", absFinalOutputDir)
-	} else {
-		fmt.Printf("Direktori output '%s' sudah ada.\n", absFinalOutputDir)
 	}
 
-	// Setup untuk unduhan konkurensi
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, maxConcurrentDownloads)
-
-	// HTTP Client dari referensi Anda
-	customTransport := &http.Transport{
-		TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
-	}
-	client := &http.Client{
-		Timeout:   requestTimeout,
-		Transport: customTransport,
+	report := &Report{
+		OutputDir:  absFinalOutputDir,
+		Downloaded: []string{},
+		Skipped:    []string{},
+		Repaired:   []string{},
+		NoURL:      []string{},
+		Failed:     []DownloadFailure{},
 	}
+	manifest := Manifest{}
 
-	// Iterasi dan unduh setiap gambar
+	var (
+		wg          sync.WaitGroup
+		reportMutex sync.Mutex
+	)
+	sem := make(chan struct{}, opts.Concurrency)
 	for _, el := range elements {
+		baseFileName := sanitizeFilename(el.Name)
+		replacePath := ""
+		// report dan manifest juga ditulis goroutine unduhan yang sudah berjalan.
+		if existingBase, path, found := findExistingImage(existing, el.Name); found {
+			entry, err := inspectImageFile(path, manifestEntryFor(oldManifest, el.Name))
+			if err == nil {
+				entry.URL = el.ImageURL
+				reportMutex.Lock()
+				manifest[el.Name] = entry
+				report.Skipped = append(report.Skipped, el.Name)
+				reportMutex.Unlock()
+				continue
+			}
+			log.Printf("Gambar '%s' tidak bisa dipakai (%v), diunduh ulang.\n", path, err)
+			reportMutex.Lock()
+			report.Repaired = append(report.Repaired, el.Name)
+			reportMutex.Unlock()
+			baseFileName, replacePath = existingBase, path
+		}
 		if el.ImageURL == "" {
-			// log.Printf("Melewati elemen '%s' karena URL gambar kosong.\n", el.Name)
+			reportMutex.Lock()
+			report.NoURL = append(report.NoURL, el.Name)
+			reportMutex.Unlock()
 			continue
 		}
+		if opts.DryRun {
+			report.Planned = append(report.Planned, el.Name)
+			continue
+		}
+
 		wg.Add(1)
 		sem <- struct{}{} // Ambil slot semaphore
-		go downloadIndividualFile(el, absFinalOutputDir, client, &wg, sem)
+		go func(element Element, baseFileName, replacePath string) {
+			defer wg.Done()
+			defer func() { <-sem }() // Lepaskan slot semaphore
+
+			entry, attempts, err := downloadWithRetry(client, element, absFinalOutputDir, baseFileName, replacePath, opts.Retries, opts.InitialBackoff)
+			reportMutex.Lock()
+			defer reportMutex.Unlock()
+			if err != nil {
+				log.Printf("Gagal mengunduh '%s' (%s) setelah %d percobaan: %v\n", element.Name, element.ImageURL, attempts, err)
+				report.Failed = append(report.Failed, DownloadFailure{Name: element.Name, URL: element.ImageURL, Attempts: attempts, Error: err.Error()})
+				return
+			}
+			fmt.Printf("Berhasil mengunduh '%s' -> '%s'\n", element.Name, entry.File)
+			manifest[element.Name] = entry
+			report.Downloaded = append(report.Downloaded, element.Name)
+		}(el, baseFileName, replacePath)
+	}
+	wg.Wait()
+
+	if !opts.DryRun {
+		report.Manifest = filepath.Join(absFinalOutputDir, DefaultManifestName)
+		if err := manifest.WriteFile(report.Manifest); err != nil {
+			return report, fmt.Errorf("gagal menulis manifest '%s': %w", report.Manifest, err)
+		}
+	}
+
+	sort.Strings(report.Downloaded)
+	sort.Strings(report.Skipped)
+	sort.Strings(report.Repaired)
+	sort.Strings(report.Planned)
+	sort.Strings(report.NoURL)
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].Name < report.Failed[j].Name })
+	return report, nil
+}
+
+// InvalidImage adalah file gambar yang ada tetapi tidak bisa dipakai.
+type InvalidImage struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+// VerifyReport adalah hasil VerifyImages. Missing dan Invalid bersama-sama adalah elemen yang
+// belum punya ikon yang bisa dipakai.
+type VerifyReport struct {
+	OutputDir string         `json:"outputDir"`
+	Elements  int            `json:"elements"`
+	Usable    int            `json:"usable"`
+	Missing   []string       `json:"missing"`
+	Invalid   []InvalidImage `json:"invalid"`
+}
+
+// OK mengembalikan true jika semua elemen punya ikon yang bisa dipakai.
+func (r *VerifyReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Invalid) == 0
+}
+
+// VerifyImages mengecek folder output tanpa mengunduh atau menulis apa pun: setiap elemen di
+// dataset harus punya file SVG/PNG yang utuh dan, jika tercatat di manifest, isinya sama.
+func VerifyImages(opts Options) (*VerifyReport, error) {
+	opts = opts.normalize()
+	elements, err := readElements(opts.InputPath)
+	if err != nil {
+		return nil, err
+	}
+	absOutputDir, existing, manifest, err := readOutputDir(opts.OutputDir)
+	if err != nil {
+		return nil, err
 	}
 
-	wg.Wait() // Tunggu semua goroutine selesai
-	close(sem) // Tutup channel semaphore
+	report := &VerifyReport{OutputDir: absOutputDir, Elements: len(elements), Missing: []string{}, Invalid: []InvalidImage{}}
+	for _, el := range elements {
+		_, path, found := findExistingImage(existing, el.Name)
+		if !found {
+			report.Missing = append(report.Missing, el.Name)
+			continue
+		}
+		if _, err := inspectImageFile(path, manifestEntryFor(manifest, el.Name)); err != nil {
+			report.Invalid = append(report.Invalid, InvalidImage{Name: el.Name, File: filepath.Base(path), Reason: err.Error()})
+			continue
+		}
+		report.Usable++
+	}
+	sort.Strings(report.Missing)
+	sort.Slice(report.Invalid, func(i, j int) bool { return report.Invalid[i].Name < report.Invalid[j].Name })
+	return report, nil
+}
 
-	log.Println("Proses pengunduhan gambar selesai.")
-	fmt.Printf("Gambar seharusnya telah diunduh ke: %s\n", absFinalOutputDir)
-}
\ No newline at end of file
+// DownloadAllImages membaca file JSON dan mengunduh semua gambar elemen dengan DefaultOptions.
+func DownloadAllImages(jsonInputPath string) error {
+	log.Println("Mulai proses pengunduhan gambar...")
+	opts := DefaultOptions()
+	opts.InputPath = jsonInputPath
+	report, err := DownloadImages(opts)
+	if err != nil {
+		return err
+	}
+	log.Printf("Proses pengunduhan gambar selesai: %d diunduh, %d dilewati, %d gagal.", len(report.Downloaded), len(report.Skipped), len(report.Failed))
+	fmt.Printf("Gambar seharusnya telah diunduh ke: %s\n", report.OutputDir)
+	return nil
+}
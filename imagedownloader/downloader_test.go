@@ -0,0 +1,209 @@
+package imagedownloader
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="1" height="1"><rect width="1" height="1"/></svg>`
+	testPNG = "\x89PNG\r\n\x1a\n" + "\x00\x00\x00\x00IEND\xaeB`\x82"
+)
+
+// imageServer adalah server gambar palsu yang menghitung request per path.
+type imageServer struct {
+	*httptest.Server
+	mutex sync.Mutex
+	hits  map[string]int
+}
+
+// newImageServer menjalankan server dengan handler per path. Handler menerima nomor request
+// ke-n (mulai dari 1) untuk path tersebut.
+func newImageServer(t *testing.T, routes map[string]func(w http.ResponseWriter, n int)) *imageServer {
+	t.Helper()
+	s := &imageServer{hits: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.hits[r.URL.Path]++
+		n := s.hits[r.URL.Path]
+		s.mutex.Unlock()
+		route, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		route(w, n)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *imageServer) hitCount(path string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.hits[path]
+}
+
+func serveBody(contentType, body string) func(w http.ResponseWriter, n int) {
+	return func(w http.ResponseWriter, n int) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(body))
+	}
+}
+
+// writeInput menulis dataset input downloader ke dir dan mengembalikan path-nya.
+func writeInput(t *testing.T, dir string, elements []Element) string {
+	t.Helper()
+	data, err := json.Marshal(elements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "elements.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func testOptions(t *testing.T, server *imageServer, elements []Element) Options {
+	t.Helper()
+	dir := t.TempDir()
+	return Options{
+		InputPath:      writeInput(t, dir, elements),
+		OutputDir:      filepath.Join(dir, "images"),
+		Concurrency:    2,
+		Retries:        2,
+		InitialBackoff: time.Millisecond,
+		Client:         server.Client(),
+	}
+}
+
+func TestDownloadImagesRetryAndFailures(t *testing.T) {
+	server := newImageServer(t, map[string]func(http.ResponseWriter, int){
+		"/flaky.svg": func(w http.ResponseWriter, n int) {
+			if n <= 2 {
+				http.Error(w, "sibuk", http.StatusServiceUnavailable)
+				return
+			}
+			serveBody(ContentTypeSVG, testSVG)(w, n)
+		},
+		"/down.svg": func(w http.ResponseWriter, n int) {
+			http.Error(w, "sibuk", http.StatusServiceUnavailable)
+		},
+		"/page.svg": serveBody("text/html", "<html><body>Not an image</body></html>"),
+	})
+	elements := []Element{
+		{Name: "Flaky", ImageURL: server.URL + "/flaky.svg"},
+		{Name: "Down", ImageURL: server.URL + "/down.svg"},
+		{Name: "Page", ImageURL: server.URL + "/page.svg"},
+		{Name: "Missing", ImageURL: server.URL + "/missing.svg"},
+		{Name: "Nourl"},
+	}
+	report, err := DownloadImages(testOptions(t, server, elements))
+	if err != nil {
+		t.Fatalf("DownloadImages: %v", err)
+	}
+
+	if !reflect.DeepEqual(report.Downloaded, []string{"Flaky"}) {
+		t.Errorf("Downloaded = %v, want [Flaky]", report.Downloaded)
+	}
+	if !reflect.DeepEqual(report.NoURL, []string{"Nourl"}) {
+		t.Errorf("NoURL = %v, want [Nourl]", report.NoURL)
+	}
+	if got := server.hitCount("/flaky.svg"); got != 3 {
+		t.Errorf("request ke flaky.svg = %d, want 3 (dua 503 lalu berhasil)", got)
+	}
+
+	tests := []struct {
+		name         string
+		wantAttempts int
+		wantError    string
+	}{
+		// 5xx dicoba ulang sampai Retries habis.
+		{name: "Down", wantAttempts: 3, wantError: "503"},
+		// 404 dan isi yang bukan gambar gagal permanen, tidak dicoba ulang.
+		{name: "Missing", wantAttempts: 1, wantError: "404"},
+		{name: "Page", wantAttempts: 1, wantError: "text/html"},
+	}
+	if len(report.Failed) != len(tests) {
+		t.Fatalf("Failed = %+v, want %d entri", report.Failed, len(tests))
+	}
+	for i, tt := range tests {
+		failure := report.Failed[i]
+		if failure.Name != tt.name || failure.Attempts != tt.wantAttempts || !strings.Contains(failure.Error, tt.wantError) {
+			t.Errorf("Failed[%d] = %+v, want %s dengan %d percobaan dan error berisi %q", i, failure, tt.name, tt.wantAttempts, tt.wantError)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(report.OutputDir, "Page.svg")); !os.IsNotExist(err) {
+		t.Errorf("respons HTML tetap ditulis sebagai Page.svg (err = %v)", err)
+	}
+}
+
+func TestDownloadImagesResumesFromManifest(t *testing.T) {
+	server := newImageServer(t, map[string]func(http.ResponseWriter, int){
+		"/air.svg":   serveBody(ContentTypeSVG, testSVG),
+		"/fire.png":  serveBody(ContentTypePNG, testPNG),
+		"/water.svg": serveBody(ContentTypeSVG, testSVG),
+	})
+	elements := []Element{
+		{Name: "Air", ImageURL: server.URL + "/air.svg"},
+		{Name: "Fire", ImageURL: server.URL + "/fire.png"},
+		{Name: "Water", ImageURL: server.URL + "/water.svg"},
+	}
+	opts := testOptions(t, server, elements)
+
+	first, err := DownloadImages(opts)
+	if err != nil {
+		t.Fatalf("run pertama: %v", err)
+	}
+	if !reflect.DeepEqual(first.Downloaded, []string{"Air", "Fire", "Water"}) {
+		t.Fatalf("run pertama Downloaded = %v", first.Downloaded)
+	}
+	manifest, err := ReadManifest(first.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := manifest["Fire"]; entry.File != "Fire.png" || entry.ContentType != ContentTypePNG || entry.Size != int64(len(testPNG)) {
+		t.Errorf("manifest Fire = %+v", entry)
+	}
+
+	// Fire terpotong, Air diganti SVG lain yang valid tetapi tidak cocok dengan manifest,
+	// dan ada sisa .part dari run yang terputus.
+	if err := os.WriteFile(filepath.Join(first.OutputDir, "Fire.png"), []byte(testPNG[:12]), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(first.OutputDir, "Air.svg"), []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(first.OutputDir, "Water.svg"+partSuffix), []byte("<svg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := DownloadImages(opts)
+	if err != nil {
+		t.Fatalf("run kedua: %v", err)
+	}
+	if !reflect.DeepEqual(second.Skipped, []string{"Water"}) {
+		t.Errorf("Skipped = %v, want [Water]", second.Skipped)
+	}
+	if !reflect.DeepEqual(second.Repaired, []string{"Air", "Fire"}) || !reflect.DeepEqual(second.Downloaded, []string{"Air", "Fire"}) {
+		t.Errorf("Repaired = %v, Downloaded = %v, want [Air Fire] untuk keduanya", second.Repaired, second.Downloaded)
+	}
+	for path, want := range map[string]int{"/air.svg": 2, "/fire.png": 2, "/water.svg": 1} {
+		if got := server.hitCount(path); got != want {
+			t.Errorf("request ke %s = %d, want %d", path, got, want)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(second.OutputDir, "Fire.png"))
+	if err != nil || string(data) != testPNG {
+		t.Errorf("Fire.png tidak diperbaiki (err = %v, %d byte)", err, len(data))
+	}
+}
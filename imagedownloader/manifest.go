@@ -0,0 +1,166 @@
+package imagedownloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultManifestName adalah nama file manifest yang ditulis di folder output.
+const DefaultManifestName = "manifest.json"
+
+// Content type gambar yang diterima. Format lain dianggap tidak bisa dipakai frontend.
+const (
+	ContentTypeSVG = "image/svg+xml"
+	ContentTypePNG = "image/png"
+)
+
+// Alasan sebuah file gambar dianggap tidak bisa dipakai.
+var (
+	errImageEmpty       = errors.New("file kosong")
+	errImageTruncated   = errors.New("file terpotong")
+	errImageUnsupported = errors.New("bukan SVG atau PNG")
+	errImageMismatch    = errors.New("isi file tidak cocok dengan manifest")
+)
+
+// pngSignature dan pngTrailer adalah awal file PNG dan chunk IEND yang selalu menutupnya.
+var (
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	pngTrailer   = []byte("IEND\xaeB`\x82")
+)
+
+// ManifestEntry mencatat satu file gambar yang sudah dicek bisa dipakai.
+type ManifestEntry struct {
+	File        string `json:"file"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	ContentType string `json:"contentType"`
+	URL         string `json:"url,omitempty"`
+}
+
+// Manifest memetakan nama elemen -> file gambarnya.
+type Manifest map[string]ManifestEntry
+
+// ReadManifest membaca manifest di path. File yang belum ada menghasilkan manifest kosong.
+func ReadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest := Manifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("gagal parse manifest '%s': %w", path, err)
+	}
+	return manifest, nil
+}
+
+// WriteFile menulis manifest sebagai JSON ter-indentasi (key urut nama elemen). File ditulis
+// ke file sementara dulu agar manifest lama tidak rusak jika proses terhenti.
+func (m Manifest) WriteFile(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + partSuffix
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// sniffImage mengembalikan content type dan ekstensi data jika data adalah SVG atau PNG utuh.
+func sniffImage(data []byte) (string, string, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "", "", errImageEmpty
+	}
+	if bytes.HasPrefix(data, pngSignature) {
+		if !bytes.HasSuffix(data, pngTrailer) {
+			return "", "", errImageTruncated
+		}
+		return ContentTypePNG, ".png", nil
+	}
+	if len(pngSignature) > len(data) && bytes.HasPrefix(pngSignature, data) {
+		return "", "", errImageTruncated
+	}
+	if err := checkSVG(data); err != nil {
+		return "", "", err
+	}
+	return ContentTypeSVG, ".svg", nil
+}
+
+// checkSVG memastikan data adalah dokumen XML lengkap dengan root <svg>.
+func checkSVG(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	rootSeen := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if !rootSeen {
+				return errImageUnsupported
+			}
+			return nil
+		}
+		if err != nil {
+			if rootSeen && strings.Contains(err.Error(), "unexpected EOF") {
+				return errImageTruncated
+			}
+			return errImageUnsupported
+		}
+		if start, ok := token.(xml.StartElement); ok && !rootSeen {
+			if start.Name.Local != "svg" {
+				return errImageUnsupported
+			}
+			rootSeen = true
+		}
+	}
+}
+
+// newManifestEntry membuat entri manifest untuk isi file di path.
+func newManifestEntry(path string, data []byte, contentType, sourceURL string) ManifestEntry {
+	sum := sha256.Sum256(data)
+	return ManifestEntry{
+		File:        filepath.Base(path),
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		ContentType: contentType,
+		URL:         sourceURL,
+	}
+}
+
+// inspectImageFile membaca file gambar dan mengembalikan entri manifest-nya jika file bisa
+// dipakai. Jika expected berisi entri manifest sebelumnya, ukuran dan hash harus sama.
+func inspectImageFile(path string, expected *ManifestEntry) (ManifestEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	contentType, ext, err := sniffImage(data)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	if !strings.EqualFold(filepath.Ext(path), ext) {
+		return ManifestEntry{}, fmt.Errorf("%w: isi %s dengan ekstensi %s", errImageUnsupported, contentType, filepath.Ext(path))
+	}
+	entry := newManifestEntry(path, data, contentType, "")
+	if expected != nil && expected.File == entry.File && expected.SHA256 != "" {
+		if expected.Size > entry.Size {
+			return ManifestEntry{}, fmt.Errorf("%w: %d dari %d byte", errImageTruncated, entry.Size, expected.Size)
+		}
+		if expected.SHA256 != entry.SHA256 {
+			return ManifestEntry{}, errImageMismatch
+		}
+	}
+	return entry, nil
+}
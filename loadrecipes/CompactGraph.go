@@ -0,0 +1,220 @@
+package loadrecipes
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// ElementID adalah ID padat (dense) sebuah elemen di dalam CompactGraph.
+// ID diberikan berurutan sesuai urutan leksikografis nama elemen, sehingga
+// membandingkan dua ID sama dengan membandingkan nama elemennya.
+type ElementID uint16
+
+// MaxCompactElements adalah jumlah elemen maksimal yang bisa direpresentasikan ElementID.
+const MaxCompactElements = 1 << 16
+
+// CompactPair adalah versi ID dari PairMats. Mat1 selalu <= Mat2.
+type CompactPair struct {
+	Mat1, Mat2 ElementID
+}
+
+// ConstructCompactPair membuat CompactPair dengan urutan kanonis (Mat1 <= Mat2).
+func ConstructCompactPair(mat1, mat2 ElementID) CompactPair {
+	if mat1 <= mat2 {
+		return CompactPair{Mat1: mat1, Mat2: mat2}
+	}
+	return CompactPair{Mat1: mat2, Mat2: mat1}
+}
+
+// CompactStep adalah versi ID dari satu langkah resep: Child = Pair.Mat1 + Pair.Mat2.
+type CompactStep struct {
+	Child ElementID
+	Pair  CompactPair
+}
+
+// Bitset adalah himpunan ElementID berbasis slice uint64.
+type Bitset []uint64
+
+// NewBitset membuat Bitset yang cukup untuk n elemen.
+func NewBitset(n int) Bitset {
+	return make(Bitset, (n+63)/64)
+}
+
+func (b Bitset) Set(id ElementID) {
+	b[id/64] |= 1 << (id % 64)
+}
+
+func (b Bitset) Clear(id ElementID) {
+	b[id/64] &^= 1 << (id % 64)
+}
+
+func (b Bitset) Has(id ElementID) bool {
+	return b[id/64]&(1<<(id%64)) != 0
+}
+
+// Clone menyalin Bitset; dipakai saat state pencarian bercabang.
+func (b Bitset) Clone() Bitset {
+	c := make(Bitset, len(b))
+	copy(c, b)
+	return c
+}
+
+// Count mengembalikan jumlah ID yang ada di dalam Bitset.
+func (b Bitset) Count() int {
+	total := 0
+	for _, word := range b {
+		total += bits.OnesCount64(word)
+	}
+	return total
+}
+
+// ForEach memanggil fn untuk setiap ID di dalam Bitset, urut dari ID terkecil.
+func (b Bitset) ForEach(fn func(id ElementID)) {
+	for wordIndex, word := range b {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			fn(ElementID(wordIndex*64 + bit))
+			word &= word - 1
+		}
+	}
+}
+
+// CompactGraph adalah representasi BiGraphAlchemy yang sudah di-intern ke ElementID.
+// Dipakai oleh algoritma pencarian di hot loop; nama elemen hanya dipakai lagi
+// saat hasil dikembalikan ke pemanggil.
+type CompactGraph struct {
+	Names             []string
+	IDs               map[string]ElementID
+	ChildToParents    [][]CompactPair
+	ParentPairToChild map[CompactPair][]ElementID
+	Base              Bitset
+	BaseIDs           []ElementID
+	// HasCycles bernilai true jika ada elemen yang (lewat resepnya) bergantung pada dirinya
+	// sendiri. Dataset dengan aturan tier selalu bebas siklus; algoritma hanya perlu
+	// pengecekan siklus tambahan jika nilai ini true.
+	HasCycles bool
+	// Craftability adalah hasil analisis elemen yang bisa dibuat dari elemen dasar.
+	Craftability *Craftability
+}
+
+// NewCompactGraph membangun CompactGraph dari BiGraphAlchemy.
+func NewCompactGraph(graph *BiGraphAlchemy) (*CompactGraph, error) {
+	if len(graph.AllElements) > MaxCompactElements {
+		return nil, fmt.Errorf("jumlah elemen (%d) melebihi batas CompactGraph (%d)", len(graph.AllElements), MaxCompactElements)
+	}
+
+	names := make([]string, 0, len(graph.AllElements))
+	for name := range graph.AllElements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cg := &CompactGraph{
+		Names:             names,
+		IDs:               make(map[string]ElementID, len(names)),
+		ChildToParents:    make([][]CompactPair, len(names)),
+		ParentPairToChild: make(map[CompactPair][]ElementID, len(graph.ParentPairToChild)),
+		Base:              NewBitset(len(names)),
+	}
+	for i, name := range names {
+		cg.IDs[name] = ElementID(i)
+	}
+
+	for i, name := range names {
+		if graph.BaseElements[name] {
+			cg.Base.Set(ElementID(i))
+			cg.BaseIDs = append(cg.BaseIDs, ElementID(i))
+		}
+		for _, pair := range graph.ChildToParents[name] {
+			cg.ChildToParents[i] = append(cg.ChildToParents[i], ConstructCompactPair(cg.IDs[pair.Mat1], cg.IDs[pair.Mat2]))
+		}
+	}
+
+	for pair, children := range graph.ParentPairToChild {
+		compactPair := ConstructCompactPair(cg.IDs[pair.Mat1], cg.IDs[pair.Mat2])
+		childIDs := make([]ElementID, 0, len(children))
+		for _, child := range children {
+			childIDs = append(childIDs, cg.IDs[child])
+		}
+		cg.ParentPairToChild[compactPair] = childIDs
+	}
+	cg.HasCycles = cg.detectCycles()
+	cg.Craftability = computeCraftability(cg)
+
+	return cg, nil
+}
+
+// detectCycles mencari siklus di graf ketergantungan child -> parent dengan DFS iteratif
+// (pewarnaan putih/abu-abu/hitam).
+func (cg *CompactGraph) detectCycles() bool {
+	const (
+		white = iota
+		grey
+		black
+	)
+	type frame struct {
+		id     ElementID
+		parent int // indeks parent berikutnya yang dicek: pair ke-(parent/2), Mat1 jika genap
+	}
+	color := make([]uint8, cg.Len())
+	for start := range cg.Names {
+		if color[start] != white {
+			continue
+		}
+		stack := []frame{{id: ElementID(start)}}
+		color[start] = grey
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			pairs := cg.ChildToParents[top.id]
+			if top.parent >= 2*len(pairs) {
+				color[top.id] = black
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			pair := pairs[top.parent/2]
+			next := pair.Mat1
+			if top.parent%2 == 1 {
+				next = pair.Mat2
+			}
+			top.parent++
+			switch color[next] {
+			case grey:
+				return true
+			case white:
+				color[next] = grey
+				stack = append(stack, frame{id: next})
+			}
+		}
+	}
+	return false
+}
+
+// Len mengembalikan jumlah elemen di graf.
+func (cg *CompactGraph) Len() int {
+	return len(cg.Names)
+}
+
+// ID mencari ElementID dari nama elemen.
+func (cg *CompactGraph) ID(name string) (ElementID, bool) {
+	id, ok := cg.IDs[name]
+	return id, ok
+}
+
+// Name mengembalikan nama elemen untuk ElementID.
+func (cg *CompactGraph) Name(id ElementID) string {
+	return cg.Names[id]
+}
+
+// IsBase mengecek apakah elemen adalah elemen dasar.
+func (cg *CompactGraph) IsBase(id ElementID) bool {
+	return cg.Base.Has(id)
+}
+
+// Compact mengembalikan CompactGraph untuk graf ini. Hasilnya dibangun sekali lalu di-cache.
+func (g *BiGraphAlchemy) Compact() (*CompactGraph, error) {
+	g.compactOnce.Do(func() {
+		g.compact, g.compactErr = NewCompactGraph(g)
+	})
+	return g.compact, g.compactErr
+}
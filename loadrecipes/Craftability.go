@@ -0,0 +1,180 @@
+package loadrecipes
+
+// Craftability menyimpan hasil analisis elemen mana yang bisa dibuat dari elemen dasar,
+// dihitung sekali per CompactGraph. Tanpa aturan tier (misalnya hasil scrape mentah yang
+// dimuat dengan NoFilterPolicy) graf resep bisa bersiklus; dengan analisis ini algoritma
+// pencarian tidak perlu menelusuri siklus untuk tahu sebuah elemen tidak bisa dibuat.
+type Craftability struct {
+	// Craftable berisi elemen yang bisa dibuat dari elemen dasar (least fixpoint).
+	Craftable Bitset
+	// rank adalah urutan elemen menjadi craftable saat fixpoint dihitung; -1 jika tidak
+	// bisa dibuat. Elemen dasar mendapat rank terkecil.
+	rank []int32
+	// component adalah ID strongly connected component elemen di graf child -> parent.
+	component []int32
+}
+
+// computeCraftability menghitung Craftability dengan worklist dari elemen dasar: sebuah
+// elemen menjadi craftable saat salah satu resepnya punya kedua bahan yang craftable.
+// Kompleksitasnya linear terhadap jumlah resep.
+func computeCraftability(cg *CompactGraph) *Craftability {
+	n := cg.Len()
+	c := &Craftability{
+		Craftable: NewBitset(n),
+		rank:      make([]int32, n),
+		component: cg.stronglyConnectedComponents(),
+	}
+	for i := range c.rank {
+		c.rank[i] = -1
+	}
+
+	// missing[child][i] adalah jumlah bahan resep ke-i milik child yang belum craftable.
+	type recipeRef struct {
+		child ElementID
+		index int
+	}
+	missing := make([][]uint8, n)
+	usedIn := make([][]recipeRef, n)
+	for child, pairs := range cg.ChildToParents {
+		missing[child] = make([]uint8, len(pairs))
+		for i, pair := range pairs {
+			ref := recipeRef{child: ElementID(child), index: i}
+			if pair.Mat1 == pair.Mat2 {
+				missing[child][i] = 1
+				usedIn[pair.Mat1] = append(usedIn[pair.Mat1], ref)
+				continue
+			}
+			missing[child][i] = 2
+			usedIn[pair.Mat1] = append(usedIn[pair.Mat1], ref)
+			usedIn[pair.Mat2] = append(usedIn[pair.Mat2], ref)
+		}
+	}
+
+	var next int32
+	queue := make([]ElementID, 0, n)
+	markCraftable := func(id ElementID) {
+		c.Craftable.Set(id)
+		c.rank[id] = next
+		next++
+		queue = append(queue, id)
+	}
+	for _, id := range cg.BaseIDs {
+		markCraftable(id)
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, ref := range usedIn[id] {
+			missing[ref.child][ref.index]--
+			if missing[ref.child][ref.index] == 0 && !c.Craftable.Has(ref.child) {
+				markCraftable(ref.child)
+			}
+		}
+	}
+	return c
+}
+
+// CanMake mengecek apakah elemen bisa dibuat dari elemen dasar.
+func (c *Craftability) CanMake(id ElementID) bool {
+	return c.Craftable.Has(id)
+}
+
+// Usable mengecek apakah resep pair boleh dipakai untuk membuat child saat menyusun tree.
+// Kedua bahan harus craftable, dan bahan yang satu SCC dengan child harus punya rank lebih
+// kecil. Urutan (SCC, rank) itu menurun di setiap langkah, jadi tree yang hanya memakai resep
+// Usable tidak pernah bersiklus, dan setiap elemen craftable non-dasar punya minimal satu
+// resep Usable (resep yang membuatnya craftable di fixpoint). Di graf tanpa siklus semua SCC
+// berukuran satu, sehingga Usable sama dengan "kedua bahan craftable dan bukan child sendiri".
+//
+// Di graf bersiklus Usable sengaja tidak lengkap: rank hanya urutan penemuan di fixpoint, bukan
+// sifat sebuah tree. Misalnya Mud = Earth+Water | Clay+Air dan Clay = Earth+Fire | Mud+Fire:
+// tree "Mud = Clay+Air, Clay = Earth+Fire" dan "Clay = Mud+Fire, Mud = Earth+Water" sama-sama
+// bebas siklus, tetapi hanya satu dari dua resep silang itu yang Usable. Cek visited per path
+// akan menemukan keduanya, tetapi harus dibawa setiap algoritma di setiap state; dengan Usable
+// cukup cek lokal per resep. Dataset dengan aturan tier tidak terpengaruh.
+func (c *Craftability) Usable(child ElementID, pair CompactPair) bool {
+	for _, parent := range [2]ElementID{pair.Mat1, pair.Mat2} {
+		if !c.Craftable.Has(parent) {
+			return false
+		}
+		if c.component[parent] == c.component[child] && c.rank[parent] >= c.rank[child] {
+			return false
+		}
+	}
+	return true
+}
+
+// stronglyConnectedComponents menghitung SCC graf child -> parent dengan algoritma Tarjan
+// iteratif dan mengembalikan ID komponen setiap elemen.
+func (cg *CompactGraph) stronglyConnectedComponents() []int32 {
+	type frame struct {
+		id     ElementID
+		parent int // indeks parent berikutnya, sama seperti di detectCycles
+	}
+	n := cg.Len()
+	index := make([]int32, n)
+	lowLink := make([]int32, n)
+	onStack := NewBitset(n)
+	component := make([]int32, n)
+	for i := range index {
+		index[i] = -1
+	}
+
+	var nextIndex, nextComponent int32
+	var sccStack []ElementID
+	for start := range cg.Names {
+		if index[start] != -1 {
+			continue
+		}
+		visit := func(id ElementID) {
+			index[id] = nextIndex
+			lowLink[id] = nextIndex
+			nextIndex++
+			sccStack = append(sccStack, id)
+			onStack.Set(id)
+		}
+		visit(ElementID(start))
+		stack := []frame{{id: ElementID(start)}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			pairs := cg.ChildToParents[top.id]
+			if top.parent < 2*len(pairs) {
+				pair := pairs[top.parent/2]
+				next := pair.Mat1
+				if top.parent%2 == 1 {
+					next = pair.Mat2
+				}
+				top.parent++
+				switch {
+				case index[next] == -1:
+					visit(next)
+					stack = append(stack, frame{id: next})
+				case onStack.Has(next):
+					lowLink[top.id] = min(lowLink[top.id], index[next])
+				}
+				continue
+			}
+
+			id := top.id
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				caller := stack[len(stack)-1].id
+				lowLink[caller] = min(lowLink[caller], lowLink[id])
+			}
+			if lowLink[id] != index[id] {
+				continue
+			}
+			for {
+				member := sccStack[len(sccStack)-1]
+				sccStack = sccStack[:len(sccStack)-1]
+				onStack.Clear(member)
+				component[member] = nextComponent
+				if member == id {
+					break
+				}
+			}
+			nextComponent++
+		}
+	}
+	return component
+}
@@ -0,0 +1,149 @@
+package loadrecipes
+
+import (
+	"strings"
+	"testing"
+)
+
+// cyclicDataset: Mud, Brick, dan Wall saling bergantung tetapi bisa dibuat lewat Earth+Water;
+// Steam dan Cloud hanya bisa dibuat dari satu sama lain, Ghost hanya dari dirinya sendiri.
+const cyclicDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"], ["Brick", "Water"]]},
+	{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"], ["Wall", "Fire"]]},
+	{"name": "Wall", "tier": 3, "recipes": [["Brick", "Brick"]]},
+	{"name": "Steam", "tier": 1, "recipes": [["Cloud", "Fire"]]},
+	{"name": "Cloud", "tier": 1, "recipes": [["Steam", "Air"]]},
+	{"name": "Ghost", "tier": 1, "recipes": [["Ghost", "Air"]]}
+]`
+
+func loadCyclicCompact(t *testing.T) *CompactGraph {
+	t.Helper()
+	graph, err := LoadBiGraphFromReader(strings.NewReader(cyclicDataset), "cyclic", LoadOptions{Policy: NoFilterPolicy})
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	cg, err := graph.Compact()
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if !cg.HasCycles {
+		t.Fatal("dataset uji seharusnya bersiklus")
+	}
+	return cg
+}
+
+func TestCraftabilityCanMake(t *testing.T) {
+	cg := loadCyclicCompact(t)
+	tests := []struct {
+		element string
+		want    bool
+	}{
+		{"Air", true},
+		{"Mud", true},
+		{"Brick", true},
+		{"Wall", true},
+		{"Steam", false},
+		{"Cloud", false},
+		{"Ghost", false},
+	}
+	for _, tt := range tests {
+		id, _ := cg.ID(tt.element)
+		if got := cg.Craftability.CanMake(id); got != tt.want {
+			t.Errorf("CanMake(%s) = %v, want %v", tt.element, got, tt.want)
+		}
+	}
+}
+
+func TestCraftabilityUsable(t *testing.T) {
+	cg := loadCyclicCompact(t)
+	pair := func(a, b string) CompactPair {
+		idA, _ := cg.ID(a)
+		idB, _ := cg.ID(b)
+		return ConstructCompactPair(idA, idB)
+	}
+	tests := []struct {
+		child  string
+		pair   CompactPair
+		want   bool
+		reason string
+	}{
+		{"Mud", pair("Earth", "Water"), true, "bahan dasar"},
+		{"Mud", pair("Brick", "Water"), false, "Brick menjadi craftable setelah Mud"},
+		{"Brick", pair("Mud", "Fire"), true, "Mud menjadi craftable lebih dulu"},
+		{"Brick", pair("Wall", "Fire"), false, "Wall menjadi craftable setelah Brick"},
+		{"Wall", pair("Brick", "Brick"), true, "Brick menjadi craftable lebih dulu"},
+		{"Steam", pair("Cloud", "Fire"), false, "Cloud tidak bisa dibuat"},
+		{"Ghost", pair("Ghost", "Air"), false, "resep memakai dirinya sendiri"},
+	}
+	for _, tt := range tests {
+		child, _ := cg.ID(tt.child)
+		if got := cg.Craftability.Usable(child, tt.pair); got != tt.want {
+			t.Errorf("Usable(%s <- %s+%s) = %v, want %v (%s)", tt.child, cg.Name(tt.pair.Mat1), cg.Name(tt.pair.Mat2), got, tt.want, tt.reason)
+		}
+	}
+}
+
+// TestCraftabilityUsableIsIncompleteOnCycles mendokumentasikan batasan Usable: Mud dan Clay
+// satu SCC, dan kedua tree yang memakai salah satu resep silang bebas siklus, tetapi hanya
+// resep silang milik elemen dengan rank lebih besar yang Usable.
+func TestCraftabilityUsableIsIncompleteOnCycles(t *testing.T) {
+	graph, err := LoadBiGraphFromReader(strings.NewReader(`[
+		{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"], ["Clay", "Air"]]},
+		{"name": "Clay", "tier": 1, "recipes": [["Earth", "Fire"], ["Mud", "Fire"]]}
+	]`), "cross", LoadOptions{Policy: NoFilterPolicy})
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	cg, err := graph.Compact()
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	id := func(name string) ElementID {
+		id, _ := cg.ID(name)
+		return id
+	}
+	mud, clay := id("Mud"), id("Clay")
+	if !cg.HasCycles || cg.Craftability.component[mud] != cg.Craftability.component[clay] {
+		t.Fatal("Mud dan Clay seharusnya satu SCC")
+	}
+
+	for _, child := range []ElementID{mud, clay} {
+		for _, pair := range cg.ChildToParents[child] {
+			if pair.Mat1 != mud && pair.Mat1 != clay && pair.Mat2 != mud && pair.Mat2 != clay && !cg.Craftability.Usable(child, pair) {
+				t.Errorf("resep dasar %s+%s untuk %s seharusnya Usable", cg.Name(pair.Mat1), cg.Name(pair.Mat2), cg.Name(child))
+			}
+		}
+	}
+	mudFromClay := cg.Craftability.Usable(mud, ConstructCompactPair(clay, id("Air")))
+	clayFromMud := cg.Craftability.Usable(clay, ConstructCompactPair(mud, id("Fire")))
+	if mudFromClay == clayFromMud {
+		t.Errorf("Usable(Mud <- Clay+Air) = %v, Usable(Clay <- Mud+Fire) = %v; want tepat satu true", mudFromClay, clayFromMud)
+	}
+	later, crossUsable := mud, mudFromClay
+	if cg.Craftability.rank[clay] > cg.Craftability.rank[mud] {
+		later, crossUsable = clay, clayFromMud
+	}
+	if !crossUsable {
+		t.Errorf("resep silang untuk %s (rank lebih besar) seharusnya Usable", cg.Name(later))
+	}
+}
+
+func TestCraftabilityAcyclicUsesEveryCraftableRecipe(t *testing.T) {
+	graph, err := LoadBiGraphFromReader(strings.NewReader(`[
+		{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+		{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"], ["Mud", "Air"]]}
+	]`), "acyclic", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	cg, err := graph.Compact()
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	brick, _ := cg.ID("Brick")
+	for _, pair := range cg.ChildToParents[brick] {
+		if !cg.Craftability.Usable(brick, pair) {
+			t.Errorf("resep %s+%s untuk Brick seharusnya Usable", cg.Name(pair.Mat1), cg.Name(pair.Mat2))
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package loadrecipes
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format export yang didukung WriteExport.
+const (
+	ExportGraphML  = "graphml"
+	ExportDOT      = "dot"
+	ExportCSVNodes = "csv-nodes"
+	ExportCSVEdges = "csv-edges"
+)
+
+// ExportFormats adalah semua format yang bisa dipakai di WriteExport.
+var ExportFormats = []string{ExportGraphML, ExportDOT, ExportCSVNodes, ExportCSVEdges}
+
+// Jenis node dan peran edge di graf hasil export.
+const (
+	exportKindElement = "element"
+	exportKindRecipe  = "recipe"
+	exportRoleInput   = "ingredient"
+	exportRoleOutput  = "product"
+)
+
+// UnknownExportFormatError dikembalikan WriteExport untuk format yang tidak dikenal.
+type UnknownExportFormatError struct {
+	Format string
+}
+
+func (e *UnknownExportFormatError) Error() string {
+	return fmt.Sprintf("format export '%s' tidak dikenal (pilihan: %s)", e.Format, strings.Join(ExportFormats, ", "))
+}
+
+// exportNode adalah satu node di graf export: elemen, atau node AND untuk satu resep.
+type exportNode struct {
+	ID    string
+	Label string
+	Kind  string
+	Tier  int
+	Image string
+	Pack  string
+	Base  bool
+}
+
+// exportEdge menghubungkan parent -> node resep (ingredient) atau node resep -> child (product).
+type exportEdge struct {
+	Source string
+	Target string
+	Role   string
+}
+
+// exportRecipeID membuat ID node resep yang stabil dan unik per (child, pasangan parent).
+func exportRecipeID(child string, pair PairMats) string {
+	return "recipe:" + pair.Mat1 + "+" + pair.Mat2 + "=" + child
+}
+
+// exportGraph mengubah graf menjadi node dan edge eksplisit. Setiap resep menjadi satu node
+// AND dengan dua edge ingredient (dua edge dari parent yang sama jika resepnya X + X) dan satu
+// edge product. Node dan edge diurutkan berdasarkan nama agar output deterministik.
+func (g *BiGraphAlchemy) exportGraph() ([]exportNode, []exportEdge) {
+	names := make([]string, 0, len(g.AllElements))
+	for name := range g.AllElements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nodes := make([]exportNode, 0, len(names))
+	for _, name := range names {
+		nodes = append(nodes, exportNode{
+			ID:    name,
+			Label: name,
+			Kind:  exportKindElement,
+			Tier:  g.Tiers[name],
+			Image: g.Images[name],
+			Pack:  g.Packs[name],
+			Base:  g.BaseElements[name],
+		})
+	}
+
+	var edges []exportEdge
+	for _, child := range names {
+		for _, pair := range g.ChildToParents[child] {
+			recipeID := exportRecipeID(child, pair)
+			nodes = append(nodes, exportNode{
+				ID:    recipeID,
+				Label: pair.Mat1 + " + " + pair.Mat2,
+				Kind:  exportKindRecipe,
+				Tier:  g.Tiers[child],
+				Pack:  g.Packs[child],
+			})
+			edges = append(edges,
+				exportEdge{Source: pair.Mat1, Target: recipeID, Role: exportRoleInput},
+				exportEdge{Source: pair.Mat2, Target: recipeID, Role: exportRoleInput},
+				exportEdge{Source: recipeID, Target: child, Role: exportRoleOutput},
+			)
+		}
+	}
+	return nodes, edges
+}
+
+// WriteExport menulis graf dalam salah satu ExportFormats.
+func WriteExport(w io.Writer, g *BiGraphAlchemy, format string) error {
+	switch format {
+	case ExportGraphML:
+		return WriteGraphML(w, g)
+	case ExportDOT:
+		return WriteDOT(w, g)
+	case ExportCSVNodes:
+		return WriteCSVNodes(w, g)
+	case ExportCSVEdges:
+		return WriteCSVEdges(w, g)
+	}
+	return &UnknownExportFormatError{Format: format}
+}
+
+// WriteGraphML menulis graf sebagai GraphML (bisa dibuka di Gephi, networkx.read_graphml, yEd).
+func WriteGraphML(w io.Writer, g *BiGraphAlchemy) error {
+	nodes, edges := g.exportGraph()
+	bw := bufio.NewWriter(w)
+	escape := func(text string) string {
+		var b strings.Builder
+		xml.EscapeText(&b, []byte(text))
+		return b.String()
+	}
+
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(bw, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(bw, `  <key id="label" for="node" attr.name="label" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="kind" for="node" attr.name="kind" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="tier" for="node" attr.name="tier" attr.type="int"/>`)
+	fmt.Fprintln(bw, `  <key id="image" for="node" attr.name="image" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="pack" for="node" attr.name="pack" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="base" for="node" attr.name="base" attr.type="boolean"/>`)
+	fmt.Fprintln(bw, `  <key id="role" for="edge" attr.name="role" attr.type="string"/>`)
+	fmt.Fprintf(bw, "  <graph id=\"%s\" edgedefault=\"directed\">\n", escape("alchemy-"+g.Version))
+	for _, node := range nodes {
+		fmt.Fprintf(bw, "    <node id=\"%s\">", escape(node.ID))
+		fmt.Fprintf(bw, "<data key=\"label\">%s</data><data key=\"kind\">%s</data><data key=\"tier\">%d</data>", escape(node.Label), node.Kind, node.Tier)
+		if node.Image != "" {
+			fmt.Fprintf(bw, "<data key=\"image\">%s</data>", escape(node.Image))
+		}
+		if node.Pack != "" {
+			fmt.Fprintf(bw, "<data key=\"pack\">%s</data>", escape(node.Pack))
+		}
+		fmt.Fprintf(bw, "<data key=\"base\">%t</data></node>\n", node.Base)
+	}
+	for i, edge := range edges {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\"><data key=\"role\">%s</data></edge>\n",
+			i, escape(edge.Source), escape(edge.Target), edge.Role)
+	}
+	fmt.Fprintln(bw, "  </graph>")
+	fmt.Fprintln(bw, "</graphml>")
+	return bw.Flush()
+}
+
+// WriteDOT menulis graf sebagai Graphviz DOT. Node elemen berbentuk ellipse, node resep
+// berbentuk titik (AND); tier, image, pack, dan base ditulis sebagai atribut node.
+func WriteDOT(w io.Writer, g *BiGraphAlchemy) error {
+	nodes, edges := g.exportGraph()
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph alchemy {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	for _, node := range nodes {
+		attrs := []string{
+			"label=" + strconv.Quote(node.Label),
+			"kind=" + strconv.Quote(node.Kind),
+			"tier=" + strconv.Itoa(node.Tier),
+		}
+		if node.Kind == exportKindRecipe {
+			attrs = append(attrs, "shape=point")
+		} else {
+			attrs = append(attrs, "shape=ellipse", "base="+strconv.FormatBool(node.Base))
+		}
+		if node.Image != "" {
+			attrs = append(attrs, "image_url="+strconv.Quote(node.Image))
+		}
+		if node.Pack != "" {
+			attrs = append(attrs, "pack="+strconv.Quote(node.Pack))
+		}
+		fmt.Fprintf(bw, "  %s [%s];\n", strconv.Quote(node.ID), strings.Join(attrs, ", "))
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(bw, "  %s -> %s [role=%s];\n", strconv.Quote(edge.Source), strconv.Quote(edge.Target), strconv.Quote(edge.Role))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteCSVNodes menulis tabel node (kolom Id, Label, Kind, Tier, Image, Pack, Base) dengan
+// nama kolom yang dikenali import "Nodes table" Gephi.
+func WriteCSVNodes(w io.Writer, g *BiGraphAlchemy) error {
+	nodes, _ := g.exportGraph()
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Id", "Label", "Kind", "Tier", "Image", "Pack", "Base"})
+	for _, node := range nodes {
+		cw.Write([]string{node.ID, node.Label, node.Kind, strconv.Itoa(node.Tier), node.Image, node.Pack, strconv.FormatBool(node.Base)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCSVEdges menulis edge list (kolom Source, Target, Role, Type) untuk import "Edges table"
+// Gephi atau networkx.from_pandas_edgelist.
+func WriteCSVEdges(w io.Writer, g *BiGraphAlchemy) error {
+	_, edges := g.exportGraph()
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Source", "Target", "Role", "Type"})
+	for _, edge := range edges {
+		cw.Write([]string{edge.Source, edge.Target, edge.Role, "Directed"})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// graphMLData adalah satu elemen <data> di node atau edge GraphML.
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLDocument adalah bagian dokumen GraphML yang ditulis WriteGraphML.
+type graphMLDocument struct {
+	Graph struct {
+		ID    string `xml:"id,attr"`
+		Nodes []struct {
+			ID   string        `xml:"id,attr"`
+			Data []graphMLData `xml:"data"`
+		} `xml:"node"`
+		Edges []struct {
+			Source string        `xml:"source,attr"`
+			Target string        `xml:"target,attr"`
+			Data   []graphMLData `xml:"data"`
+		} `xml:"edge"`
+	} `xml:"graph"`
+}
+
+// graphMLValues mengubah daftar <data> menjadi map key -> nilai.
+func graphMLValues(data []graphMLData) map[string]string {
+	values := make(map[string]string, len(data))
+	for _, d := range data {
+		values[d.Key] = d.Value
+	}
+	return values
+}
+
+// ReadGraphML membaca kembali graf yang ditulis WriteGraphML. Semua elemen mendapat entri Tiers,
+// termasuk elemen dasar yang tidak ada di file dataset (tier 0), dan Policy tidak ikut di-export
+// sehingga bernilai kosong.
+func ReadGraphML(r io.Reader) (*BiGraphAlchemy, error) {
+	var doc graphMLDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("gagal parse GraphML: %w", err)
+	}
+
+	g := &BiGraphAlchemy{
+		ChildToParents:    make(map[string][]PairMats),
+		ParentPairToChild: make(map[PairMats][]string),
+		BaseElements:      make(map[string]bool),
+		AllElements:       make(map[string]bool),
+		Tiers:             make(map[string]int),
+		Packs:             make(map[string]string),
+		Images:            make(map[string]string),
+		Version:           strings.TrimPrefix(doc.Graph.ID, "alchemy-"),
+	}
+	recipes := make(map[string]bool)
+	for _, node := range doc.Graph.Nodes {
+		values := graphMLValues(node.Data)
+		switch values["kind"] {
+		case exportKindRecipe:
+			recipes[node.ID] = true
+		case exportKindElement:
+			g.AllElements[node.ID] = true
+			tier, err := strconv.Atoi(values["tier"])
+			if err != nil {
+				return nil, fmt.Errorf("tier node '%s' tidak valid: %w", node.ID, err)
+			}
+			g.Tiers[node.ID] = tier
+			if values["base"] == "true" {
+				g.BaseElements[node.ID] = true
+			}
+			if image := values["image"]; image != "" {
+				g.Images[node.ID] = image
+			}
+			if pack := values["pack"]; pack != "" {
+				g.Packs[node.ID] = pack
+			}
+		default:
+			return nil, fmt.Errorf("node '%s' memiliki kind '%s' yang tidak dikenal", node.ID, values["kind"])
+		}
+	}
+
+	// Edge dikelompokkan per node resep; urutan edge mengikuti urutan resep saat export.
+	ingredients := make(map[string][]string)
+	var order []string
+	products := make(map[string]string)
+	for _, edge := range doc.Graph.Edges {
+		switch graphMLValues(edge.Data)["role"] {
+		case exportRoleInput:
+			if !recipes[edge.Target] {
+				return nil, fmt.Errorf("edge ingredient '%s' -> '%s' tidak menuju node resep", edge.Source, edge.Target)
+			}
+			ingredients[edge.Target] = append(ingredients[edge.Target], edge.Source)
+		case exportRoleOutput:
+			if !recipes[edge.Source] {
+				return nil, fmt.Errorf("edge product '%s' -> '%s' tidak berasal dari node resep", edge.Source, edge.Target)
+			}
+			products[edge.Source] = edge.Target
+			order = append(order, edge.Source)
+		}
+	}
+	for _, recipeID := range order {
+		parents, child := ingredients[recipeID], products[recipeID]
+		if len(parents) != 2 {
+			return nil, fmt.Errorf("resep '%s' memiliki %d bahan, want 2", recipeID, len(parents))
+		}
+		pair := ConstructPair(parents[0], parents[1])
+		g.ChildToParents[child] = append(g.ChildToParents[child], pair)
+		g.ParentPairToChild[pair] = append(g.ParentPairToChild[pair], child)
+	}
+	for pair := range g.ParentPairToChild {
+		sort.Strings(g.ParentPairToChild[pair])
+	}
+	return g, nil
+}
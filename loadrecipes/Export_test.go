@@ -0,0 +1,198 @@
+package loadrecipes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// exportDataset memuat resep X + X, elemen dengan dua resep, pack, URL gambar, dan nama yang
+// harus di-escape di XML maupun CSV.
+const exportDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]], "imageUrl": "https://example.com/mud.svg?v=1&s=2"},
+	{"name": "Steam", "tier": 1, "recipes": [["Fire", "Water"]]},
+	{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"], ["Mud", "Air"]], "pack": "Builder"},
+	{"name": "Wall", "tier": 3, "recipes": [["Brick", "Brick"]], "pack": "Builder"},
+	{"name": "Rock & Roll", "tier": 4, "recipes": [["Wall", "Steam"]], "pack": "Music, \"Live\""}
+]`
+
+// assertSameGraph membandingkan semua data graf yang ikut di-export. Tier dibandingkan per
+// elemen karena elemen tanpa tier ter-export sebagai tier 0.
+func assertSameGraph(t *testing.T, got, want *BiGraphAlchemy) {
+	t.Helper()
+	for name := range want.AllElements {
+		if got.Tiers[name] != want.Tiers[name] {
+			t.Errorf("Tiers[%s] = %d, want %d", name, got.Tiers[name], want.Tiers[name])
+		}
+	}
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"AllElements", got.AllElements, want.AllElements},
+		{"BaseElements", got.BaseElements, want.BaseElements},
+		{"Images", got.Images, want.Images},
+		{"Packs", got.Packs, want.Packs},
+		{"ChildToParents", got.ChildToParents, want.ChildToParents},
+		{"ParentPairToChild", got.ParentPairToChild, want.ParentPairToChild},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+// readCSVExport menyusun ulang graf dari output WriteCSVNodes dan WriteCSVEdges.
+func readCSVExport(t *testing.T, nodesCSV, edgesCSV []byte) *BiGraphAlchemy {
+	t.Helper()
+	nodes, err := csv.NewReader(bytes.NewReader(nodesCSV)).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV node: %v", err)
+	}
+	edges, err := csv.NewReader(bytes.NewReader(edgesCSV)).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV edge: %v", err)
+	}
+
+	g := &BiGraphAlchemy{
+		ChildToParents:    make(map[string][]PairMats),
+		ParentPairToChild: make(map[PairMats][]string),
+		BaseElements:      make(map[string]bool),
+		AllElements:       make(map[string]bool),
+		Tiers:             make(map[string]int),
+		Packs:             make(map[string]string),
+		Images:            make(map[string]string),
+	}
+	// Kolom: Id, Label, Kind, Tier, Image, Pack, Base.
+	for _, row := range nodes[1:] {
+		if row[2] != exportKindElement {
+			continue
+		}
+		name := row[0]
+		tier, err := strconv.Atoi(row[3])
+		if err != nil {
+			t.Fatalf("tier %s = %q: %v", name, row[3], err)
+		}
+		g.AllElements[name] = true
+		g.Tiers[name] = tier
+		if row[6] == "true" {
+			g.BaseElements[name] = true
+		}
+		if row[4] != "" {
+			g.Images[name] = row[4]
+		}
+		if row[5] != "" {
+			g.Packs[name] = row[5]
+		}
+	}
+	// Kolom: Source, Target, Role, Type. Setiap resep ditulis sebagai dua ingredient lalu product.
+	var parents []string
+	for _, row := range edges[1:] {
+		switch row[2] {
+		case exportRoleInput:
+			parents = append(parents, row[0])
+		case exportRoleOutput:
+			if len(parents) != 2 {
+				t.Fatalf("resep %s memiliki %d bahan, want 2", row[0], len(parents))
+			}
+			pair := ConstructPair(parents[0], parents[1])
+			g.ChildToParents[row[1]] = append(g.ChildToParents[row[1]], pair)
+			g.ParentPairToChild[pair] = append(g.ParentPairToChild[pair], row[1])
+			parents = nil
+		}
+	}
+	return g
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	graph, err := LoadBiGraphFromReader(strings.NewReader(exportDataset), "export", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+
+	t.Run(ExportGraphML, func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteExport(&buf, graph, ExportGraphML); err != nil {
+			t.Fatalf("WriteExport: %v", err)
+		}
+		got, err := ReadGraphML(&buf)
+		if err != nil {
+			t.Fatalf("ReadGraphML: %v", err)
+		}
+		if got.Version != graph.Version {
+			t.Errorf("Version = %q, want %q", got.Version, graph.Version)
+		}
+		assertSameGraph(t, got, graph)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var nodes, edges bytes.Buffer
+		if err := WriteExport(&nodes, graph, ExportCSVNodes); err != nil {
+			t.Fatalf("WriteExport nodes: %v", err)
+		}
+		if err := WriteExport(&edges, graph, ExportCSVEdges); err != nil {
+			t.Fatalf("WriteExport edges: %v", err)
+		}
+		assertSameGraph(t, readCSVExport(t, nodes.Bytes(), edges.Bytes()), graph)
+	})
+}
+
+func TestExportRoundTripBundledDataset(t *testing.T) {
+	data, err := os.ReadFile("../elements_filtered.json")
+	if err != nil {
+		t.Skipf("dataset tidak tersedia: %v", err)
+	}
+	graph, err := LoadBiGraphFromReader(bytes.NewReader(data), "elements_filtered.json", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, graph); err != nil {
+		t.Fatalf("WriteGraphML: %v", err)
+	}
+	got, err := ReadGraphML(&buf)
+	if err != nil {
+		t.Fatalf("ReadGraphML: %v", err)
+	}
+	assertSameGraph(t, got, graph)
+}
+
+func TestReadGraphMLRejectsBrokenRecipe(t *testing.T) {
+	tests := []struct {
+		name    string
+		graphML string
+	}{
+		{
+			name:    "bukan XML",
+			graphML: "alchemy",
+		},
+		{
+			name: "resep dengan satu bahan",
+			graphML: `<graphml><graph id="alchemy-x">
+				<node id="Fire"><data key="kind">element</data><data key="tier">0</data><data key="base">true</data></node>
+				<node id="Ash"><data key="kind">element</data><data key="tier">1</data><data key="base">false</data></node>
+				<node id="recipe:Fire+Fire=Ash"><data key="kind">recipe</data><data key="tier">1</data></node>
+				<edge source="Fire" target="recipe:Fire+Fire=Ash"><data key="role">ingredient</data></edge>
+				<edge source="recipe:Fire+Fire=Ash" target="Ash"><data key="role">product</data></edge>
+			</graph></graphml>`,
+		},
+		{
+			name: "kind tidak dikenal",
+			graphML: `<graphml><graph id="alchemy-x">
+				<node id="Fire"><data key="kind">spell</data><data key="tier">0</data></node>
+			</graph></graphml>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadGraphML(strings.NewReader(tt.graphML)); err == nil {
+				t.Error("ReadGraphML seharusnya error")
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package loadrecipes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FilterPolicy menentukan resep mana yang dibuang saat dataset dimuat. Dengan policy ini
+// hasil scraping mentah (tanpa filter) bisa dimuat apa adanya, atau difilter per resep.
+type FilterPolicy struct {
+	// TierRule hanya menyimpan resep yang tier kedua parent-nya lebih kecil dari tier child.
+	// Parent tanpa tier dianggap melanggar aturan ini, kecuali elemen dasar.
+	TierRule bool `json:"tierRule"`
+	// DropUnknownParents membuang resep yang parent-nya tidak terdaftar sebagai elemen.
+	DropUnknownParents bool `json:"dropUnknownParents"`
+	// DropSelfCombinations membuang resep yang memakai child itu sendiri sebagai parent.
+	DropSelfCombinations bool `json:"dropSelfCombinations"`
+}
+
+// DefaultFilterPolicy memakai aturan per resep yang sama dengan scraper (tier dan parent tidak
+// dikenal), sehingga dataset yang sudah difilter dimuat tanpa perubahan. Berbeda dengan
+// scrape.FilterElements, loader tidak menghapus elemen yang kehabisan resep secara iteratif:
+// elemen itu tetap ada tanpa resep, begitu juga resep yang memakainya. Resep tersebut tidak
+// pernah dipakai pencarian karena Craftability menandai bahannya tidak bisa dibuat, jadi
+// bagian graf yang bisa dibuat sama dengan dataset hasil filter scraper.
+var DefaultFilterPolicy = FilterPolicy{TierRule: true, DropUnknownParents: true, DropSelfCombinations: true}
+
+// NoFilterPolicy memuat semua resep apa adanya. Graf hasilnya bisa mengandung siklus.
+var NoFilterPolicy = FilterPolicy{}
+
+// String mengembalikan nama aturan yang aktif dipisah koma, atau "none".
+func (p FilterPolicy) String() string {
+	var rules []string
+	if p.TierRule {
+		rules = append(rules, "tier")
+	}
+	if p.DropUnknownParents {
+		rules = append(rules, "unknown")
+	}
+	if p.DropSelfCombinations {
+		rules = append(rules, "self")
+	}
+	if len(rules) == 0 {
+		return "none"
+	}
+	return strings.Join(rules, ",")
+}
+
+// ParseFilterPolicy membaca policy dari daftar aturan dipisah koma ("tier,unknown,self"),
+// "none", atau "default".
+func ParseFilterPolicy(text string) (FilterPolicy, error) {
+	var policy FilterPolicy
+	switch strings.TrimSpace(text) {
+	case "", "default":
+		return DefaultFilterPolicy, nil
+	case "none":
+		return policy, nil
+	}
+	for _, rule := range strings.Split(text, ",") {
+		switch strings.TrimSpace(rule) {
+		case "tier":
+			policy.TierRule = true
+		case "unknown":
+			policy.DropUnknownParents = true
+		case "self":
+			policy.DropSelfCombinations = true
+		default:
+			return policy, &UnknownFilterRuleError{Rule: rule}
+		}
+	}
+	return policy, nil
+}
+
+// UnknownFilterRuleError dikembalikan ParseFilterPolicy untuk nama aturan yang tidak dikenal.
+type UnknownFilterRuleError struct {
+	Rule string
+}
+
+func (e *UnknownFilterRuleError) Error() string {
+	return fmt.Sprintf("aturan filter '%s' tidak dikenal (pilihan: tier, unknown, self, none, default)", e.Rule)
+}
+
+// Aturan yang bisa menolak resep saat dataset dimuat. Tiga yang pertama sama dengan nama
+// aturan di FilterPolicy.String dan ParseFilterPolicy.
+const (
+	RuleTier    = "tier"
+	RuleUnknown = "unknown"
+	RuleSelf    = "self"
+	// RuleMalformed: resep bukan pasangan dua parent; selalu ditolak apa pun policy-nya.
+	RuleMalformed = "malformed"
+)
+
+// RejectedRecipe adalah satu resep yang tidak dimasukkan loader ke graf.
+type RejectedRecipe struct {
+	Element string   `json:"element"`
+	Recipe  []string `json:"recipe"`
+	Rule    string   `json:"rule"`
+}
+
+// FilterReport mencatat apa yang dibuang loader saat memuat dataset dengan sebuah FilterPolicy.
+type FilterReport struct {
+	Policy          string           `json:"policy"`
+	RejectedRecipes []RejectedRecipe `json:"rejectedRecipes"`
+	// ElementsWithoutRecipes berisi elemen non-dasar yang tidak punya resep tersisa. Elemen ini
+	// tetap dimuat, tetapi tidak bisa dibuat (lihat DefaultFilterPolicy).
+	ElementsWithoutRecipes []string `json:"elementsWithoutRecipes"`
+}
+
+// countByRule mengembalikan jumlah resep yang ditolak per aturan.
+func (r *FilterReport) countByRule() map[string]int {
+	counts := make(map[string]int)
+	for _, rejected := range r.RejectedRecipes {
+		counts[rejected.Rule]++
+	}
+	return counts
+}
+
+// WriteJSON menulis laporan sebagai JSON ter-indentasi.
+func (r *FilterReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(r)
+}
+
+// recipeRejection mengembalikan nama aturan yang menolak resep, atau "" jika resep lolos.
+func (p FilterPolicy) recipeRejection(child, parent1, parent2 string, known, base map[string]bool, tiers map[string]int) string {
+	if p.DropSelfCombinations && (parent1 == child || parent2 == child) {
+		return RuleSelf
+	}
+	if p.DropUnknownParents && (!known[parent1] || !known[parent2]) {
+		return RuleUnknown
+	}
+	if p.TierRule {
+		childTier := tiers[child]
+		for _, parent := range [2]string{parent1, parent2} {
+			parentTier, ok := tiers[parent]
+			if !ok && base[parent] {
+				continue
+			}
+			if !ok || parentTier >= childTier {
+				return RuleTier
+			}
+		}
+	}
+	return ""
+}
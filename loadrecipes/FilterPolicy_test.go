@@ -0,0 +1,145 @@
+package loadrecipes
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// policyDataset punya satu resep untuk setiap aturan: Mud + Water (self), Slime + Water
+// (unknown), dan Wall + Fire untuk Brick (tier, Wall lebih tinggi dari Brick).
+const policyDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"], ["Mud", "Water"], ["Slime", "Water"]]},
+	{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"], ["Wall", "Fire"]]},
+	{"name": "Wall", "tier": 3, "recipes": [["Brick", "Brick"]]}
+]`
+
+func TestParseFilterPolicy(t *testing.T) {
+	tests := []struct {
+		text string
+		want FilterPolicy
+	}{
+		{text: "", want: DefaultFilterPolicy},
+		{text: "default", want: DefaultFilterPolicy},
+		{text: "none", want: NoFilterPolicy},
+		{text: "tier", want: FilterPolicy{TierRule: true}},
+		{text: "self, unknown", want: FilterPolicy{DropUnknownParents: true, DropSelfCombinations: true}},
+		{text: "tier,unknown,self", want: DefaultFilterPolicy},
+	}
+	for _, tt := range tests {
+		got, err := ParseFilterPolicy(tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseFilterPolicy(%q) = %+v, %v; want %+v", tt.text, got, err, tt.want)
+		}
+		if roundTrip, err := ParseFilterPolicy(got.String()); err != nil || roundTrip != got {
+			t.Errorf("ParseFilterPolicy(%q) = %+v, %v; want %+v", got.String(), roundTrip, err, got)
+		}
+	}
+
+	var unknown *UnknownFilterRuleError
+	if _, err := ParseFilterPolicy("tier,cycles"); !errors.As(err, &unknown) || unknown.Rule != "cycles" {
+		t.Errorf("ParseFilterPolicy(tier,cycles) error = %v, want UnknownFilterRuleError untuk 'cycles'", err)
+	}
+}
+
+func TestLoadWithFilterPolicy(t *testing.T) {
+	tests := []struct {
+		policy      string
+		wantMud     int
+		wantBrick   int
+		wantVersion string
+		wantCycles  bool
+	}{
+		{policy: "default", wantMud: 1, wantBrick: 1},
+		{policy: "none", wantMud: 3, wantBrick: 2, wantVersion: "+policy:none", wantCycles: true},
+		{policy: "tier", wantMud: 1, wantBrick: 1, wantVersion: "+policy:tier"},
+		{policy: "unknown", wantMud: 2, wantBrick: 2, wantVersion: "+policy:unknown", wantCycles: true},
+		{policy: "self", wantMud: 2, wantBrick: 2, wantVersion: "+policy:self", wantCycles: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			policy, err := ParseFilterPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			graph, err := LoadBiGraphFromReader(strings.NewReader(policyDataset), "policy", LoadOptions{Policy: policy})
+			if err != nil {
+				t.Fatalf("memuat dataset: %v", err)
+			}
+			if got := len(graph.ChildToParents["Mud"]); got != tt.wantMud {
+				t.Errorf("resep Mud = %d (%v), want %d", got, graph.ChildToParents["Mud"], tt.wantMud)
+			}
+			if got := len(graph.ChildToParents["Brick"]); got != tt.wantBrick {
+				t.Errorf("resep Brick = %d (%v), want %d", got, graph.ChildToParents["Brick"], tt.wantBrick)
+			}
+			if !strings.HasSuffix(graph.Version, tt.wantVersion) || (tt.wantVersion == "" && strings.Contains(graph.Version, "+policy:")) {
+				t.Errorf("Version = %q, want akhiran %q", graph.Version, tt.wantVersion)
+			}
+			if graph.Policy != policy {
+				t.Errorf("Policy = %+v, want %+v", graph.Policy, policy)
+			}
+			cg, err := graph.Compact()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cg.HasCycles != tt.wantCycles {
+				t.Errorf("HasCycles = %v, want %v", cg.HasCycles, tt.wantCycles)
+			}
+		})
+	}
+}
+
+func TestNewFilterReport(t *testing.T) {
+	var elements []ElementInput
+	if err := json.Unmarshal([]byte(policyDataset), &elements); err != nil {
+		t.Fatal(err)
+	}
+	// Goo hanya punya resep yang melanggar tier dan resep yang bukan pasangan.
+	elements = append(elements, ElementInput{Name: "Goo", Tier: 2, Recipes: [][]string{{"Wall", "Water"}, {"Mud"}}})
+
+	tests := []struct {
+		policy string
+		want   []RejectedRecipe
+		// wantEmpty adalah elemen non-dasar yang tidak punya resep tersisa.
+		wantEmpty []string
+	}{
+		{
+			policy: "default",
+			want: []RejectedRecipe{
+				{Element: "Mud", Recipe: []string{"Mud", "Water"}, Rule: RuleSelf},
+				{Element: "Mud", Recipe: []string{"Slime", "Water"}, Rule: RuleUnknown},
+				{Element: "Brick", Recipe: []string{"Wall", "Fire"}, Rule: RuleTier},
+				{Element: "Goo", Recipe: []string{"Wall", "Water"}, Rule: RuleTier},
+				{Element: "Goo", Recipe: []string{"Mud"}, Rule: RuleMalformed},
+			},
+			wantEmpty: []string{"Goo"},
+		},
+		{
+			policy: "none",
+			want: []RejectedRecipe{
+				{Element: "Goo", Recipe: []string{"Mud"}, Rule: RuleMalformed},
+			},
+			wantEmpty: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			policy, err := ParseFilterPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := NewFilterReport(elements, LoadOptions{Policy: policy})
+			if report.Policy != policy.String() {
+				t.Errorf("Policy = %q, want %q", report.Policy, policy.String())
+			}
+			if !reflect.DeepEqual(report.RejectedRecipes, tt.want) {
+				t.Errorf("RejectedRecipes = %+v, want %+v", report.RejectedRecipes, tt.want)
+			}
+			if !reflect.DeepEqual(report.ElementsWithoutRecipes, tt.wantEmpty) {
+				t.Errorf("ElementsWithoutRecipes = %v, want %v", report.ElementsWithoutRecipes, tt.wantEmpty)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package loadrecipes
+
+import "sort"
+
+// DefaultStatsTopN adalah jumlah entri default untuk daftar "top" di GraphStats.
+const DefaultStatsTopN = 20
+
+type TierCount struct {
+	Tier  int `json:"tier"`
+	Count int `json:"count"`
+}
+
+type ElementCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type PairChildren struct {
+	Mat1     string   `json:"mat1"`
+	Mat2     string   `json:"mat2"`
+	Children []string `json:"children"`
+}
+
+type DepthCount struct {
+	Depth int `json:"depth"`
+	Count int `json:"count"`
+}
+
+// GraphStats adalah statistik level dataset yang dihitung dari BiGraphAlchemy.
+type GraphStats struct {
+	Version                        string         `json:"version"`
+	TotalElements                  int            `json:"totalElements"`
+	TotalRecipes                   int            `json:"totalRecipes"`
+	UniqueParentPairs              int            `json:"uniqueParentPairs"`
+	ElementsPerTier                []TierCount    `json:"elementsPerTier"`
+	MostUsedIngredients            []ElementCount `json:"mostUsedIngredients"`
+	MostRecipes                    []ElementCount `json:"mostRecipes"`
+	PairsProducingMultipleChildren []PairChildren `json:"pairsProducingMultipleChildren"`
+	FinalElements                  []string       `json:"finalElements"`
+	MinimalDepthDistribution       []DepthCount   `json:"minimalDepthDistribution"`
+	UnreachableElements            []string       `json:"unreachableElements"`
+}
+
+// ComputeMinimalDepths menghitung kedalaman resep minimal tiap elemen: elemen dasar = 0,
+// elemen lain = min atas semua resep dari 1 + max(kedalaman kedua parent).
+// Elemen yang tidak bisa dicapai dari elemen dasar tidak ada di map hasil.
+func ComputeMinimalDepths(graph *BiGraphAlchemy) map[string]int {
+	depths := make(map[string]int, len(graph.AllElements))
+	for base := range graph.BaseElements {
+		depths[base] = 0
+	}
+
+	// Relaksasi berulang sampai stabil; jumlah putaran paling banyak sebanyak kedalaman maksimum.
+	for changed := true; changed; {
+		changed = false
+		for child, pairs := range graph.ChildToParents {
+			if graph.BaseElements[child] {
+				continue
+			}
+			for _, pair := range pairs {
+				d1, ok1 := depths[pair.Mat1]
+				d2, ok2 := depths[pair.Mat2]
+				if !ok1 || !ok2 {
+					continue
+				}
+				candidate := 1 + max(d1, d2)
+				if current, ok := depths[child]; !ok || candidate < current {
+					depths[child] = candidate
+					changed = true
+				}
+			}
+		}
+	}
+	return depths
+}
+
+// ComputeGraphStats menghitung GraphStats. topN membatasi panjang daftar "most used"
+// dan "most recipes"; nilai <= 0 memakai DefaultStatsTopN.
+func ComputeGraphStats(graph *BiGraphAlchemy, topN int) *GraphStats {
+	if topN <= 0 {
+		topN = DefaultStatsTopN
+	}
+
+	stats := &GraphStats{
+		Version:           graph.Version,
+		TotalElements:     len(graph.AllElements),
+		UniqueParentPairs: len(graph.ParentPairToChild),
+
+		PairsProducingMultipleChildren: []PairChildren{},
+		FinalElements:                  []string{},
+		UnreachableElements:            []string{},
+	}
+
+	tierCounts := make(map[int]int)
+	for name := range graph.AllElements {
+		tierCounts[graph.Tiers[name]]++
+	}
+	for tier, count := range tierCounts {
+		stats.ElementsPerTier = append(stats.ElementsPerTier, TierCount{Tier: tier, Count: count})
+	}
+	sort.Slice(stats.ElementsPerTier, func(i, j int) bool {
+		return stats.ElementsPerTier[i].Tier < stats.ElementsPerTier[j].Tier
+	})
+
+	usage := make(map[string]int)
+	for pair, children := range graph.ParentPairToChild {
+		usage[pair.Mat1] += len(children)
+		if pair.Mat2 != pair.Mat1 {
+			usage[pair.Mat2] += len(children)
+		}
+		if len(children) > 1 {
+			stats.PairsProducingMultipleChildren = append(stats.PairsProducingMultipleChildren, PairChildren{
+				Mat1: pair.Mat1, Mat2: pair.Mat2, Children: children,
+			})
+		}
+	}
+	sort.Slice(stats.PairsProducingMultipleChildren, func(i, j int) bool {
+		a, b := stats.PairsProducingMultipleChildren[i], stats.PairsProducingMultipleChildren[j]
+		if len(a.Children) != len(b.Children) {
+			return len(a.Children) > len(b.Children)
+		}
+		if a.Mat1 != b.Mat1 {
+			return a.Mat1 < b.Mat1
+		}
+		return a.Mat2 < b.Mat2
+	})
+	stats.MostUsedIngredients = topElementCounts(usage, topN)
+
+	recipeCounts := make(map[string]int)
+	for child, pairs := range graph.ChildToParents {
+		recipeCounts[child] = len(pairs)
+		stats.TotalRecipes += len(pairs)
+	}
+	stats.MostRecipes = topElementCounts(recipeCounts, topN)
+
+	for name := range graph.AllElements {
+		if usage[name] == 0 {
+			stats.FinalElements = append(stats.FinalElements, name)
+		}
+	}
+	sort.Strings(stats.FinalElements)
+
+	depths := ComputeMinimalDepths(graph)
+	depthCounts := make(map[int]int)
+	for name := range graph.AllElements {
+		if depth, ok := depths[name]; ok {
+			depthCounts[depth]++
+		} else {
+			stats.UnreachableElements = append(stats.UnreachableElements, name)
+		}
+	}
+	for depth, count := range depthCounts {
+		stats.MinimalDepthDistribution = append(stats.MinimalDepthDistribution, DepthCount{Depth: depth, Count: count})
+	}
+	sort.Slice(stats.MinimalDepthDistribution, func(i, j int) bool {
+		return stats.MinimalDepthDistribution[i].Depth < stats.MinimalDepthDistribution[j].Depth
+	})
+	sort.Strings(stats.UnreachableElements)
+
+	return stats
+}
+
+// topElementCounts mengurutkan count menurun (nama sebagai tie-breaker) lalu mengambil n teratas.
+func topElementCounts(counts map[string]int, n int) []ElementCount {
+	list := make([]ElementCount, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, ElementCount{Name: name, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Name < list[j].Name
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
@@ -0,0 +1,115 @@
+package loadrecipes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// statsDataset: Fire + Mud menghasilkan Brick dan Clay, Lonely tidak punya resep sehingga
+// Lonely dan Ghost tidak bisa dicapai dari elemen dasar.
+const statsDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+	{"name": "Steam", "tier": 1, "recipes": [["Fire", "Water"]]},
+	{"name": "Lonely", "tier": 1, "recipes": []},
+	{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"], ["Mud", "Air"]]},
+	{"name": "Clay", "tier": 2, "recipes": [["Mud", "Fire"]]},
+	{"name": "Ghost", "tier": 2, "recipes": [["Lonely", "Air"]]},
+	{"name": "Wall", "tier": 3, "recipes": [["Brick", "Brick"]]}
+]`
+
+func TestComputeGraphStats(t *testing.T) {
+	graph, err := LoadBiGraphFromReader(strings.NewReader(statsDataset), "stats", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	stats := ComputeGraphStats(graph, 3)
+
+	if stats.Version != graph.Version {
+		t.Errorf("Version = %q, want %q", stats.Version, graph.Version)
+	}
+	counts := []struct {
+		name      string
+		got, want int
+	}{
+		{"TotalElements", stats.TotalElements, 11},
+		{"TotalRecipes", stats.TotalRecipes, 7},
+		{"UniqueParentPairs", stats.UniqueParentPairs, 6},
+	}
+	for _, c := range counts {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{
+			name: "ElementsPerTier",
+			got:  stats.ElementsPerTier,
+			want: []TierCount{{Tier: 0, Count: 4}, {Tier: 1, Count: 3}, {Tier: 2, Count: 3}, {Tier: 3, Count: 1}},
+		},
+		{
+			name: "MostUsedIngredients",
+			got:  stats.MostUsedIngredients,
+			want: []ElementCount{{Name: "Fire", Count: 3}, {Name: "Mud", Count: 3}, {Name: "Air", Count: 2}},
+		},
+		{
+			name: "MostRecipes",
+			got:  stats.MostRecipes,
+			want: []ElementCount{{Name: "Brick", Count: 2}, {Name: "Clay", Count: 1}, {Name: "Ghost", Count: 1}},
+		},
+		{
+			name: "PairsProducingMultipleChildren",
+			got:  stats.PairsProducingMultipleChildren,
+			want: []PairChildren{{Mat1: "Fire", Mat2: "Mud", Children: []string{"Brick", "Clay"}}},
+		},
+		{
+			name: "FinalElements",
+			got:  stats.FinalElements,
+			want: []string{"Clay", "Ghost", "Steam", "Wall"},
+		},
+		{
+			name: "MinimalDepthDistribution",
+			got:  stats.MinimalDepthDistribution,
+			want: []DepthCount{{Depth: 0, Count: 4}, {Depth: 1, Count: 2}, {Depth: 2, Count: 2}, {Depth: 3, Count: 1}},
+		},
+		{
+			name: "UnreachableElements",
+			got:  stats.UnreachableElements,
+			want: []string{"Ghost", "Lonely"},
+		},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %+v, want %+v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestComputeMinimalDepths(t *testing.T) {
+	graph, err := LoadBiGraphFromReader(strings.NewReader(statsDataset), "stats", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	want := map[string]int{
+		"Air": 0, "Earth": 0, "Fire": 0, "Water": 0,
+		"Mud": 1, "Steam": 1, "Brick": 2, "Clay": 2, "Wall": 3,
+	}
+	if got := ComputeMinimalDepths(graph); !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeMinimalDepths = %v, want %v", got, want)
+	}
+}
+
+func TestComputeGraphStatsDefaultTopN(t *testing.T) {
+	graph, err := LoadBiGraphFromReader(strings.NewReader(statsDataset), "stats", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	// Hanya 7 elemen dipakai sebagai bahan, jadi topN default tidak memotong daftar.
+	if got := len(ComputeGraphStats(graph, 0).MostUsedIngredients); got != 7 {
+		t.Errorf("len(MostUsedIngredients) dengan topN 0 = %d, want 7", got)
+	}
+}
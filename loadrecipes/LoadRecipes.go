@@ -1,16 +1,23 @@
 package loadrecipes
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"io/ioutil"
+	"io"
+	"io/fs"
 	"log"
 	"os"
 	"sort"
+	"sync"
 )
 
 type ElementInput struct {
-	Name    string     `json:"name"`
-	Recipes [][]string `json:"recipes"`
+	Name     string     `json:"name"`
+	Recipes  [][]string `json:"recipes"`
+	Tier     int        `json:"tier"`
+	ImageURL string     `json:"imageUrl,omitempty"`
+	Pack     string     `json:"pack,omitempty"`
 }
 
 type PairMats struct {
@@ -38,16 +45,90 @@ type BiGraphAlchemy struct {
 	ParentPairToChild map[PairMats][]string 
 	BaseElements      map[string]bool
 	AllElements       map[string]bool
+	Tiers             map[string]int
+	// Packs berisi expansion pack tiap elemen; elemen base game tidak ada di map ini.
+	Packs map[string]string
+	// Images berisi URL gambar tiap elemen (field imageUrl di dataset), jika ada.
+	Images map[string]string
+	// Version adalah hash isi file dataset; berubah setiap kali data berubah.
+	Version string
+	// Policy adalah FilterPolicy yang dipakai saat graf dimuat.
+	Policy FilterPolicy
+
+	compactOnce sync.Once
+	compact     *CompactGraph
+	compactErr  error
+
+	packViewsMutex sync.Mutex
+	packViews      map[string]*BiGraphAlchemy
+}
+
+// RecipeCount mengembalikan jumlah resep (pasangan parent -> child) di graf.
+func (g *BiGraphAlchemy) RecipeCount() int {
+	count := 0
+	for _, pairs := range g.ChildToParents {
+		count += len(pairs)
+	}
+	return count
+}
+
+// DefaultBaseElements adalah elemen dasar Little Alchemy 1 dan 2.
+var DefaultBaseElements = []string{"Air", "Earth", "Fire", "Water"}
+
+// LoadOptions mengatur elemen dasar dan FilterPolicy saat memuat dataset.
+type LoadOptions struct {
+	BaseElements []string
+	Policy       FilterPolicy
+	// Strict menjalankan ValidateElements sebelum memuat; dataset dengan error ditolak
+	// dengan InvalidDatasetError, bukan hanya di-log lalu dilewati.
+	Strict bool
+}
+
+// DefaultLoadOptions memakai DefaultBaseElements dan DefaultFilterPolicy.
+func DefaultLoadOptions() LoadOptions {
+	return LoadOptions{BaseElements: DefaultBaseElements, Policy: DefaultFilterPolicy}
 }
 
 func LoadBiGraph(filepath string) (*BiGraphAlchemy, error) {
+	return LoadBiGraphWithOptions(filepath, DefaultLoadOptions())
+}
+
+// LoadBiGraphWithBase sama dengan LoadBiGraph, tapi dengan daftar elemen dasar sendiri
+// (untuk dataset lain seperti custom pack).
+func LoadBiGraphWithBase(filepath string, baseElements []string) (*BiGraphAlchemy, error) {
+	return LoadBiGraphWithOptions(filepath, LoadOptions{BaseElements: baseElements, Policy: DefaultFilterPolicy})
+}
+
+// LoadBiGraphWithOptions memuat dataset lalu menerapkan opts.Policy ke setiap resep.
+// Policy selain DefaultFilterPolicy ditandai di Version dengan akhiran "+policy:<aturan>".
+func LoadBiGraphWithOptions(filepath string, opts LoadOptions) (*BiGraphAlchemy, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
+	return LoadBiGraphFromReader(file, filepath, opts)
+}
+
+// LoadBiGraphFS sama dengan LoadBiGraphWithOptions, tapi membaca name dari fsys
+// (misalnya embed.FS yang berisi dataset bawaan).
+func LoadBiGraphFS(fsys fs.FS, name string, opts LoadOptions) (*BiGraphAlchemy, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadBiGraphFromReader(file, name, opts)
+}
+
+// LoadBiGraphFromReader memuat dataset JSON dari r. source hanya dipakai untuk log dan pesan error.
+func LoadBiGraphFromReader(r io.Reader, source string, opts LoadOptions) (*BiGraphAlchemy, error) {
+	baseElements := opts.BaseElements
+	if len(baseElements) == 0 {
+		baseElements = DefaultBaseElements
+	}
 
-	data, err := ioutil.ReadAll(file)
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
@@ -57,26 +138,80 @@ func LoadBiGraph(filepath string) (*BiGraphAlchemy, error) {
 	if err != nil {
 		return nil, err
 	}
+	if opts.Strict {
+		if report := ValidateElements(elements, ValidationOptions{BaseElements: baseElements}); !report.Valid {
+			return nil, &InvalidDatasetError{Path: source, Report: report}
+		}
+	}
 
+	graphData, report := buildBiGraph(elements, baseElements, datasetVersion(data), opts)
+	if counts := report.countByRule(); len(counts) > 0 {
+		log.Printf("[INFO] Filter policy '%s' membuang resep: tier=%d, unknown=%d, self=%d, malformed=%d.",
+			opts.Policy, counts[RuleTier], counts[RuleUnknown], counts[RuleSelf], counts[RuleMalformed])
+	}
+
+	log.Printf("[INFO] Data successfully loaded from '%s'. Total Unique Elements: %d. Unique Parent Pairs: %d. Child-to-Parent Relations: %d.\n",
+		source, len(graphData.AllElements), len(graphData.ParentPairToChild), len(graphData.ChildToParents))
+
+	return graphData, nil
+}
+
+// NewFilterReport menjalankan opts.Policy ke elements persis seperti loader dan mengembalikan
+// semua resep yang ditolak, tanpa perlu menyimpan graf hasilnya.
+func NewFilterReport(elements []ElementInput, opts LoadOptions) *FilterReport {
+	baseElements := opts.BaseElements
+	if len(baseElements) == 0 {
+		baseElements = DefaultBaseElements
+	}
+	_, report := buildBiGraph(elements, baseElements, "", opts)
+	return report
+}
+
+// buildBiGraph membangun BiGraphAlchemy dari elements dengan opts.Policy. FilterReport berisi
+// setiap resep yang tidak dimasukkan ke graf, jadi laporan selalu sama dengan hasil loader.
+func buildBiGraph(elements []ElementInput, baseElements []string, version string, opts LoadOptions) (*BiGraphAlchemy, *FilterReport) {
 	graphData := &BiGraphAlchemy{
 		ChildToParents:    make(map[string][]PairMats),
 		ParentPairToChild: make(map[PairMats][]string), 
-		BaseElements: map[string]bool{
-			"Air":   true,
-			"Fire":  true,
-			"Earth": true,
-			"Water": true,
-		},
+		BaseElements: make(map[string]bool, len(baseElements)),
 		AllElements: make(map[string]bool),
+		Tiers:       make(map[string]int),
+		Packs:       make(map[string]string),
+		Images:      make(map[string]string),
+		Version:     version,
+		Policy:      opts.Policy,
+	}
+	if opts.Policy != DefaultFilterPolicy {
+		graphData.Version += "+policy:" + opts.Policy.String()
 	}
 
+	for _, baseElem := range baseElements {
+		graphData.BaseElements[baseElem] = true
+	}
 	for baseElem := range graphData.BaseElements {
 		graphData.AllElements[baseElem] = true
 	}
 
+	// Tier dan daftar elemen dikumpulkan dulu agar policy bisa mengecek parent
+	// yang muncul setelah child di file.
+	knownElements := make(map[string]bool, len(elements)+len(graphData.BaseElements))
+	for baseElem := range graphData.BaseElements {
+		knownElements[baseElem] = true
+	}
 	for _, element := range elements {
+		knownElements[element.Name] = true
 		graphData.AllElements[element.Name] = true
+		graphData.Tiers[element.Name] = element.Tier
+		if element.Pack != "" {
+			graphData.Packs[element.Name] = element.Pack
+		}
+		if element.ImageURL != "" {
+			graphData.Images[element.Name] = element.ImageURL
+		}
+	}
 
+	report := &FilterReport{Policy: opts.Policy.String(), RejectedRecipes: []RejectedRecipe{}, ElementsWithoutRecipes: []string{}}
+	for _, element := range elements {
 		if graphData.BaseElements[element.Name] {
 			continue
 		}
@@ -85,10 +220,15 @@ func LoadBiGraph(filepath string) (*BiGraphAlchemy, error) {
 		for _, recipe := range element.Recipes {
 			if len(recipe) != 2 {
 				log.Printf("[WARNING] Invalid recipe format for element %s: %v. Skipping recipe.", element.Name, recipe)
+				report.RejectedRecipes = append(report.RejectedRecipes, RejectedRecipe{Element: element.Name, Recipe: recipe, Rule: RuleMalformed})
 				continue
 			}
 
 			parent1, parent2 := recipe[0], recipe[1]
+			if rule := opts.Policy.recipeRejection(element.Name, parent1, parent2, knownElements, graphData.BaseElements, graphData.Tiers); rule != "" {
+				report.RejectedRecipes = append(report.RejectedRecipes, RejectedRecipe{Element: element.Name, Recipe: recipe, Rule: rule})
+				continue
+			}
 			graphData.AllElements[parent1] = true
 			graphData.AllElements[parent2] = true
 
@@ -114,15 +254,19 @@ func LoadBiGraph(filepath string) (*BiGraphAlchemy, error) {
 			graphData.ChildToParents[element.Name] = validRecipesForChild
 		} else if !graphData.BaseElements[element.Name] {
 			log.Printf("[INFO] Element %s (non-base) has no valid recipes after loading.", element.Name)
+			report.ElementsWithoutRecipes = append(report.ElementsWithoutRecipes, element.Name)
 		}
 	}
 
 	for pair := range graphData.ParentPairToChild {
 		sort.Strings(graphData.ParentPairToChild[pair])
 	}
+	sort.Strings(report.ElementsWithoutRecipes)
+	return graphData, report
+}
 
-	log.Printf("[INFO] Data successfully loaded from '%s'. Total Unique Elements: %d. Unique Parent Pairs: %d. Child-to-Parent Relations: %d.\n",
-		filepath, len(graphData.AllElements), len(graphData.ParentPairToChild), len(graphData.ChildToParents))
-
-	return graphData, nil
-}
\ No newline at end of file
+// datasetVersion mengembalikan 12 karakter pertama SHA-256 dari isi dataset.
+func datasetVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,119 @@
+package loadrecipes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/metrics"
+)
+
+// BaseGamePack adalah nama "pack" untuk elemen base game (tanpa expansion).
+const BaseGamePack = ""
+
+// ElementPack mengembalikan pack sebuah elemen, atau BaseGamePack untuk elemen base game.
+func (g *BiGraphAlchemy) ElementPack(name string) string {
+	return g.Packs[name]
+}
+
+// PackNames mengembalikan semua expansion pack yang ada di graf, terurut.
+func (g *BiGraphAlchemy) PackNames() []string {
+	seen := make(map[string]bool)
+	for _, pack := range g.Packs {
+		seen[pack] = true
+	}
+	names := make([]string, 0, len(seen))
+	for pack := range seen {
+		names = append(names, pack)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnknownPackError dikembalikan RestrictToPacks untuk pack yang tidak ada di graf.
+type UnknownPackError struct {
+	Pack  string
+	Known []string
+}
+
+func (e *UnknownPackError) Error() string {
+	if len(e.Known) == 0 {
+		return fmt.Sprintf("pack '%s' tidak dikenal (dataset tidak punya expansion pack)", e.Pack)
+	}
+	return fmt.Sprintf("pack '%s' tidak dikenal (pilihan: %s)", e.Pack, strings.Join(e.Known, ", "))
+}
+
+// RestrictToPacks mengembalikan graf yang hanya berisi elemen base game ditambah elemen
+// dari allowedPacks. Resep yang memakai elemen di luar pack tersebut ikut dibuang, sehingga
+// hasil pencarian hanya berisi langkah yang bisa dilakukan pemain tanpa pack lain.
+// Pack yang tidak ada di PackNames ditolak dengan *UnknownPackError, jadi cache graf hasil
+// (per kombinasi pack, terurut dan tanpa duplikat) tidak bisa tumbuh dari nama sembarang.
+func (g *BiGraphAlchemy) RestrictToPacks(allowedPacks []string) (*BiGraphAlchemy, error) {
+	known := g.PackNames()
+	allowed := map[string]bool{BaseGamePack: true}
+	keyParts := make([]string, 0, len(allowedPacks))
+	for _, pack := range allowedPacks {
+		if !allowed[pack] && !ContainsString(known, pack) {
+			return nil, &UnknownPackError{Pack: pack, Known: known}
+		}
+		if !allowed[pack] {
+			allowed[pack] = true
+			keyParts = append(keyParts, pack)
+		}
+	}
+	sort.Strings(keyParts)
+	key := strings.Join(keyParts, "\x1f")
+
+	g.packViewsMutex.Lock()
+	defer g.packViewsMutex.Unlock()
+	if view, ok := g.packViews[key]; ok {
+		metrics.CacheLookup("pack_view", true)
+		return view, nil
+	}
+	metrics.CacheLookup("pack_view", false)
+
+	isAllowed := func(name string) bool { return allowed[g.Packs[name]] }
+
+	view := &BiGraphAlchemy{
+		ChildToParents:    make(map[string][]PairMats),
+		ParentPairToChild: make(map[PairMats][]string),
+		BaseElements:      g.BaseElements,
+		AllElements:       make(map[string]bool),
+		Tiers:             g.Tiers,
+		Packs:             g.Packs,
+		Images:            g.Images,
+		Version:           g.Version + "+packs:" + strings.Join(keyParts, ","),
+		Policy:            g.Policy,
+	}
+	for name := range g.AllElements {
+		if isAllowed(name) {
+			view.AllElements[name] = true
+		}
+	}
+	for child, pairs := range g.ChildToParents {
+		if !isAllowed(child) {
+			continue
+		}
+		for _, pair := range pairs {
+			if isAllowed(pair.Mat1) && isAllowed(pair.Mat2) {
+				view.ChildToParents[child] = append(view.ChildToParents[child], pair)
+			}
+		}
+	}
+	for pair, children := range g.ParentPairToChild {
+		if !isAllowed(pair.Mat1) || !isAllowed(pair.Mat2) {
+			continue
+		}
+		for _, child := range children {
+			if isAllowed(child) {
+				view.ParentPairToChild[pair] = append(view.ParentPairToChild[pair], child)
+			}
+		}
+	}
+
+	if g.packViews == nil {
+		g.packViews = make(map[string]*BiGraphAlchemy)
+	}
+	g.packViews[key] = view
+	return view, nil
+}
@@ -0,0 +1,44 @@
+package loadrecipes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const packsDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+	{"name": "Golem", "tier": 2, "pack": "Myths and Monsters", "recipes": [["Mud", "Fire"]]},
+	{"name": "Robot", "tier": 2, "pack": "Tech", "recipes": [["Mud", "Air"]]}
+]`
+
+func TestRestrictToPacks(t *testing.T) {
+	graph, err := LoadBiGraphFromReader(strings.NewReader(packsDataset), "packs", DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+
+	view, err := graph.RestrictToPacks([]string{"Tech"})
+	if err != nil {
+		t.Fatalf("RestrictToPacks(Tech): %v", err)
+	}
+	if !view.AllElements["Robot"] || !view.AllElements["Mud"] || view.AllElements["Golem"] {
+		t.Errorf("elemen view Tech salah: %v", view.AllElements)
+	}
+
+	// Urutan dan duplikat tidak membuat view baru.
+	first, _ := graph.RestrictToPacks([]string{"Tech", "Myths and Monsters"})
+	second, _ := graph.RestrictToPacks([]string{"Myths and Monsters", "Tech", "Tech"})
+	if first != second {
+		t.Error("kombinasi pack yang sama seharusnya memakai view yang sama dari cache")
+	}
+
+	_, err = graph.RestrictToPacks([]string{"Tech", "Nonexistent"})
+	var unknown *UnknownPackError
+	if !errors.As(err, &unknown) || unknown.Pack != "Nonexistent" {
+		t.Fatalf("error = %v, want UnknownPackError untuk Nonexistent", err)
+	}
+	if len(graph.packViews) != 2 {
+		t.Errorf("jumlah view di cache = %d, want 2", len(graph.packViews))
+	}
+}
@@ -0,0 +1,186 @@
+package loadrecipes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/metrics"
+)
+
+// DefaultDatasetName adalah dataset yang dipakai jika request tidak menyebut dataset.
+const DefaultDatasetName = "la2"
+
+// DatasetConfig mendeskripsikan satu dataset resep: file JSON-nya dan elemen dasarnya.
+// Jika FS diisi, Path dibaca dari FS (misalnya dataset yang di-embed ke binary).
+type DatasetConfig struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Path         string   `json:"path"`
+	FS           fs.FS    `json:"-"`
+	BaseElements []string `json:"baseElements,omitempty"`
+	// Policy adalah FilterPolicy saat dataset dimuat; nil berarti DefaultFilterPolicy.
+	Policy *FilterPolicy `json:"policy,omitempty"`
+	// Strict menolak dataset yang gagal ValidateElements saat dimuat (lihat LoadOptions.Strict).
+	Strict bool `json:"strict,omitempty"`
+}
+
+// UnknownDatasetError dikembalikan Registry.Get untuk nama dataset yang tidak terdaftar.
+type UnknownDatasetError struct {
+	Name string
+}
+
+func (e *UnknownDatasetError) Error() string {
+	return fmt.Sprintf("dataset '%s' tidak terdaftar", e.Name)
+}
+
+// Metrik dataset yang sudah dimuat Registry, per nama dataset.
+var (
+	graphLoadSeconds = metrics.Default.NewGaugeVec("alchemy_graph_load_seconds",
+		"Lama memuat dan memfilter dataset terakhir kali, dalam detik.", "dataset")
+	graphElements = metrics.Default.NewGaugeVec("alchemy_graph_elements",
+		"Jumlah elemen di dataset yang sudah dimuat.", "dataset")
+	graphRecipes = metrics.Default.NewGaugeVec("alchemy_graph_recipes",
+		"Jumlah resep di dataset yang sudah dimuat.", "dataset")
+)
+
+// Registry menyimpan beberapa dataset berdampingan. Graf dimuat saat pertama kali
+// diminta lalu di-cache.
+type Registry struct {
+	mutex       sync.Mutex
+	defaultName string
+	configs     map[string]DatasetConfig
+	graphs      map[string]*BiGraphAlchemy
+}
+
+func NewRegistry(defaultName string) *Registry {
+	return &Registry{
+		defaultName: defaultName,
+		configs:     make(map[string]DatasetConfig),
+		graphs:      make(map[string]*BiGraphAlchemy),
+	}
+}
+
+// DefaultDatasetConfig mengembalikan konfigurasi dataset Little Alchemy 2 bawaan yang dibaca
+// dari working directory.
+func DefaultDatasetConfig() DatasetConfig {
+	return DatasetConfig{
+		Name:        DefaultDatasetName,
+		Description: "Little Alchemy 2",
+		Path:        "elements_filtered.json",
+	}
+}
+
+// NewDefaultRegistry membuat Registry dengan DefaultDatasetConfig. Jika env DATASETS_CONFIG
+// diisi, dataset tambahan dibaca dari file JSON tersebut (lihat LoadDatasetConfigs).
+func NewDefaultRegistry() (*Registry, error) {
+	return NewDefaultRegistryWith(DefaultDatasetConfig())
+}
+
+// NewDefaultRegistryWith sama dengan NewDefaultRegistry, tapi dengan konfigurasi dataset
+// default sendiri (misalnya dataset yang di-embed). defaultConfig.Name menjadi nama default.
+func NewDefaultRegistryWith(defaultConfig DatasetConfig) (*Registry, error) {
+	registry := NewRegistry(defaultConfig.Name)
+	registry.Register(defaultConfig)
+
+	if configPath := os.Getenv("DATASETS_CONFIG"); configPath != "" {
+		configs, err := LoadDatasetConfigs(configPath)
+		if err != nil {
+			return registry, err
+		}
+		for _, config := range configs {
+			registry.Register(config)
+		}
+	}
+	return registry, nil
+}
+
+// LoadDatasetConfigs membaca daftar DatasetConfig dari file JSON berbentuk array.
+func LoadDatasetConfigs(path string) ([]DatasetConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var configs []DatasetConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("gagal parse konfigurasi dataset '%s': %w", path, err)
+	}
+	for _, config := range configs {
+		if config.Name == "" || config.Path == "" {
+			return nil, fmt.Errorf("konfigurasi dataset di '%s' wajib memiliki name dan path", path)
+		}
+	}
+	return configs, nil
+}
+
+// Register menambah atau mengganti dataset. Graf yang sudah di-cache untuk nama yang sama dibuang.
+func (r *Registry) Register(config DatasetConfig) {
+	if len(config.BaseElements) == 0 {
+		config.BaseElements = DefaultBaseElements
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.configs[config.Name] = config
+	delete(r.graphs, config.Name)
+}
+
+// Get mengembalikan graf untuk dataset name ("" berarti dataset default).
+func (r *Registry) Get(name string) (*BiGraphAlchemy, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if graph, ok := r.graphs[name]; ok {
+		metrics.CacheLookup("dataset", true)
+		return graph, nil
+	}
+	metrics.CacheLookup("dataset", false)
+	config, ok := r.configs[name]
+	if !ok {
+		return nil, &UnknownDatasetError{Name: name}
+	}
+	opts := LoadOptions{BaseElements: config.BaseElements, Policy: DefaultFilterPolicy, Strict: config.Strict}
+	if config.Policy != nil {
+		opts.Policy = *config.Policy
+	}
+	var graph *BiGraphAlchemy
+	var err error
+	start := time.Now()
+	if config.FS != nil {
+		graph, err = LoadBiGraphFS(config.FS, config.Path, opts)
+	} else {
+		graph, err = LoadBiGraphWithOptions(config.Path, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	graphLoadSeconds.Set(time.Since(start).Seconds(), name)
+	graphElements.Set(float64(len(graph.AllElements)), name)
+	graphRecipes.Set(float64(graph.RecipeCount()), name)
+	r.graphs[name] = graph
+	return graph, nil
+}
+
+// DefaultName mengembalikan nama dataset default.
+func (r *Registry) DefaultName() string {
+	return r.defaultName
+}
+
+// Datasets mengembalikan konfigurasi semua dataset, diurutkan berdasarkan nama.
+func (r *Registry) Datasets() []DatasetConfig {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	configs := make([]DatasetConfig, 0, len(r.configs))
+	for _, config := range r.configs {
+		configs = append(configs, config)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Name < configs[j].Name })
+	return configs
+}
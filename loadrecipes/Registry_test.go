@@ -0,0 +1,54 @@
+package loadrecipes
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// invalidDatasetJSON punya elemen duplikat, yang dilaporkan ValidateElements sebagai error.
+const invalidDatasetJSON = `[
+	{"name": "Air", "recipes": [], "tier": 0},
+	{"name": "Earth", "recipes": [], "tier": 0},
+	{"name": "Fire", "recipes": [], "tier": 0},
+	{"name": "Water", "recipes": [], "tier": 0},
+	{"name": "Mud", "recipes": [["Earth", "Water"]], "tier": 1},
+	{"name": "Mud", "recipes": [["Earth", "Water"]], "tier": 1}
+]`
+
+func TestRegistryGetStrict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(path, []byte(invalidDatasetJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		strict  bool
+		wantErr bool
+	}{
+		{name: "tanpa strict dimuat", strict: false, wantErr: false},
+		{name: "strict ditolak", strict: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry("test")
+			registry.Register(DatasetConfig{Name: "test", Path: path, Strict: tt.strict})
+			graph, err := registry.Get("test")
+			if !tt.wantErr {
+				if err != nil || graph == nil {
+					t.Fatalf("Get() = %v, %v; ingin graf tanpa error", graph, err)
+				}
+				return
+			}
+			var invalid *InvalidDatasetError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Get() error = %v; ingin InvalidDatasetError", err)
+			}
+			if invalid.Report.Errors == 0 {
+				t.Errorf("report tidak berisi error: %+v", invalid.Report)
+			}
+		})
+	}
+}
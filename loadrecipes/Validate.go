@@ -0,0 +1,324 @@
+package loadrecipes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Tingkat keparahan ValidationIssue.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Kode masalah yang bisa muncul di ValidationIssue.Code.
+const (
+	ValidationDuplicateElement  = "duplicate_element"
+	ValidationEmptyName         = "empty_name"
+	ValidationDuplicateRecipe   = "duplicate_recipe"
+	ValidationMalformedRecipe   = "malformed_recipe"
+	ValidationUnknownElement    = "unknown_element"
+	ValidationSelfRecipe        = "self_recipe"
+	ValidationCycle             = "cycle"
+	ValidationUnreachable       = "unreachable_element"
+	ValidationTierInconsistency = "tier_inconsistency"
+	ValidationMissingImage      = "missing_image"
+)
+
+// ValidationIssue adalah satu masalah yang ditemukan ValidateElements.
+type ValidationIssue struct {
+	Severity string   `json:"severity"`
+	Code     string   `json:"code"`
+	Element  string   `json:"element,omitempty"`
+	Recipe   []string `json:"recipe,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// ValidationReport adalah hasil pengecekan satu dataset. Valid = true jika tidak ada error
+// (warning tidak membuat dataset tidak valid).
+type ValidationReport struct {
+	Valid    bool              `json:"valid"`
+	Elements int               `json:"elements"`
+	Recipes  int               `json:"recipes"`
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+	Issues   []ValidationIssue `json:"issues"`
+}
+
+// InvalidDatasetError dikembalikan loader dengan LoadOptions.Strict jika dataset punya error.
+type InvalidDatasetError struct {
+	Path   string
+	Report *ValidationReport
+}
+
+func (e *InvalidDatasetError) Error() string {
+	for _, issue := range e.Report.Issues {
+		if issue.Severity == SeverityError {
+			return fmt.Sprintf("dataset '%s' tidak valid (%d error), pertama: %s", e.Path, e.Report.Errors, issue.Message)
+		}
+	}
+	return fmt.Sprintf("dataset '%s' tidak valid (%d error)", e.Path, e.Report.Errors)
+}
+
+// ValidationOptions mengatur ValidateElements.
+type ValidationOptions struct {
+	BaseElements []string
+	// ImageNames berisi nama elemen yang punya file gambar lokal. Jika nil, hanya field
+	// imageUrl yang dicek.
+	ImageNames map[string]bool
+}
+
+// ReadElementInputs membaca file dataset JSON tanpa memuatnya menjadi graf.
+func ReadElementInputs(path string) ([]ElementInput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var elements []ElementInput
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, fmt.Errorf("gagal parse '%s': %w", path, err)
+	}
+	return elements, nil
+}
+
+// ValidateElements mengecek dataset mentah: nama elemen duplikat, resep duplikat (urutan parent
+// diabaikan), resep yang bukan pasangan, referensi ke elemen yang tidak ada, resep yang memakai
+// child sebagai parent, siklus, elemen yang tidak bisa dicapai dari elemen dasar,
+// tier parent >= tier child, dan elemen tanpa gambar.
+func ValidateElements(elements []ElementInput, opts ValidationOptions) *ValidationReport {
+	report := &ValidationReport{Elements: len(elements), Issues: []ValidationIssue{}}
+	add := func(severity, code, element string, recipe []string, format string, args ...interface{}) {
+		report.Issues = append(report.Issues, ValidationIssue{
+			Severity: severity, Code: code, Element: element, Recipe: recipe, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	baseElements := opts.BaseElements
+	if len(baseElements) == 0 {
+		baseElements = DefaultBaseElements
+	}
+	base := make(map[string]bool, len(baseElements))
+	for _, name := range baseElements {
+		base[name] = true
+	}
+
+	known := make(map[string]bool, len(elements)+len(base))
+	for name := range base {
+		known[name] = true
+	}
+	tiers := make(map[string]int, len(elements))
+	seen := make(map[string]bool, len(elements))
+	for _, element := range elements {
+		if strings.TrimSpace(element.Name) == "" {
+			add(SeverityError, ValidationEmptyName, "", nil, "elemen dengan nama kosong")
+			continue
+		}
+		if seen[element.Name] {
+			add(SeverityError, ValidationDuplicateElement, element.Name, nil, "elemen '%s' muncul lebih dari sekali", element.Name)
+			continue
+		}
+		seen[element.Name] = true
+		known[element.Name] = true
+		tiers[element.Name] = element.Tier
+	}
+
+	// dependsOn: child -> parent (tanpa resep rusak, resep ke elemen tidak dikenal, dan self-recipe).
+	dependsOn := make(map[string][]string)
+	recipesOf := make(map[string][][2]string)
+	for _, element := range elements {
+		if element.Name == "" {
+			continue
+		}
+		recipeKeys := make(map[PairMats]bool)
+		for _, recipe := range element.Recipes {
+			report.Recipes++
+			if len(recipe) != 2 {
+				add(SeverityError, ValidationMalformedRecipe, element.Name, recipe, "resep '%s' berisi %d bahan, bukan 2", element.Name, len(recipe))
+				continue
+			}
+			parent1, parent2 := recipe[0], recipe[1]
+
+			pair := ConstructPair(parent1, parent2)
+			if recipeKeys[pair] {
+				add(SeverityWarning, ValidationDuplicateRecipe, element.Name, recipe, "resep %s + %s untuk '%s' muncul lebih dari sekali", parent1, parent2, element.Name)
+				continue
+			}
+			recipeKeys[pair] = true
+
+			unknown := false
+			for _, parent := range [2]string{parent1, parent2} {
+				if !known[parent] {
+					add(SeverityError, ValidationUnknownElement, element.Name, recipe, "resep '%s' memakai elemen '%s' yang tidak ada di dataset", element.Name, parent)
+					unknown = true
+				}
+			}
+			if unknown {
+				continue
+			}
+			if parent1 == element.Name || parent2 == element.Name {
+				add(SeverityWarning, ValidationSelfRecipe, element.Name, recipe, "resep '%s' memakai '%s' sendiri sebagai bahan", element.Name, element.Name)
+				continue
+			}
+
+			childTier := tiers[element.Name]
+			for _, parent := range [2]string{parent1, parent2} {
+				if parentTier, ok := tiers[parent]; ok && parentTier >= childTier {
+					add(SeverityWarning, ValidationTierInconsistency, element.Name, recipe, "tier '%s' (%d) tidak lebih kecil dari tier '%s' (%d)", parent, parentTier, element.Name, childTier)
+				}
+			}
+
+			if !base[element.Name] {
+				recipesOf[element.Name] = append(recipesOf[element.Name], [2]string{parent1, parent2})
+				for _, parent := range [2]string{parent1, parent2} {
+					if !ContainsString(dependsOn[element.Name], parent) {
+						dependsOn[element.Name] = append(dependsOn[element.Name], parent)
+					}
+				}
+			}
+		}
+	}
+
+	for _, component := range findDependencyCycles(dependsOn) {
+		add(SeverityWarning, ValidationCycle, component[0], nil, "elemen saling bergantung dalam siklus: %s", strings.Join(component, ", "))
+	}
+
+	// Elemen yang bisa dicapai dari elemen dasar, dihitung sampai stabil.
+	reachable := make(map[string]bool, len(known))
+	for name := range base {
+		reachable[name] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for child, recipes := range recipesOf {
+			if reachable[child] {
+				continue
+			}
+			for _, recipe := range recipes {
+				if reachable[recipe[0]] && reachable[recipe[1]] {
+					reachable[child] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	var unreachable []string
+	for name := range seen {
+		if !reachable[name] {
+			unreachable = append(unreachable, name)
+		}
+	}
+	sort.Strings(unreachable)
+	for _, name := range unreachable {
+		add(SeverityWarning, ValidationUnreachable, name, nil, "elemen '%s' tidak bisa dibuat dari elemen dasar", name)
+	}
+
+	for _, element := range elements {
+		if element.Name == "" || element.ImageURL != "" || opts.ImageNames[element.Name] {
+			continue
+		}
+		add(SeverityWarning, ValidationMissingImage, element.Name, nil, "elemen '%s' tidak punya gambar", element.Name)
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Severity == SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	report.Valid = report.Errors == 0
+	return report
+}
+
+// findDependencyCycles mengembalikan setiap komponen terhubung kuat (Tarjan) berukuran > 1
+// dari graf child -> parent. Anggota tiap komponen diurutkan, komponen diurutkan berdasarkan
+// anggota pertamanya.
+func findDependencyCycles(dependsOn map[string][]string) [][]string {
+	names := make([]string, 0, len(dependsOn))
+	for name := range dependsOn {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	nextIndex := 0
+
+	var strongConnect func(name string)
+	strongConnect = func(name string) {
+		index[name] = nextIndex
+		lowLink[name] = nextIndex
+		nextIndex++
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, next := range dependsOn[name] {
+			if _, visited := index[next]; !visited {
+				strongConnect(next)
+				lowLink[name] = min(lowLink[name], lowLink[next])
+			} else if onStack[next] {
+				lowLink[name] = min(lowLink[name], index[next])
+			}
+		}
+
+		if lowLink[name] == index[name] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == name {
+					break
+				}
+			}
+			if len(component) > 1 {
+				sort.Strings(component)
+				components = append(components, component)
+			}
+		}
+	}
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			strongConnect(name)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// CheckLoaded mengecek graf yang sudah dimuat: graf punya resep, semua elemen dasar ada, dan
+// setiap resep hanya merujuk elemen di graf. Dipakai readiness check server; dataset mentah
+// dicek lebih lengkap dengan ValidateElements.
+func (g *BiGraphAlchemy) CheckLoaded() error {
+	// Elemen dasar selalu ditambahkan loader, jadi dataset kosong terlihat dari jumlah resepnya.
+	if len(g.ChildToParents) == 0 {
+		return fmt.Errorf("graf tidak berisi resep")
+	}
+	for base := range g.BaseElements {
+		if !g.AllElements[base] {
+			return fmt.Errorf("elemen dasar '%s' tidak ada di graf", base)
+		}
+	}
+	for child, pairs := range g.ChildToParents {
+		if !g.AllElements[child] {
+			return fmt.Errorf("resep untuk elemen '%s' yang tidak ada di graf", child)
+		}
+		for _, pair := range pairs {
+			if !g.AllElements[pair.Mat1] || !g.AllElements[pair.Mat2] {
+				return fmt.Errorf("resep '%s' + '%s' -> '%s' memakai elemen yang tidak ada di graf", pair.Mat1, pair.Mat2, child)
+			}
+		}
+	}
+	if _, err := g.Compact(); err != nil {
+		return fmt.Errorf("gagal membangun graf kompak: %w", err)
+	}
+	return nil
+}
@@ -1,22 +1,109 @@
 package main
 
 import (
+	"embed"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Starath/Tubes2_BE_SayMyName/admission"
 	"github.com/Starath/Tubes2_BE_SayMyName/api"
+	"github.com/Starath/Tubes2_BE_SayMyName/api/handlers"
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
 )
 
+// embeddedDataset berisi dataset default, sehingga server tidak bergantung pada file di working directory.
+//
+//go:embed elements_filtered.json
+var embeddedDataset embed.FS
+
 func main() {
+	datasetPath := flag.String("dataset", os.Getenv("DATASET_PATH"), "file dataset default eksternal (kosong = dataset yang di-embed)")
+	imageDir := flag.String("images", os.Getenv("IMAGE_DIR"), "folder ikon elemen untuk render SVG (kosong = img)")
+	strict := flag.Bool("strict", os.Getenv("STRICT_DATASETS") == "true", "validasi semua dataset saat start dan berhenti jika ada yang tidak valid")
+	flag.Parse()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	router := api.SetupRouter()
+	defaultDataset := loadrecipes.DefaultDatasetConfig()
+	if *datasetPath != "" {
+		defaultDataset.Path = *datasetPath
+		log.Printf("[INFO] Dataset default dibaca dari file '%s'.", *datasetPath)
+	} else {
+		defaultDataset.FS = embeddedDataset
+	}
+	registry, err := loadrecipes.NewDefaultRegistryWith(defaultDataset)
+	if err != nil {
+		if *strict {
+			log.Fatalf("FATAL: Gagal memuat konfigurasi dataset tambahan: %v", err)
+		}
+		log.Printf("[WARNING] Gagal memuat konfigurasi dataset tambahan: %v. Hanya dataset default yang tersedia.", err)
+	}
+	if *strict {
+		// Mode strict memuat semua dataset di awal, sehingga dataset rusak menggagalkan start,
+		// bukan request pertama yang memintanya.
+		for _, config := range registry.Datasets() {
+			config.Strict = true
+			registry.Register(config)
+			if _, err := registry.Get(config.Name); err != nil {
+				log.Fatalf("FATAL: Dataset '%s' tidak valid: %v", config.Name, err)
+			}
+		}
+		log.Printf("[INFO] Mode strict: semua dataset lolos validasi.")
+	}
+	handlers.Datasets = registry
+
+	// Ikon tidak di-embed: beberapa nama file (mis. "Frankenstein's monster.svg") ditolak go:embed.
+	if *imageDir != "" {
+		handlers.Icons = os.DirFS(*imageDir)
+		log.Printf("[INFO] Ikon elemen dibaca dari folder '%s'.", *imageDir)
+	}
+
+	// Batas pencarian; nilai 0 mematikan rate limit (RATE_LIMIT_RPS) atau batas global (MAX_CONCURRENT_SEARCHES).
+	admissionOptions := admission.DefaultOptions()
+	admissionOptions.Rate = envFloat("RATE_LIMIT_RPS", admissionOptions.Rate)
+	admissionOptions.Burst = envFloat("RATE_LIMIT_BURST", admissionOptions.Burst)
+	admissionOptions.MaxConcurrent = int(envFloat("MAX_CONCURRENT_SEARCHES", float64(admissionOptions.MaxConcurrent)))
+	admissionOptions.MaxQueue = int(envFloat("SEARCH_QUEUE_SIZE", float64(admissionOptions.MaxQueue)))
+	if value := os.Getenv("SEARCH_QUEUE_TIMEOUT"); value != "" {
+		if timeout, err := time.ParseDuration(value); err == nil {
+			admissionOptions.QueueTimeout = timeout
+		} else {
+			log.Printf("[WARNING] SEARCH_QUEUE_TIMEOUT '%s' tidak valid: %v", value, err)
+		}
+	}
+	handlers.Admission = admission.New(admissionOptions)
+	handlers.TrustProxyHeaders = os.Getenv("TRUST_PROXY_HEADERS") == "true"
+
+	routerOptions := api.DefaultRouterOptions()
+	routerOptions.Prefix = os.Getenv("API_PREFIX")
+	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		routerOptions.CORS.AllowedOrigins = strings.Split(origins, ",")
+	}
+	router := api.SetupRouter(routerOptions)
 	
 	fmt.Printf("Server running on port %s\n", port)
 	log.Fatal(http.ListenAndServe(":"+port, router))
-}
\ No newline at end of file
+}
+
+// envFloat membaca angka dari environment variable name, atau fallback jika kosong/tidak valid.
+func envFloat(name string, fallback float64) float64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("[WARNING] %s '%s' bukan angka, memakai %v", name, value, fallback)
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,14 @@
+package metrics
+
+// cacheRequests menghitung lookup ke cache in-memory server, per nama cache dan hasilnya.
+var cacheRequests = Default.NewCounterVec("alchemy_cache_requests_total",
+	"Jumlah lookup cache in-memory, per cache dan hasil (hit/miss).", "cache", "result")
+
+// CacheLookup mencatat satu lookup ke cache bernama cache.
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.Inc(cache, result)
+}
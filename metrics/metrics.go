@@ -0,0 +1,287 @@
+// Package metrics adalah registry metrik sederhana (counter, gauge, histogram dengan label)
+// yang ditulis dalam format teks Prometheus, tanpa dependensi di luar standard library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType adalah content type format teks Prometheus.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets adalah batas bucket histogram latency dalam detik.
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// ExponentialBuckets mengembalikan count batas bucket mulai dari start, masing-masing factor
+// kali batas sebelumnya.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// collector adalah satu metrik (dengan semua series-nya) di Registry.
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+// Registry menyimpan metrik dan menuliskannya dalam urutan nama.
+type Registry struct {
+	mutex      sync.Mutex
+	collectors map[string]collector
+}
+
+// Default adalah registry yang dipakai metrik package lain dan endpoint /metrics.
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+func (r *Registry) register(name string, c collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.collectors[name]; exists {
+		panic("metrics: metrik '" + name + "' sudah terdaftar")
+	}
+	r.collectors[name] = c
+}
+
+// WriteText menulis semua metrik dalam format teks Prometheus.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mutex.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	collectors := make([]collector, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mutex.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+// desc adalah nama, deskripsi, dan nama label sebuah metrik.
+type desc struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+}
+
+func (d *desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.name, strings.NewReplacer("\\", `\\`, "\n", `\n`).Replace(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.name, d.kind)
+}
+
+// key menggabungkan nilai label menjadi key map series.
+func (d *desc) key(labelValues []string) string {
+	if len(labelValues) != len(d.labelNames) {
+		panic(fmt.Sprintf("metrics: %s butuh %d label, diberi %d", d.name, len(d.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+// labels menulis {a="x",b="y"} untuk nilai label dalam key, ditambah pasangan extra.
+func (d *desc) labels(key string, extra ...string) string {
+	var values []string
+	if len(d.labelNames) > 0 {
+		values = strings.Split(key, "\xff")
+	}
+	if len(values)+len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(values)+len(extra)/2)
+	for i, value := range values {
+		pairs = append(pairs, d.labelNames[i]+`="`+escapeLabel(value)+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer("\\", `\\`, "\"", `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](series map[string]V) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// valueVec adalah counter atau gauge dengan label.
+type valueVec struct {
+	desc
+	mutex  sync.Mutex
+	series map[string]float64
+}
+
+func (v *valueVec) write(w *bufio.Writer) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.writeHeader(w)
+	for _, key := range sortedKeys(v.series) {
+		fmt.Fprintf(w, "%s%s %s\n", v.name, v.labels(key), formatValue(v.series[key]))
+	}
+}
+
+// CounterVec adalah counter yang hanya bisa bertambah, satu series per kombinasi label.
+type CounterVec struct {
+	valueVec
+}
+
+// NewCounterVec mendaftarkan counter baru di r.
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{valueVec{desc: desc{name, help, "counter", labelNames}, series: make(map[string]float64)}}
+	r.register(name, c)
+	return c
+}
+
+// Add menambah counter dengan value (harus >= 0).
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		panic("metrics: counter " + c.name + " tidak boleh berkurang")
+	}
+	key := c.key(labelValues)
+	c.mutex.Lock()
+	c.series[key] += value
+	c.mutex.Unlock()
+}
+
+// Inc menambah counter dengan 1.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// GaugeVec adalah nilai yang bisa naik turun, satu series per kombinasi label.
+type GaugeVec struct {
+	valueVec
+}
+
+// NewGaugeVec mendaftarkan gauge baru di r.
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	g := &GaugeVec{valueVec{desc: desc{name, help, "gauge", labelNames}, series: make(map[string]float64)}}
+	r.register(name, g)
+	return g
+}
+
+// Set mengganti nilai gauge.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	key := g.key(labelValues)
+	g.mutex.Lock()
+	g.series[key] = value
+	g.mutex.Unlock()
+}
+
+// gaugeFunc adalah gauge tanpa label yang nilainya dibaca saat metrik ditulis.
+type gaugeFunc struct {
+	desc
+	value func() float64
+}
+
+func (g *gaugeFunc) write(w *bufio.Writer) {
+	g.writeHeader(w)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatValue(g.value()))
+}
+
+// NewGaugeFunc mendaftarkan gauge yang nilainya diambil dari value setiap kali ditulis.
+func (r *Registry) NewGaugeFunc(name, help string, value func() float64) {
+	r.register(name, &gaugeFunc{desc: desc{name: name, help: help, kind: "gauge"}, value: value})
+}
+
+// HistogramVec menghitung sebaran nilai ke dalam bucket kumulatif, satu series per
+// kombinasi label.
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mutex   sync.Mutex
+	series  map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec mendaftarkan histogram baru di r. buckets harus urut naik; nil berarti
+// DefaultBuckets.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &HistogramVec{
+		desc:    desc{name, help, "histogram", labelNames},
+		buckets: buckets,
+		series:  make(map[string]*histogram),
+	}
+	r.register(name, h)
+	return h
+}
+
+// Observe mencatat satu nilai.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	series, exists := h.series[key]
+	if !exists {
+		series = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = series
+	}
+	// Bucket disimpan tidak kumulatif; write yang menjumlahkan.
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		series.counts[i]++
+	}
+	series.count++
+	series.sum += value
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.writeHeader(w)
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labels(key, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labels(key, "le", "+Inf"), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labels(key), formatValue(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labels(key), series.count)
+	}
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("test_requests_total", "Jumlah request.", "path", "status")
+	requests.Inc("/b", "200")
+	requests.Add(2, "/a", "200")
+	requests.Inc(`/q"x\`, "500")
+	r.NewGaugeVec("test_elements", "Jumlah elemen\nper dataset.", "dataset").Set(619, "la2")
+	r.NewGaugeFunc("test_up", "Selalu 1.", func() float64 { return 1 })
+	latency := r.NewHistogramVec("test_latency_seconds", "Latency.", []float64{0.1, 1}, "algorithm")
+	for _, value := range []float64{0.05, 0.1, 0.5, 3} {
+		latency.Observe(value, "bfs")
+	}
+
+	var out strings.Builder
+	if err := r.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_elements Jumlah elemen\nper dataset.
+# TYPE test_elements gauge
+test_elements{dataset="la2"} 619
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{algorithm="bfs",le="0.1"} 2
+test_latency_seconds_bucket{algorithm="bfs",le="1"} 3
+test_latency_seconds_bucket{algorithm="bfs",le="+Inf"} 4
+test_latency_seconds_sum{algorithm="bfs"} 3.65
+test_latency_seconds_count{algorithm="bfs"} 4
+# HELP test_requests_total Jumlah request.
+# TYPE test_requests_total counter
+test_requests_total{path="/a",status="200"} 2
+test_requests_total{path="/b",status="200"} 1
+test_requests_total{path="/q\"x\\",status="500"} 1
+# HELP test_up Selalu 1.
+# TYPE test_up gauge
+test_up 1
+`
+	if out.String() != want {
+		t.Errorf("WriteText =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRegistryPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(r *Registry)
+	}{
+		{name: "nama ganda", run: func(r *Registry) {
+			r.NewCounterVec("dup", "a")
+			r.NewGaugeVec("dup", "b")
+		}},
+		{name: "jumlah label salah", run: func(r *Registry) {
+			r.NewCounterVec("labels", "a", "x", "y").Inc("satu")
+		}},
+		{name: "counter berkurang", run: func(r *Registry) {
+			r.NewCounterVec("down", "a").Add(-1)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("tidak panic")
+				}
+			}()
+			tt.run(NewRegistry())
+		})
+	}
+}
+
+func TestExponentialBuckets(t *testing.T) {
+	got := ExponentialBuckets(1, 10, 3)
+	if len(got) != 3 || got[0] != 1 || got[1] != 10 || got[2] != 100 {
+		t.Errorf("ExponentialBuckets(1, 10, 3) = %v", got)
+	}
+}
@@ -0,0 +1,22 @@
+package pathfinding
+
+import "github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+
+// StepFromCompact mengubah satu CompactStep kembali menjadi PathStep berbasis nama.
+func StepFromCompact(cg *loadrecipes.CompactGraph, step loadrecipes.CompactStep) PathStep {
+	return PathStep{
+		ChildName:   cg.Name(step.Child),
+		Parent1Name: cg.Name(step.Pair.Mat1),
+		Parent2Name: cg.Name(step.Pair.Mat2),
+	}
+}
+
+// StepsFromCompact mengubah jalur CompactStep menjadi []PathStep. Dipakai di batas
+// hasil algoritma, setelah pencarian selesai dengan ElementID.
+func StepsFromCompact(cg *loadrecipes.CompactGraph, steps []loadrecipes.CompactStep) []PathStep {
+	path := make([]PathStep, len(steps))
+	for i, step := range steps {
+		path[i] = StepFromCompact(cg, step)
+	}
+	return path
+}
@@ -0,0 +1,31 @@
+package pathfinding
+
+// Alasan yang bisa dikembalikan di Diagnosis.Reason.
+const (
+	ReasonCraftable          = "craftable"
+	ReasonUnknownElement     = "unknown_element"
+	ReasonNoRecipes          = "no_recipes"
+	ReasonBlockedIngredients = "blocked_by_uncraftable"
+	ReasonBudgetExceeded     = "budget_exceeded"
+	ReasonExcludedByFilter   = "excluded_by_constraints"
+)
+
+// BlockedRecipe menjelaskan kenapa satu resep dari elemen target tidak bisa dipakai.
+type BlockedRecipe struct {
+	Parent1Name string `json:"parent1Name"`
+	Parent2Name string `json:"parent2Name"`
+	BlockedBy   string `json:"blockedBy"`
+}
+
+// Diagnosis menjelaskan kenapa sebuah elemen tidak bisa (atau tidak berhasil) dibuat.
+// BlockingChain berisi rantai target -> bahan -> ... sampai elemen penyebab utama
+// (elemen tanpa resep, atau elemen yang kembali ke rantai sehingga membentuk siklus).
+type Diagnosis struct {
+	Element        string          `json:"element"`
+	Craftable      bool            `json:"craftable"`
+	Reason         string          `json:"reason"`
+	Message        string          `json:"message"`
+	BlockingChain  []string        `json:"blockingChain,omitempty"`
+	BlockedRecipes []BlockedRecipe `json:"blockedRecipes,omitempty"`
+	NodesVisited   int             `json:"nodesVisited"`
+}
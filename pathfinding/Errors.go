@@ -0,0 +1,96 @@
+package pathfinding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Jenis kegagalan pencarian. Error yang dikembalikan algoritma membungkus salah satu nilai
+// ini, jadi pemanggil cukup mengecek dengan errors.Is(err, pathfinding.ErrUnknownElement).
+var (
+	ErrUnknownElement = errors.New("elemen tidak dikenal")
+	ErrNoRecipe       = errors.New("tidak ada resep")
+	ErrInvalidParam   = errors.New("parameter tidak valid")
+	ErrBudgetExceeded = errors.New("batas pencarian tercapai")
+)
+
+// Kode error yang stabil untuk tiap jenis kegagalan, dipakai di respons API.
+const (
+	CodeUnknownElement = "unknown_element"
+	CodeNoRecipe       = "no_recipe"
+	CodeInvalidParam   = "invalid_param"
+	CodeBudgetExceeded = "budget_exceeded"
+)
+
+// SearchError adalah kegagalan pencarian dengan jenis (Kind, salah satu Err*), pesan untuk
+// pengguna, dan detail terstruktur (mis. nama elemen atau parameter yang salah).
+type SearchError struct {
+	Kind    error
+	Message string
+	Details map[string]interface{}
+}
+
+func (e *SearchError) Error() string {
+	return e.Message
+}
+
+func (e *SearchError) Unwrap() error {
+	return e.Kind
+}
+
+// Code mengembalikan kode error stabil untuk Kind.
+func (e *SearchError) Code() string {
+	return ErrorCode(e.Kind)
+}
+
+// ErrorCode mengembalikan kode error untuk err jika err membungkus salah satu Err*, atau "".
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrUnknownElement):
+		return CodeUnknownElement
+	case errors.Is(err, ErrNoRecipe):
+		return CodeNoRecipe
+	case errors.Is(err, ErrInvalidParam):
+		return CodeInvalidParam
+	case errors.Is(err, ErrBudgetExceeded):
+		return CodeBudgetExceeded
+	}
+	return ""
+}
+
+// UnknownElementError dikembalikan jika elemen target tidak ada di graf.
+func UnknownElementError(element string) error {
+	return &SearchError{
+		Kind:    ErrUnknownElement,
+		Message: fmt.Sprintf("elemen target '%s' tidak ditemukan dalam data", element),
+		Details: map[string]interface{}{"element": element},
+	}
+}
+
+// NoRecipeError dikembalikan jika pencarian selesai tanpa menemukan resep untuk element.
+func NoRecipeError(element string, nodesVisited int) error {
+	return &SearchError{
+		Kind:    ErrNoRecipe,
+		Message: fmt.Sprintf("tidak ada jalur resep yang ditemukan untuk elemen '%s'", element),
+		Details: map[string]interface{}{"element": element, "nodesVisited": nodesVisited},
+	}
+}
+
+// InvalidParamError dikembalikan untuk parameter pencarian yang tidak valid.
+func InvalidParamError(param string, value interface{}, reason string) error {
+	return &SearchError{
+		Kind:    ErrInvalidParam,
+		Message: fmt.Sprintf("parameter %s %s", param, reason),
+		Details: map[string]interface{}{"param": param, "value": value},
+	}
+}
+
+// BudgetExceededError dikembalikan jika pencarian berhenti karena batas iterasi sebelum
+// menemukan resep, padahal ruang pencarian belum habis.
+func BudgetExceededError(element string, limit int, nodesVisited int) error {
+	return &SearchError{
+		Kind:    ErrBudgetExceeded,
+		Message: fmt.Sprintf("pencarian resep '%s' berhenti di batas %d iterasi sebelum menemukan resep", element, limit),
+		Details: map[string]interface{}{"element": element, "limit": limit, "nodesVisited": nodesVisited},
+	}
+}
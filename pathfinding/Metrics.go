@@ -0,0 +1,28 @@
+package pathfinding
+
+import "github.com/Starath/Tubes2_BE_SayMyName/metrics"
+
+// Metrik algoritma pencarian, menggantikan log per iterasi yang tidak bisa diagregasi.
+var (
+	searchNodesVisited = metrics.Default.NewHistogramVec("alchemy_search_nodes_visited",
+		"Jumlah node yang dieksplorasi per pencarian.", metrics.ExponentialBuckets(1, 4, 12), "algorithm")
+	searchGoroutines = metrics.Default.NewCounterVec("alchemy_search_goroutines_total",
+		"Jumlah goroutine worker yang diluncurkan algoritma pencarian.", "algorithm")
+	searchIterations = metrics.Default.NewHistogramVec("alchemy_search_iterations",
+		"Jumlah iterasi (level) per pencarian.", metrics.ExponentialBuckets(1, 2, 10), "algorithm")
+)
+
+// ObserveSearch mencatat jumlah node yang dieksplorasi satu pencarian algorithm.
+func ObserveSearch(algorithm string, nodesVisited int) {
+	searchNodesVisited.Observe(float64(nodesVisited), algorithm)
+}
+
+// ObserveIterations mencatat jumlah iterasi satu pencarian algorithm.
+func ObserveIterations(algorithm string, iterations int) {
+	searchIterations.Observe(float64(iterations), algorithm)
+}
+
+// CountGoroutines mencatat n goroutine worker yang diluncurkan algorithm.
+func CountGoroutines(algorithm string, n int) {
+	searchGoroutines.Add(float64(n), algorithm)
+}
@@ -0,0 +1,125 @@
+package pathfinding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+// RecipeTree adalah bentuk kanonis sebuah resep: himpunan step unik dengan parent
+// terurut (Parent1Name <= Parent2Name), diurutkan berdasarkan child lalu parent.
+// Dua jalur dengan step yang sama tapi urutan berbeda menghasilkan RecipeTree yang sama,
+// apapun algoritma yang menemukannya.
+type RecipeTree struct {
+	Steps []PathStep
+	hash  uint64
+}
+
+// NewRecipeTree membuat RecipeTree kanonis dari daftar step. Slice input tidak diubah.
+func NewRecipeTree(steps []PathStep) RecipeTree {
+	canonical := make([]PathStep, len(steps))
+	copy(canonical, steps)
+
+	for i := range canonical {
+		if canonical[i].Parent1Name > canonical[i].Parent2Name {
+			canonical[i].Parent1Name, canonical[i].Parent2Name = canonical[i].Parent2Name, canonical[i].Parent1Name
+		}
+	}
+
+	sort.Slice(canonical, func(i, j int) bool {
+		if canonical[i].ChildName != canonical[j].ChildName {
+			return canonical[i].ChildName < canonical[j].ChildName
+		}
+		if canonical[i].Parent1Name != canonical[j].Parent1Name {
+			return canonical[i].Parent1Name < canonical[j].Parent1Name
+		}
+		return canonical[i].Parent2Name < canonical[j].Parent2Name
+	})
+
+	// Step yang identik cukup muncul sekali.
+	unique := canonical[:0]
+	for i, step := range canonical {
+		if i > 0 && step == canonical[i-1] {
+			continue
+		}
+		unique = append(unique, step)
+	}
+
+	tree := RecipeTree{Steps: unique}
+	tree.hash = hashSteps(unique)
+	return tree
+}
+
+// NewRecipeTreeFromCompact membuat RecipeTree dari jalur CompactStep hasil pencarian.
+func NewRecipeTreeFromCompact(cg *loadrecipes.CompactGraph, steps []loadrecipes.CompactStep) RecipeTree {
+	return NewRecipeTree(StepsFromCompact(cg, steps))
+}
+
+// hashSteps menghitung FNV-1a 64-bit dari step kanonis. Nama dipisah byte kontrol
+// supaya ("ab","c") dan ("a","bc") tidak bertabrakan.
+func hashSteps(steps []PathStep) uint64 {
+	h := fnv.New64a()
+	for _, step := range steps {
+		h.Write([]byte(step.ChildName))
+		h.Write([]byte{0x1f})
+		h.Write([]byte(step.Parent1Name))
+		h.Write([]byte{0x1f})
+		h.Write([]byte(step.Parent2Name))
+		h.Write([]byte{0x1e})
+	}
+	return h.Sum64()
+}
+
+// Hash mengembalikan hash 64-bit dari bentuk kanonis resep.
+func (t RecipeTree) Hash() uint64 {
+	return t.hash
+}
+
+// ID mengembalikan ID pendek (12 karakter hex) yang stabil untuk resep ini.
+// ID hanya bergantung pada nama elemen, jadi sama antar algoritma dan antar proses.
+func (t RecipeTree) ID() string {
+	return fmt.Sprintf("%012x", t.hash>>16)
+}
+
+// Equal mengecek apakah dua RecipeTree berisi step yang sama persis.
+func (t RecipeTree) Equal(other RecipeTree) bool {
+	if t.hash != other.hash || len(t.Steps) != len(other.Steps) {
+		return false
+	}
+	for i := range t.Steps {
+		if t.Steps[i] != other.Steps[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// RecipeSet menyimpan RecipeTree unik. Dipakai semua algoritma untuk dedupe hasil.
+// Tabrakan hash ditangani dengan Equal.
+type RecipeSet struct {
+	byHash map[uint64][]RecipeTree
+	count  int
+}
+
+func NewRecipeSet() *RecipeSet {
+	return &RecipeSet{byHash: make(map[uint64][]RecipeTree)}
+}
+
+// Add menambahkan tree ke set. Mengembalikan false jika resep yang sama sudah ada.
+func (s *RecipeSet) Add(tree RecipeTree) bool {
+	for _, existing := range s.byHash[tree.hash] {
+		if existing.Equal(tree) {
+			return false
+		}
+	}
+	s.byHash[tree.hash] = append(s.byHash[tree.hash], tree)
+	s.count++
+	return true
+}
+
+// Len mengembalikan jumlah resep unik di dalam set.
+func (s *RecipeSet) Len() int {
+	return s.count
+}
@@ -0,0 +1,129 @@
+package pathfinding_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/bfs"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/bis"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/dfs"
+)
+
+// brickSteps adalah resep Brick dengan urutan step dan parent seperti hasil pencarian.
+var brickSteps = []pathfinding.PathStep{
+	{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Water"},
+	{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+}
+
+func TestRecipeTreeID(t *testing.T) {
+	want := pathfinding.NewRecipeTree(brickSteps)
+	if len(want.ID()) != 12 {
+		t.Fatalf("ID() = %q, want 12 karakter hex", want.ID())
+	}
+
+	tests := []struct {
+		name  string
+		steps []pathfinding.PathStep
+		same  bool
+	}{
+		{name: "urutan step dibalik", same: true, steps: []pathfinding.PathStep{
+			{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+			{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Water"},
+		}},
+		{name: "urutan parent ditukar", same: true, steps: []pathfinding.PathStep{
+			{ChildName: "Mud", Parent1Name: "Water", Parent2Name: "Earth"},
+			{ChildName: "Brick", Parent1Name: "Fire", Parent2Name: "Mud"},
+		}},
+		{name: "step duplikat", same: true, steps: []pathfinding.PathStep{
+			{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Water"},
+			{ChildName: "Mud", Parent1Name: "Water", Parent2Name: "Earth"},
+			{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+		}},
+		{name: "parent berbeda", same: false, steps: []pathfinding.PathStep{
+			{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Rain"},
+			{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+		}},
+		{name: "batas nama tidak bergeser", same: false, steps: []pathfinding.PathStep{
+			{ChildName: "Mu", Parent1Name: "dEarth", Parent2Name: "Water"},
+			{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+		}},
+		{name: "step kurang", same: false, steps: brickSteps[1:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := pathfinding.NewRecipeTree(tt.steps)
+			if got := tree.ID() == want.ID(); got != tt.same {
+				t.Errorf("ID() = %s, ID Brick = %s; sama = %v, want %v", tree.ID(), want.ID(), got, tt.same)
+			}
+			if got := tree.Equal(want); got != tt.same {
+				t.Errorf("Equal() = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestRecipeTreeDoesNotModifyInput(t *testing.T) {
+	steps := []pathfinding.PathStep{
+		{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+		{ChildName: "Mud", Parent1Name: "Water", Parent2Name: "Earth"},
+	}
+	pathfinding.NewRecipeTree(steps)
+	if steps[0].ChildName != "Brick" || steps[1].Parent1Name != "Water" {
+		t.Errorf("NewRecipeTree mengubah input: %+v", steps)
+	}
+}
+
+func TestRecipeSet(t *testing.T) {
+	set := pathfinding.NewRecipeSet()
+	if !set.Add(pathfinding.NewRecipeTree(brickSteps)) {
+		t.Fatal("Add pertama ditolak")
+	}
+	if set.Add(pathfinding.NewRecipeTree([]pathfinding.PathStep{brickSteps[1], brickSteps[0]})) {
+		t.Error("resep yang sama dengan urutan berbeda ditambahkan dua kali")
+	}
+	if !set.Add(pathfinding.NewRecipeTree(brickSteps[:1])) {
+		t.Error("resep berbeda ditolak")
+	}
+	if set.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", set.Len())
+	}
+}
+
+// TestRecipeIDSameAcrossAlgorithms memastikan ketiga algoritma memberi RecipeID yang sama
+// untuk resep yang sama, sehingga hasil bisa dibandingkan antar algoritma.
+func TestRecipeIDSameAcrossAlgorithms(t *testing.T) {
+	const dataset = `[
+		{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+		{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"]]}
+	]`
+	graph, err := loadrecipes.LoadBiGraphFromReader(strings.NewReader(dataset), "brick", loadrecipes.DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	want := pathfinding.NewRecipeTree(brickSteps).ID()
+
+	dfsResult, err := dfs.DFSFindPathString(graph, "Brick")
+	if err != nil {
+		t.Fatalf("DFS: %v", err)
+	}
+	bfsResult, err := bfs.BFSFindPath(graph, "Brick", 1)
+	if err != nil || len(bfsResult.Results) != 1 {
+		t.Fatalf("BFS: %v, %+v", err, bfsResult)
+	}
+	bisResult, _, err := bis.BiSFindMultiplePaths(graph, "Brick", 1)
+	if err != nil || len(bisResult.Results) != 1 {
+		t.Fatalf("BiS: %v, %+v", err, bisResult)
+	}
+
+	for algorithm, id := range map[string]string{
+		"dfs": dfsResult.RecipeID,
+		"bfs": bfsResult.Results[0].RecipeID,
+		"bis": bisResult.Results[0].RecipeID,
+	} {
+		if id != want {
+			t.Errorf("%s: RecipeID = %s, want %s", algorithm, id, want)
+		}
+	}
+}
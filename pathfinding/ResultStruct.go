@@ -9,8 +9,18 @@ type PathStep struct {
 type Result struct {
 	Path         []PathStep
 	NodesVisited int
+	RecipeID     string
 }
 
 type MultipleResult struct {
 	Results             []Result
 }
+
+// NewResult membuat Result dan mengisi RecipeID dari bentuk kanonis path.
+func NewResult(path []PathStep, nodesVisited int) Result {
+	return Result{
+		Path:         path,
+		NodesVisited: nodesVisited,
+		RecipeID:     NewRecipeTree(path).ID(),
+	}
+}
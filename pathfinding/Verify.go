@@ -0,0 +1,200 @@
+package pathfinding
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+// Kode masalah yang bisa dilaporkan oleh VerifyRecipe.
+const (
+	IssueUnknownElement    = "unknown_element"
+	IssueInvalidRecipe     = "invalid_recipe"
+	IssueMissingIngredient = "missing_ingredient"
+	IssueCycle             = "cycle"
+	IssueTargetNotProduced = "target_not_produced"
+	IssueInvalidNode       = "invalid_node"
+	IssueEmptyRecipe       = "empty_recipe"
+)
+
+// RecipeNode adalah bentuk resep bertingkat (nested): sebuah elemen beserta dua bahannya.
+// Node tanpa Parents berarti bahan yang dianggap sudah tersedia (harus elemen dasar).
+type RecipeNode struct {
+	Name    string        `json:"name"`
+	Parents []*RecipeNode `json:"parents,omitempty"`
+}
+
+// StepIssue adalah satu masalah pada resep. StepIndex = -1 untuk masalah yang
+// berlaku ke resep secara keseluruhan.
+type StepIssue struct {
+	StepIndex int    `json:"stepIndex"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+// VerificationResult adalah hasil pengecekan resep terhadap BiGraphAlchemy.
+type VerificationResult struct {
+	Valid    bool        `json:"valid"`
+	Target   string      `json:"target"`
+	RecipeID string      `json:"recipeId,omitempty"`
+	Steps    []PathStep  `json:"steps"`
+	Issues   []StepIssue `json:"issues"`
+}
+
+// FlattenRecipeTree mengubah RecipeNode menjadi daftar PathStep dengan urutan
+// post-order, sehingga bahan selalu dibuat sebelum elemen yang memakainya.
+func FlattenRecipeTree(root *RecipeNode) ([]PathStep, []StepIssue) {
+	var steps []PathStep
+	var issues []StepIssue
+
+	var walk func(node *RecipeNode)
+	walk = func(node *RecipeNode) {
+		if node == nil {
+			return
+		}
+		switch len(node.Parents) {
+		case 0:
+			return
+		case 2:
+			if node.Parents[0] == nil || node.Parents[1] == nil {
+				issues = append(issues, StepIssue{StepIndex: -1, Code: IssueInvalidNode, Message: fmt.Sprintf("node '%s' memiliki bahan kosong", node.Name)})
+				return
+			}
+			walk(node.Parents[0])
+			walk(node.Parents[1])
+			steps = append(steps, PathStep{ChildName: node.Name, Parent1Name: node.Parents[0].Name, Parent2Name: node.Parents[1].Name})
+		default:
+			issues = append(issues, StepIssue{StepIndex: -1, Code: IssueInvalidNode, Message: fmt.Sprintf("node '%s' harus memiliki tepat 2 bahan, ditemukan %d", node.Name, len(node.Parents))})
+		}
+	}
+	walk(root)
+	return steps, issues
+}
+
+// VerifyRecipe mengecek daftar step terhadap graf: setiap step harus resep yang ada,
+// setiap bahan harus elemen dasar atau dibuat oleh step sebelumnya, tidak ada siklus,
+// dan target benar-benar dihasilkan. Jika target kosong, child dari step terakhir dipakai.
+func VerifyRecipe(graph *loadrecipes.BiGraphAlchemy, target string, steps []PathStep) *VerificationResult {
+	if target == "" && len(steps) > 0 {
+		target = steps[len(steps)-1].ChildName
+	}
+	result := &VerificationResult{Target: target, Steps: steps, Issues: []StepIssue{}}
+
+	if target == "" {
+		result.Issues = append(result.Issues, StepIssue{StepIndex: -1, Code: IssueEmptyRecipe, Message: "resep tidak memiliki target maupun step"})
+		return result
+	}
+	if !graph.AllElements[target] {
+		result.Issues = append(result.Issues, StepIssue{StepIndex: -1, Code: IssueUnknownElement, Message: fmt.Sprintf("elemen target '%s' tidak ditemukan dalam data", target)})
+	}
+
+	producedAt := make(map[string]int)
+	for i, step := range steps {
+		for _, name := range []string{step.ChildName, step.Parent1Name, step.Parent2Name} {
+			if !graph.AllElements[name] {
+				result.Issues = append(result.Issues, StepIssue{StepIndex: i, Code: IssueUnknownElement, Message: fmt.Sprintf("elemen '%s' tidak ditemukan dalam data", name)})
+			}
+		}
+
+		pair := loadrecipes.ConstructPair(step.Parent1Name, step.Parent2Name)
+		if !loadrecipes.ContainsString(graph.ParentPairToChild[pair], step.ChildName) {
+			result.Issues = append(result.Issues, StepIssue{StepIndex: i, Code: IssueInvalidRecipe, Message: fmt.Sprintf("%s + %s tidak menghasilkan %s", step.Parent1Name, step.Parent2Name, step.ChildName)})
+		}
+
+		for _, ingredient := range []string{step.Parent1Name, step.Parent2Name} {
+			if graph.BaseElements[ingredient] {
+				continue
+			}
+			if _, produced := producedAt[ingredient]; !produced {
+				result.Issues = append(result.Issues, StepIssue{StepIndex: i, Code: IssueMissingIngredient, Message: fmt.Sprintf("bahan '%s' bukan elemen dasar dan belum dibuat oleh step sebelumnya", ingredient)})
+			}
+		}
+
+		if _, produced := producedAt[step.ChildName]; !produced {
+			producedAt[step.ChildName] = i
+		}
+	}
+
+	for _, cycle := range findStepCycles(steps, graph.BaseElements) {
+		result.Issues = append(result.Issues, StepIssue{StepIndex: cycle.stepIndex, Code: IssueCycle, Message: "siklus resep: " + strings.Join(cycle.chain, " -> ")})
+	}
+
+	if !graph.BaseElements[target] {
+		if _, produced := producedAt[target]; !produced {
+			result.Issues = append(result.Issues, StepIssue{StepIndex: -1, Code: IssueTargetNotProduced, Message: fmt.Sprintf("tidak ada step yang menghasilkan target '%s'", target)})
+		}
+	}
+
+	result.Valid = len(result.Issues) == 0
+	if result.Valid {
+		result.RecipeID = NewRecipeTree(steps).ID()
+	}
+	return result
+}
+
+// HasStepCycle mengecek apakah ada elemen di steps yang (lewat step lain) bergantung pada
+// dirinya sendiri. Hanya mungkin terjadi pada graf yang dimuat tanpa aturan tier.
+func HasStepCycle(steps []PathStep) bool {
+	return len(findStepCycles(steps, nil)) > 0
+}
+
+type stepCycle struct {
+	stepIndex int
+	chain     []string
+}
+
+// findStepCycles mencari siklus pada graf dependensi child -> bahan dari daftar step.
+// Setiap siklus dilaporkan sekali, pada step pertama yang membuat elemen awal siklus.
+func findStepCycles(steps []PathStep, baseElements map[string]bool) []stepCycle {
+	dependsOn := make(map[string][]string)
+	firstStep := make(map[string]int)
+	var order []string
+	for i, step := range steps {
+		if _, seen := firstStep[step.ChildName]; !seen {
+			firstStep[step.ChildName] = i
+			order = append(order, step.ChildName)
+		}
+		for _, ingredient := range []string{step.Parent1Name, step.Parent2Name} {
+			if !baseElements[ingredient] && !loadrecipes.ContainsString(dependsOn[step.ChildName], ingredient) {
+				dependsOn[step.ChildName] = append(dependsOn[step.ChildName], ingredient)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles []stepCycle
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = onStack
+		stack = append(stack, name)
+		for _, next := range dependsOn[name] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case onStack:
+				start := len(stack) - 1
+				for stack[start] != next {
+					start--
+				}
+				chain := append(append([]string{}, stack[start:]...), next)
+				cycles = append(cycles, stepCycle{stepIndex: firstStep[next], chain: chain})
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+	for _, name := range order {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+	return cycles
+}
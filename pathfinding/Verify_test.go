@@ -0,0 +1,98 @@
+package pathfinding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+// verifyDataset dimuat tanpa filter supaya resep bersiklus Mud <- Brick bisa diuji.
+const verifyDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"], ["Brick", "Water"]]},
+	{"name": "Brick", "tier": 2, "recipes": [["Mud", "Fire"]]}
+]`
+
+func issueCodes(result *VerificationResult) []string {
+	codes := []string{}
+	for _, issue := range result.Issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestVerifyRecipe(t *testing.T) {
+	graph, err := loadrecipes.LoadBiGraphFromReader(strings.NewReader(verifyDataset), "verify", loadrecipes.LoadOptions{Policy: loadrecipes.NoFilterPolicy})
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	mud := PathStep{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Water"}
+	brick := PathStep{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"}
+
+	tests := []struct {
+		name      string
+		target    string
+		steps     []PathStep
+		wantCodes []string
+	}{
+		{name: "valid", target: "Brick", steps: []PathStep{mud, brick}, wantCodes: []string{}},
+		{name: "target dari step terakhir", steps: []PathStep{mud, brick}, wantCodes: []string{}},
+		{name: "parent ditukar tetap valid", target: "Mud", steps: []PathStep{{ChildName: "Mud", Parent1Name: "Water", Parent2Name: "Earth"}}, wantCodes: []string{}},
+		{name: "elemen dasar tanpa step", target: "Fire", wantCodes: []string{}},
+		{name: "kosong", wantCodes: []string{IssueEmptyRecipe}},
+		{name: "bahan belum dibuat", target: "Brick", steps: []PathStep{brick}, wantCodes: []string{IssueMissingIngredient}},
+		{name: "resep tidak ada", target: "Mud", steps: []PathStep{{ChildName: "Mud", Parent1Name: "Fire", Parent2Name: "Water"}}, wantCodes: []string{IssueInvalidRecipe}},
+		{
+			name: "elemen tidak dikenal", target: "Mud",
+			steps:     []PathStep{{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Slime"}},
+			wantCodes: []string{IssueUnknownElement, IssueInvalidRecipe, IssueMissingIngredient},
+		},
+		{name: "target tidak dibuat", target: "Brick", steps: []PathStep{mud}, wantCodes: []string{IssueTargetNotProduced}},
+		{
+			name: "siklus", target: "Brick",
+			steps:     []PathStep{{ChildName: "Mud", Parent1Name: "Brick", Parent2Name: "Water"}, brick},
+			wantCodes: []string{IssueMissingIngredient, IssueCycle},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyRecipe(graph, tt.target, tt.steps)
+			if got := issueCodes(result); !reflect.DeepEqual(got, tt.wantCodes) {
+				t.Errorf("issues = %v (%+v), want %v", got, result.Issues, tt.wantCodes)
+			}
+			wantValid := len(tt.wantCodes) == 0
+			if result.Valid != wantValid {
+				t.Errorf("Valid = %v, want %v", result.Valid, wantValid)
+			}
+			if wantValid != (result.RecipeID != "") {
+				t.Errorf("RecipeID = %q untuk Valid = %v", result.RecipeID, result.Valid)
+			}
+		})
+	}
+}
+
+func TestFlattenRecipeTree(t *testing.T) {
+	base := func(name string) *RecipeNode { return &RecipeNode{Name: name} }
+	root := &RecipeNode{Name: "Brick", Parents: []*RecipeNode{
+		{Name: "Mud", Parents: []*RecipeNode{base("Earth"), base("Water")}},
+		base("Fire"),
+	}}
+	steps, issues := FlattenRecipeTree(root)
+	want := []PathStep{
+		{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Water"},
+		{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+	}
+	if len(issues) != 0 || !reflect.DeepEqual(steps, want) {
+		t.Errorf("FlattenRecipeTree = %+v, %+v; want %+v tanpa issue", steps, issues, want)
+	}
+
+	for _, node := range []*RecipeNode{
+		{Name: "Mud", Parents: []*RecipeNode{base("Earth")}},
+		{Name: "Mud", Parents: []*RecipeNode{base("Earth"), nil}},
+	} {
+		if _, issues := FlattenRecipeTree(node); len(issues) != 1 || issues[0].Code != IssueInvalidNode {
+			t.Errorf("FlattenRecipeTree(%d bahan) issues = %+v, want satu invalid_node", len(node.Parents), issues)
+		}
+	}
+}
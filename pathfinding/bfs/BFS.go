@@ -2,7 +2,6 @@ package bfs
 
 import (
 	"container/list"
-	"fmt"
 	"log"
 	"sort"
 	"sync"
@@ -16,137 +15,148 @@ const (
 	DefaultWorkerMaxIterations = 2500000
 )
 
-// reversePathStepsBFS membalik urutan slice PathStep
-func reversePathStepsBFS(steps []pathfinding.PathStep) {
-	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
-		steps[i], steps[j] = steps[j], steps[i]
-	}
+// BFSPathNode adalah simpul linked list (persisten) untuk langkah-langkah yang sudah diambil.
+// State anak berbagi prefix path dengan state induknya, jadi path tidak perlu disalin per state.
+type BFSPathNode struct {
+	Step   loadrecipes.CompactStep
+	Prev   *BFSPathNode
+	Length int
 }
 
-// createPathSignature membuat string unik untuk sebuah path agar bisa deteksi duplikasinya. Path yang diberikan sudah dalam urutan base-ke-target.
-func createPathSignature(steps []pathfinding.PathStep) string {
-	pathCopy := make([]pathfinding.PathStep, len(steps))
-	copy(pathCopy, steps)
+// toSteps mengembalikan path dalam urutan base-ke-target. Langkah terakhir yang diambil
+// (paling dekat ke base) ada di kepala linked list, jadi cukup ditelusuri dari kepala.
+func (node *BFSPathNode) toSteps() []loadrecipes.CompactStep {
+	if node == nil {
+		return []loadrecipes.CompactStep{}
+	}
+	steps := make([]loadrecipes.CompactStep, 0, node.Length)
+	for current := node; current != nil; current = current.Prev {
+		steps = append(steps, current.Step)
+	}
+	return steps
+}
 
-	for i := range pathCopy {
-		if pathCopy[i].Parent1Name > pathCopy[i].Parent2Name {
-			pathCopy[i].Parent1Name, pathCopy[i].Parent2Name = pathCopy[i].Parent2Name, pathCopy[i].Parent1Name
+// produces mengecek apakah path sudah berisi step yang membuat id.
+func (node *BFSPathNode) produces(id loadrecipes.ElementID) bool {
+	for current := node; current != nil; current = current.Prev {
+		if current.Step.Child == id {
+			return true
 		}
 	}
+	return false
+}
 
-	// Urutkan langkah-langkah dalam path untuk memastikan path dengan set step yang sama
-	// namun urutan berbeda dianggap identik.
-	sort.Slice(pathCopy, func(i, j int) bool {
-		if pathCopy[i].ChildName != pathCopy[j].ChildName {
-			return pathCopy[i].ChildName < pathCopy[j].ChildName
-		}
-		if pathCopy[i].Parent1Name != pathCopy[j].Parent1Name {
-			return pathCopy[i].Parent1Name < pathCopy[j].Parent1Name
-		}
-		return pathCopy[i].Parent2Name < pathCopy[j].Parent2Name
-	})
+// bfsMaxIterations adalah batas state yang diproses satu pencarian BFS.
+const bfsMaxIterations = 5000000
+
+// bfsBudget adalah batas iterasi yang dibagi semua worker satu pencarian BFS, ditambah sinyal
+// berhenti saat hasil sudah cukup. Tanpa budget bersama, setiap worker bisa memproses
+// bfsMaxIterations state sendiri-sendiri walaupun pencarian sudah selesai.
+type bfsBudget struct {
+	used int64
+	done <-chan struct{}
+}
 
-	var signature string
-	for _, step := range pathCopy {
-		signature += fmt.Sprintf("%s=(%s+%s);", step.ChildName, step.Parent1Name, step.Parent2Name)
+// take mengambil satu iterasi. Hasilnya false jika budget habis atau pencarian dihentikan.
+func (b *bfsBudget) take() bool {
+	used := atomic.AddInt64(&b.used, 1)
+	if used > bfsMaxIterations {
+		return false
 	}
-	return signature
+	if b.done != nil && used%1024 == 0 {
+		select {
+		case <-b.done:
+			return false
+		default:
+		}
+	}
+	return true
+}
+
+// exhausted mengecek apakah batas iterasi sudah tercapai.
+func (b *bfsBudget) exhausted() bool {
+	return atomic.LoadInt64(&b.used) > bfsMaxIterations
 }
 
+// metricsAlgorithm adalah label algoritma BFS di metrik pencarian.
+const metricsAlgorithm = "bfs"
+
 // State untuk item dalam antrian BFS Multi-Path (Backward)
 type BFSMPStateBackward struct {
-	ElementsToDeconstruct     []string
-	PathTakenSoFar            []pathfinding.PathStep
-	elementsInCurrentPathTree map[string]bool
+	ElementsToDeconstruct []loadrecipes.ElementID
+	PathTakenSoFar        *BFSPathNode
 }
 
 // BFSFindPath mencari X path berbeda dari target ke elemen dasar.
 func BFSFindPath(graph *loadrecipes.BiGraphAlchemy, targetElementName string, maxPaths int) (*pathfinding.MultipleResult, error) {
 	if _, targetExists := graph.AllElements[targetElementName]; !targetExists {
-		return nil, fmt.Errorf("elemen target '%s' tidak ditemukan dalam data", targetElementName)
+		return nil, pathfinding.UnknownElementError(targetElementName)
 	}
 	if maxPaths <= 0 {
-		return nil, fmt.Errorf("maxPaths harus integer positif")
+		return nil, pathfinding.InvalidParamError("maxPaths", maxPaths, "harus integer positif")
 	}
 
-	var collectedPaths [][]pathfinding.PathStep
-	uniquePathSignatures := make(map[string]bool)
-	totalNodesExplored := 0
-
 	if graph.BaseElements[targetElementName] {
-		collectedPaths = append(collectedPaths, []pathfinding.PathStep{})
 		return &pathfinding.MultipleResult{
 			Results: []pathfinding.Result{
-				{Path: []pathfinding.PathStep{}, NodesVisited: 1},
+				pathfinding.NewResult([]pathfinding.PathStep{}, 1),
 			},
 		}, nil
 	}
 
-	initialState := BFSMPStateBackward{
-		ElementsToDeconstruct:     []string{targetElementName},
-		PathTakenSoFar:            []pathfinding.PathStep{},
-		elementsInCurrentPathTree: make(map[string]bool),
+	cg, err := graph.Compact()
+	if err != nil {
+		return nil, err
 	}
+	targetID, _ := cg.ID(targetElementName)
 
-	queue := list.New()
-	queue.PushBack(initialState)
+	collectedPaths, totalNodesExplored, hitLimit := bfsSearchCompact(cg, targetID, maxPaths, cg.ChildToParents[targetID], &bfsBudget{})
+	pathfinding.ObserveSearch(metricsAlgorithm, totalNodesExplored)
+	if len(collectedPaths) == 0 && hitLimit {
+		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, pathfinding.BudgetExceededError(targetElementName, bfsMaxIterations, totalNodesExplored)
+	}
+	if len(collectedPaths) == 0 {
+		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, pathfinding.NoRecipeError(targetElementName, totalNodesExplored)
+	}
 
-	maxIterations := 5000000
-	currentIterations := 0
+	var finalResults []pathfinding.Result
+	for _, path := range collectedPaths {
+		finalResults = append(finalResults, pathfinding.NewResult(pathfinding.StepsFromCompact(cg, path), totalNodesExplored))
+	}
 
-	for queue.Len() > 0 && len(collectedPaths) < maxPaths && currentIterations < maxIterations {
-		stateInterface := queue.Remove(queue.Front())
-		currentState := stateInterface.(BFSMPStateBackward)
-		currentIterations++
-		totalNodesExplored++
+	return &pathfinding.MultipleResult{Results: finalResults}, nil
+}
 
-		allCurrentDecomposedToBase := true
-		if len(currentState.ElementsToDeconstruct) == 0 {
-			allCurrentDecomposedToBase = true
-		} else {
-			for _, elemName := range currentState.ElementsToDeconstruct {
-				if !graph.BaseElements[elemName] {
-					allCurrentDecomposedToBase = false
-					break
-				}
-			}
-		}
+// bfsSearchCompact menjalankan BFS mundur di CompactGraph. targetRecipes menggantikan
+// resep target di graf, sehingga worker bisa dibatasi ke satu resep awal tanpa menyalin graf.
+// Nilai bool hasil bernilai true jika pencarian berhenti karena budget habis.
+func bfsSearchCompact(cg *loadrecipes.CompactGraph, targetID loadrecipes.ElementID, maxPaths int, targetRecipes []loadrecipes.CompactPair, budget *bfsBudget) ([][]loadrecipes.CompactStep, int, bool) {
+	var collectedPaths [][]loadrecipes.CompactStep
+	uniqueRecipes := pathfinding.NewRecipeSet()
+	totalNodesExplored := 0
 
-		if allCurrentDecomposedToBase {
-			pathCandidate := make([]pathfinding.PathStep, len(currentState.PathTakenSoFar))
-			copy(pathCandidate, currentState.PathTakenSoFar)
-			reversePathStepsBFS(pathCandidate)
+	initialState := BFSMPStateBackward{
+		ElementsToDeconstruct: []loadrecipes.ElementID{targetID},
+		PathTakenSoFar:        nil,
+	}
 
-			sig := createPathSignature(pathCandidate)
-			if !uniquePathSignatures[sig] {
-				uniquePathSignatures[sig] = true
-				collectedPaths = append(collectedPaths, pathCandidate)
-				if len(collectedPaths) >= maxPaths {
-					break
-				}
-			}
-			continue
-		}
+	queue := list.New()
+	queue.PushBack(initialState)
 
-		var elementToProcess string
-		var nextElementToProcessIdx = -1
-		remainingToDeconstructForNextState := []string{}
+	craft := cg.Craftability
 
-		for i, elem := range currentState.ElementsToDeconstruct {
-			if !graph.BaseElements[elem] {
-				elementToProcess = elem
-				nextElementToProcessIdx = i
-				break
-			}
+	for queue.Len() > 0 && len(collectedPaths) < maxPaths {
+		if !budget.take() {
+			break
 		}
+		currentState := queue.Remove(queue.Front()).(BFSMPStateBackward)
+		totalNodesExplored++
 
-		if nextElementToProcessIdx == -1 {
-			pathCandidate := make([]pathfinding.PathStep, len(currentState.PathTakenSoFar))
-			copy(pathCandidate, currentState.PathTakenSoFar)
-			reversePathStepsBFS(pathCandidate)
-			sig := createPathSignature(pathCandidate)
-			if !uniquePathSignatures[sig] {
-				uniquePathSignatures[sig] = true
+		// Elemen dasar tidak pernah dimasukkan ke ElementsToDeconstruct,
+		// jadi daftar kosong berarti semua sudah terurai ke elemen dasar.
+		if len(currentState.ElementsToDeconstruct) == 0 {
+			pathCandidate := currentState.PathTakenSoFar.toSteps()
+			if uniqueRecipes.Add(pathfinding.NewRecipeTreeFromCompact(cg, pathCandidate)) {
 				collectedPaths = append(collectedPaths, pathCandidate)
 				if len(collectedPaths) >= maxPaths {
 					break
@@ -155,22 +165,14 @@ func BFSFindPath(graph *loadrecipes.BiGraphAlchemy, targetElementName string, ma
 			continue
 		}
 
-		for i, elem := range currentState.ElementsToDeconstruct {
-			if i != nextElementToProcessIdx {
-				remainingToDeconstructForNextState = append(remainingToDeconstructForNextState, elem)
-			}
-		}
+		elementToProcess := currentState.ElementsToDeconstruct[0]
+		remainingToDeconstructForNextState := currentState.ElementsToDeconstruct[1:]
 
-		// Salinan map untuk state anak. Map ini berguna untuk mendeteksi siklus A->B->...->A yang sebenarnya.
-		newElementsInPathTreeForChildren := make(map[string]bool)
-		for k, v := range currentState.elementsInCurrentPathTree {
-			newElementsInPathTreeForChildren[k] = v
+		parentPairs := cg.ChildToParents[elementToProcess]
+		if elementToProcess == targetID {
+			parentPairs = targetRecipes
 		}
-
-		newElementsInPathTreeForChildren[elementToProcess] = true // Tambahkan elementToProcess ke tree untuk anak-anaknya
-
-		parentPairs, hasRecipes := graph.ChildToParents[elementToProcess]
-		if !hasRecipes {
+		if len(parentPairs) == 0 {
 			continue
 		}
 
@@ -178,156 +180,135 @@ func BFSFindPath(graph *loadrecipes.BiGraphAlchemy, targetElementName string, ma
 			if len(collectedPaths) >= maxPaths {
 				break
 			}
-
-			currentStep := pathfinding.PathStep{
-				ChildName:   elementToProcess,
-				Parent1Name: pair.Mat1,
-				Parent2Name: pair.Mat2,
+			// Hanya resep Usable yang diurai: bahannya pasti bisa dibuat, dan tree yang hanya
+			// memakai resep Usable tidak pernah bersiklus walaupun grafnya tanpa aturan tier.
+			if !craft.Usable(elementToProcess, pair) {
+				continue
 			}
 
-			newPathTaken := make([]pathfinding.PathStep, len(currentState.PathTakenSoFar))
-			copy(newPathTaken, currentState.PathTakenSoFar)
-			newPathTaken = append(newPathTaken, currentStep)
-
-			nextElementsToDeconstruct := make([]string, len(remainingToDeconstructForNextState))
-			copy(nextElementsToDeconstruct, remainingToDeconstructForNextState)
-
-			tempDeconstructSet := make(map[string]bool)
-			for _, el := range nextElementsToDeconstruct {
-				tempDeconstructSet[el] = true
-			}
-
-			if !tempDeconstructSet[pair.Mat1] {
-				nextElementsToDeconstruct = append(nextElementsToDeconstruct, pair.Mat1)
+			newPathTaken := &BFSPathNode{
+				Step:   loadrecipes.CompactStep{Child: elementToProcess, Pair: pair},
+				Prev:   currentState.PathTakenSoFar,
+				Length: 1,
 			}
-			if !tempDeconstructSet[pair.Mat2] {
-				nextElementsToDeconstruct = append(nextElementsToDeconstruct, pair.Mat2)
+			if currentState.PathTakenSoFar != nil {
+				newPathTaken.Length = currentState.PathTakenSoFar.Length + 1
 			}
 
-			newState := BFSMPStateBackward{
-				ElementsToDeconstruct:     nextElementsToDeconstruct,
-				PathTakenSoFar:            newPathTaken,
-				elementsInCurrentPathTree: newElementsInPathTreeForChildren,
+			nextElementsToDeconstruct := make([]loadrecipes.ElementID, len(remainingToDeconstructForNextState), len(remainingToDeconstructForNextState)+2)
+			copy(nextElementsToDeconstruct, remainingToDeconstructForNextState)
+			// Setiap elemen hanya diurai satu kali per tree: parent yang sudah menunggu diurai atau
+			// sudah punya step di path tidak ditambahkan lagi. Stone+Stone mengurai Stone sekali,
+			// jadi tree tidak pernah berisi step ganda atau dua resep berbeda untuk satu elemen.
+			for _, parent := range [2]loadrecipes.ElementID{pair.Mat1, pair.Mat2} {
+				if cg.IsBase(parent) || containsElementID(nextElementsToDeconstruct, parent) || newPathTaken.produces(parent) {
+					continue
+				}
+				nextElementsToDeconstruct = append(nextElementsToDeconstruct, parent)
 			}
-			queue.PushBack(newState)
-		}
-		if len(collectedPaths) >= maxPaths {
-			break
+
+			queue.PushBack(BFSMPStateBackward{
+				ElementsToDeconstruct: nextElementsToDeconstruct,
+				PathTakenSoFar:        newPathTaken,
+			})
 		}
 	}
 
-	if currentIterations >= maxIterations {
-		log.Printf("[BFS-Multi-WARN] Mencapai batas iterasi maksimal (%d) untuk target '%s'. Hasil mungkin tidak lengkap (%d path ditemukan). Total state diproses: %d", maxIterations, targetElementName, len(collectedPaths), totalNodesExplored)
+	hitLimit := budget.exhausted()
+	if hitLimit {
+		log.Printf("[BFS-Multi-WARN] Mencapai batas iterasi maksimal (%d) untuk target '%s'. Hasil mungkin tidak lengkap (%d path ditemukan). Total state diproses: %d", bfsMaxIterations, cg.Name(targetID), len(collectedPaths), totalNodesExplored)
 	}
-
-	var finalResults []pathfinding.Result
-	for _, path := range collectedPaths {
-		finalResults = append(finalResults, pathfinding.Result{
-			Path:         path,
-			NodesVisited: totalNodesExplored,
-		})
+	if len(collectedPaths) == 0 {
+		log.Printf("[BFS-Multi-INFO] Tidak ada path yang ditemukan untuk '%s' (total state diproses: %d).", cg.Name(targetID), totalNodesExplored)
 	}
 
-	if len(finalResults) == 0 && !graph.BaseElements[targetElementName] {
-		log.Printf("[BFS-Multi-INFO] Tidak ada path yang ditemukan untuk '%s' setelah %d iterasi (total state diproses: %d). Ditemukan %d path mentah.", targetElementName, currentIterations, totalNodesExplored, len(collectedPaths))
-	}
+	return collectedPaths, totalNodesExplored, hitLimit
+}
 
-	return &pathfinding.MultipleResult{Results: finalResults}, nil
+func containsElementID(slice []loadrecipes.ElementID, id loadrecipes.ElementID) bool {
+	for _, item := range slice {
+		if item == id {
+			return true
+		}
+	}
+	return false
 }
 
 func proxyBFSWorker(
-	originalGraph *loadrecipes.BiGraphAlchemy,
-	targetElementName string,
-	assignedInitialRecipe loadrecipes.PairMats,
+	cg *loadrecipes.CompactGraph,
+	targetID loadrecipes.ElementID,
+	assignedInitialRecipe loadrecipes.CompactPair,
 	maxPathsForWorkerBranch int, // bisa dibuat maxPathsForWorkerBranch = maxPaths
-	rawPathChannel chan<- []pathfinding.PathStep,
+	rawPathChannel chan<- []loadrecipes.CompactStep,
 	wg *sync.WaitGroup,
 	doneSignal <-chan struct{},
 	nodesExploredCounter *int64,
+	budget *bfsBudget,
 ) {
 	defer wg.Done()
 
-	// Buat graf yang dimodifikasi HANYA untuk worker ini
-	modifiedChildToParents := make(map[string][]loadrecipes.PairMats)
-	for key, value := range originalGraph.ChildToParents {
-		modifiedChildToParents[key] = value
-	}
-	// Override resep untuk targetElementName di graf yang dimodifikasi
-	modifiedChildToParents[targetElementName] = []loadrecipes.PairMats{assignedInitialRecipe}
-
-	workerGraph := &loadrecipes.BiGraphAlchemy{
-		ChildToParents:    modifiedChildToParents,
-		ParentPairToChild: originalGraph.ParentPairToChild,
-		BaseElements:      originalGraph.BaseElements,
-		AllElements:       originalGraph.AllElements,
-	}
-
-	// log.Printf("[WORKER-PROXY %s via %s+%s] Dimulai. Max paths worker: %d", targetElementName, assignedInitialRecipe.Mat1, assignedInitialRecipe.Mat2, maxPathsForWorkerBranch)
-
 	select {
 	case <-doneSignal:
-		// log.Printf("[WORKER-PROXY %s via %s+%s] Berhenti (doneSignal) sebelum BFS sekuensial.", targetElementName, assignedInitialRecipe.Mat1, assignedInitialRecipe.Mat2)
 		return
 	default:
 	}
 
-	result, err := BFSFindPath(workerGraph, targetElementName, maxPathsForWorkerBranch)
-
-	// Akumulasi NodesVisited
-	var nodesFromThisCall int
-	if result != nil && len(result.Results) > 0 {
-		nodesFromThisCall = result.Results[0].NodesVisited
-	} else if result != nil && len(result.Results) == 0 && !originalGraph.BaseElements[targetElementName] {
-		// Untuk sekarang, jika `result.Results` kosong, `nodesFromThisCall` akan 0.
-		// Ini mungkin menjadi sumber perbedaan `totalNodesExploredGlobal` dengan sekuensial.
-	}
+	// Resep target dibatasi ke resep awal worker ini; graf lain dipakai bersama tanpa disalin.
+	paths, nodesFromThisCall, _ := bfsSearchCompact(cg, targetID, maxPathsForWorkerBranch, []loadrecipes.CompactPair{assignedInitialRecipe}, budget)
 	atomic.AddInt64(nodesExploredCounter, int64(nodesFromThisCall))
 
-	if err != nil {
-		return
-	}
-
-	if result != nil {
-		for _, resPath := range result.Results {
-			select {
-			case rawPathChannel <- resPath.Path:
-			case <-doneSignal:
-				return
-			}
+	for _, path := range paths {
+		select {
+		case rawPathChannel <- path:
+		case <-doneSignal:
+			return
 		}
 	}
 }
 
 func BFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName string, maxPaths int) (*pathfinding.MultipleResult, error) {
 	if _, targetExists := graph.AllElements[targetElementName]; !targetExists {
-		return nil, fmt.Errorf("elemen target '%s' tidak ditemukan (ProxyParallel)", targetElementName)
+		return nil, pathfinding.UnknownElementError(targetElementName)
 	}
 	if maxPaths <= 0 {
-		return nil, fmt.Errorf("maxPaths harus integer positif (ProxyParallel)")
+		return nil, pathfinding.InvalidParamError("maxPaths", maxPaths, "harus integer positif")
 	}
 
-	var collectedPathResults []pathfinding.Result
-	uniquePathSignaturesGlobal := make(map[string]bool)
+	var collectedPaths [][]loadrecipes.CompactStep
+	uniqueRecipesGlobal := pathfinding.NewRecipeSet()
 	var totalNodesExploredGlobal int64
 
 	if graph.BaseElements[targetElementName] {
 		log.Printf("[BFS-PROXY-ORCH] Target '%s' adalah elemen dasar.", targetElementName)
 		return &pathfinding.MultipleResult{
-			Results: []pathfinding.Result{{Path: []pathfinding.PathStep{}, NodesVisited: 1}},
+			Results: []pathfinding.Result{pathfinding.NewResult([]pathfinding.PathStep{}, 1)},
 		}, nil
 	}
 
-	initialParentPairs, hasInitialRecipes := graph.ChildToParents[targetElementName]
-	if !hasInitialRecipes {
+	cg, err := graph.Compact()
+	if err != nil {
+		return nil, err
+	}
+	targetID, _ := cg.ID(targetElementName)
+
+	// Worker hanya diluncurkan untuk resep Usable; resep lain tidak akan menghasilkan tree.
+	var initialParentPairs []loadrecipes.CompactPair
+	for _, pair := range cg.ChildToParents[targetID] {
+		if cg.Craftability.Usable(targetID, pair) {
+			initialParentPairs = append(initialParentPairs, pair)
+		}
+	}
+	if len(initialParentPairs) == 0 {
 		log.Printf("[BFS-PROXY-ORCH] Target '%s' tidak memiliki resep awal.", targetElementName)
-		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, nil
+		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, pathfinding.NoRecipeError(targetElementName, 0)
 	}
 
 	var wg sync.WaitGroup
 	// Setiap worker memanggil BFSFindXDifferentPathsBackward yang bisa menghasilkan hingga `maxPaths` jalur.
 	// Jadi, buffer channel bisa len(initialParentPairs) * maxPaths.
-	rawPathChannel := make(chan []pathfinding.PathStep, len(initialParentPairs)*maxPaths)
+	rawPathChannel := make(chan []loadrecipes.CompactStep, len(initialParentPairs)*maxPaths)
 	doneSignal := make(chan struct{})
+	budget := &bfsBudget{done: doneSignal}
 
 	log.Printf("[BFS-PROXY-ORCH] Target: '%s'. Meluncurkan %d worker (Proxy ke BFS Sekuensial). MaxPaths Global: %d", targetElementName, len(initialParentPairs), maxPaths)
 
@@ -336,20 +317,21 @@ func BFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 	maxPathsForWorkerExecution := maxPaths
 
 	for _, initialRecipe := range initialParentPairs {
-		if len(collectedPathResults) >= maxPaths {
+		if len(collectedPaths) >= maxPaths {
 			break
 		}
 		numWorkersLaunched++
 		wg.Add(1)
 		go proxyBFSWorker(
-			graph,
-			targetElementName,
+			cg,
+			targetID,
 			initialRecipe,
 			maxPathsForWorkerExecution,
 			rawPathChannel,
 			&wg,
 			doneSignal,
 			&totalNodesExploredGlobal,
+			budget,
 		)
 	}
 
@@ -364,9 +346,10 @@ func BFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 		wg.Wait()
 		close(rawPathChannel)
 	}()
+	pathfinding.CountGoroutines(metricsAlgorithm, numWorkersLaunched+1)
 
 	for pathFromWorker := range rawPathChannel {
-		if len(collectedPathResults) >= maxPaths {
+		if len(collectedPaths) >= maxPaths {
 			select {
 			case <-doneSignal:
 			default:
@@ -375,11 +358,9 @@ func BFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 			continue
 		}
 
-		sig := createPathSignature(pathFromWorker)
-		if !uniquePathSignaturesGlobal[sig] {
-			uniquePathSignaturesGlobal[sig] = true
-			collectedPathResults = append(collectedPathResults, pathfinding.Result{Path: pathFromWorker, NodesVisited: 0})
-			if len(collectedPathResults) >= maxPaths {
+		if uniqueRecipesGlobal.Add(pathfinding.NewRecipeTreeFromCompact(cg, pathFromWorker)) {
+			collectedPaths = append(collectedPaths, pathFromWorker)
+			if len(collectedPaths) >= maxPaths {
 				select {
 				case <-doneSignal:
 				default:
@@ -397,6 +378,12 @@ func BFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 	}
 
 	finalNodesExploredCount := int(atomic.LoadInt64(&totalNodesExploredGlobal))
+	pathfinding.ObserveSearch(metricsAlgorithm, finalNodesExploredCount)
+
+	collectedPathResults := make([]pathfinding.Result, 0, len(collectedPaths))
+	for _, path := range collectedPaths {
+		collectedPathResults = append(collectedPathResults, pathfinding.NewResult(pathfinding.StepsFromCompact(cg, path), 0))
+	}
 
 	if len(collectedPathResults) == 0 && !graph.BaseElements[targetElementName] {
 		if finalNodesExploredCount == 0 && numWorkersLaunched > 0 {
@@ -404,6 +391,11 @@ func BFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 			// finalNodesExploredCount = 1
 		}
 		log.Printf("[BFS-PROXY-ORCH-INFO] Tidak ada jalur unik yang ditemukan untuk '%s'. Total node dieksplorasi (gabungan worker): %d", targetElementName, finalNodesExploredCount)
+		// Tanpa hasil karena batas iterasi berbeda dengan tanpa hasil karena memang tidak ada resep.
+		if budget.exhausted() {
+			return &pathfinding.MultipleResult{Results: collectedPathResults}, pathfinding.BudgetExceededError(targetElementName, bfsMaxIterations, finalNodesExploredCount)
+		}
+		return &pathfinding.MultipleResult{Results: collectedPathResults}, pathfinding.NoRecipeError(targetElementName, finalNodesExploredCount)
 	} else if len(collectedPathResults) > 0 {
 		log.Printf("[BFS-PROXY-ORCH-INFO] Selesai untuk target '%s'. Ditemukan %d jalur unik. Total node dieksplorasi (gabungan worker): %d", targetElementName, len(collectedPathResults), finalNodesExploredCount)
 	}
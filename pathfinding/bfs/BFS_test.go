@@ -0,0 +1,125 @@
+package bfs
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// cyclicDataset meniru hasil scrape tanpa filter: Mud, Brick, dan Wall saling bergantung,
+// Steam dan Cloud hanya bisa dibuat dari satu sama lain.
+const cyclicDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Brick", "Water"], ["Earth", "Water"]]},
+	{"name": "Brick", "tier": 2, "recipes": [["Wall", "Fire"], ["Mud", "Fire"], ["Mud", "Air"]]},
+	{"name": "Wall", "tier": 3, "recipes": [["Brick", "Brick"], ["Brick", "Steam"]]},
+	{"name": "Steam", "tier": 1, "recipes": [["Cloud", "Fire"]]},
+	{"name": "Cloud", "tier": 1, "recipes": [["Steam", "Air"]]}
+]`
+
+func TestBFSFindMultiplePathsCyclic(t *testing.T) {
+	graph, err := loadrecipes.LoadBiGraphFromReader(strings.NewReader(cyclicDataset), "cyclic", loadrecipes.LoadOptions{Policy: loadrecipes.NoFilterPolicy})
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+
+	results, err := BFSFindMultiplePaths(graph, "Wall", 5)
+	if err != nil {
+		t.Fatalf("BFSFindMultiplePaths(Wall): %v", err)
+	}
+	// Brick dari Brick+Brick dibuat sekali, dari Mud+Fire atau Mud+Air; resep lewat Steam atau
+	// Wall tidak pernah selesai.
+	if len(results.Results) != 2 {
+		t.Errorf("jumlah resep Wall = %d, want 2", len(results.Results))
+	}
+	for _, result := range results.Results {
+		if verification := pathfinding.VerifyRecipe(graph, "Wall", result.Path); !verification.Valid {
+			t.Errorf("resep Wall tidak valid: %+v", verification.Issues)
+		}
+	}
+}
+
+func TestBFSReportsNoRecipe(t *testing.T) {
+	graph, err := loadrecipes.LoadBiGraphFromReader(strings.NewReader(cyclicDataset), "cyclic", loadrecipes.LoadOptions{Policy: loadrecipes.NoFilterPolicy})
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	// Steam dan Cloud punya resep, tetapi hanya dari satu sama lain.
+	for _, target := range []string{"Steam", "Cloud"} {
+		if _, err := BFSFindMultiplePaths(graph, target, 3); !errors.Is(err, pathfinding.ErrNoRecipe) {
+			t.Errorf("BFSFindMultiplePaths(%s) error = %v, want ErrNoRecipe", target, err)
+		}
+		if _, err := BFSFindPath(graph, target, 3); !errors.Is(err, pathfinding.ErrNoRecipe) {
+			t.Errorf("BFSFindPath(%s) error = %v, want ErrNoRecipe", target, err)
+		}
+	}
+
+	// Time tidak punya resep sama sekali, jadi tidak ada worker yang diluncurkan.
+	noRecipes, err := loadrecipes.LoadBiGraphFromReader(strings.NewReader(`[
+		{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+		{"name": "Time", "tier": 1, "recipes": []}
+	]`), "norecipes", loadrecipes.DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	if _, err := BFSFindMultiplePaths(noRecipes, "Time", 3); !errors.Is(err, pathfinding.ErrNoRecipe) {
+		t.Errorf("BFSFindMultiplePaths(Time) error = %v, want ErrNoRecipe", err)
+	}
+}
+
+// Wall = Stone + Stone: Stone diurai sekali, jadi setiap hasil adalah tree valid yang berbeda
+// dengan satu resep per elemen (Brick+Brick, Stone dari Pressure, dan Stone dari Lava).
+func TestBFSFindMultiplePathsWall(t *testing.T) {
+	graph, err := loadrecipes.LoadBiGraph("../../elements_filtered.json")
+	if err != nil {
+		t.Fatalf("memuat dataset bawaan: %v", err)
+	}
+	results, err := BFSFindMultiplePaths(graph, "Wall", 10)
+	if err != nil {
+		t.Fatalf("BFSFindMultiplePaths(Wall): %v", err)
+	}
+	if len(results.Results) != 3 {
+		t.Errorf("jumlah resep Wall = %d, want 3", len(results.Results))
+	}
+	seen := make(map[string]bool)
+	for _, result := range results.Results {
+		if verification := pathfinding.VerifyRecipe(graph, "Wall", result.Path); !verification.Valid {
+			t.Errorf("resep Wall tidak valid: %+v", verification.Issues)
+		}
+		if seen[result.RecipeID] {
+			t.Errorf("RecipeID %s muncul lebih dari sekali", result.RecipeID)
+		}
+		seen[result.RecipeID] = true
+
+		children := make(map[string]bool)
+		for _, step := range result.Path {
+			if children[step.ChildName] {
+				t.Errorf("resep %s membuat %s lebih dari sekali: %+v", result.RecipeID, step.ChildName, result.Path)
+			}
+			children[step.ChildName] = true
+		}
+	}
+}
+
+func BenchmarkBFS(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	graph, err := loadrecipes.LoadBiGraph("../../elements_filtered.json")
+	if err != nil {
+		b.Fatalf("memuat dataset bawaan: %v", err)
+	}
+	for _, target := range []string{"Brick", "Human", "Dragon"} {
+		b.Run(target, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := BFSFindMultiplePaths(graph, target, 5); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -2,9 +2,7 @@ package bis
 
 import (
 	"container/list"
-	"fmt"
 	"log"
-	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -15,24 +13,31 @@ import (
 // BiSQueueItem merepresentasikan item dalam antrian pencarian BiS.
 // Ini menyimpan nama elemen dan jalur (langkah-langkah resep) untuk mencapainya.
 type BiSQueueItem struct {
-	ElementName string
-	PathSoFar   []pathfinding.PathStep // PathSoFar untuk forward: dari base ke ElementName.
-	                                 // PathSoFar untuk backward: dari Target ke ElementName (langkah dekonstruksi).
+	ElementID loadrecipes.ElementID
+	PathSoFar []loadrecipes.CompactStep // PathSoFar untuk forward: dari base ke ElementID.
+	                                    // PathSoFar untuk backward: dari Target ke ElementID (langkah dekonstruksi).
 }
 
+// metricsAlgorithm adalah label algoritma BiS di metrik pencarian.
+const metricsAlgorithm = "bis"
+
 // BiSSharedData menyimpan data yang dibagikan antar goroutine selama pencarian BiS.
 // Sinkronisasi diperlukan untuk mengakses data ini secara aman.
 type BiSSharedData struct {
-	Graph             *loadrecipes.BiGraphAlchemy
-	TargetElement     string
+	Source            *loadrecipes.BiGraphAlchemy // Dipakai VerifyRecipe untuk memeriksa resep hasil rekonstruksi.
+	Graph             *loadrecipes.CompactGraph
+	TargetElement     loadrecipes.ElementID
 	MaxRecipes        int
 	// TimeoutDuration   time.Duration // Dihapus
 
-	VisitedForward    map[string][]pathfinding.PathStep
-	VisitedBackward   map[string][]pathfinding.PathStep
+	// Visited* diindeks dengan ElementID; *Seen menandai elemen yang sudah dikunjungi.
+	VisitedForward    [][]loadrecipes.CompactStep
+	VisitedBackward   [][]loadrecipes.CompactStep
+	ForwardSeen       loadrecipes.Bitset
+	BackwardSeen      loadrecipes.Bitset
 	
 	FoundRecipes      []pathfinding.Result
-	RecipeSignatures  map[string]bool      
+	UniqueRecipes     *pathfinding.RecipeSet
 	
 	Mutex             sync.RWMutex
 
@@ -43,81 +48,106 @@ type BiSSharedData struct {
 	Wg                sync.WaitGroup
 }
 
-// createBiSPathSignature membuat representasi string kanonis dari sebuah jalur resep.
-func createBiSPathSignature(steps []pathfinding.PathStep) string {
-	if len(steps) == 0 {
-		return "base_element_or_empty_path"
-	}
-
-	pathCopy := make([]pathfinding.PathStep, len(steps))
-	copy(pathCopy, steps)
-
-	for i := range pathCopy {
-		if pathCopy[i].Parent1Name > pathCopy[i].Parent2Name {
-			pathCopy[i].Parent1Name, pathCopy[i].Parent2Name = pathCopy[i].Parent2Name, pathCopy[i].Parent1Name
+// appendNewSteps menambahkan langkah dari src yang anaknya belum dibuat di dst. Setiap elemen
+// hanya dibuat satu kali, jadi gabungan dua jalur tidak pernah memberi satu anak dua resep.
+// Bahan setiap langkah src sudah dibuat lebih awal (di dst atau di src), sehingga urutan
+// hasilnya tetap topologis.
+func appendNewSteps(dst []loadrecipes.CompactStep, produced loadrecipes.Bitset, src []loadrecipes.CompactStep) []loadrecipes.CompactStep {
+	for _, step := range src {
+		if produced.Has(step.Child) {
+			continue
 		}
+		produced.Set(step.Child)
+		dst = append(dst, step)
 	}
+	return dst
+}
 
-	sort.Slice(pathCopy, func(i, j int) bool {
-		if pathCopy[i].ChildName != pathCopy[j].ChildName {
-			return pathCopy[i].ChildName < pathCopy[j].ChildName
-		}
-		if pathCopy[i].Parent1Name != pathCopy[j].Parent1Name {
-			return pathCopy[i].Parent1Name < pathCopy[j].Parent1Name
-		}
-		return pathCopy[i].Parent2Name < pathCopy[j].Parent2Name
-	})
-
-	var signature string
-	for _, step := range pathCopy {
-		signature += fmt.Sprintf("%s=(%s+%s);", step.ChildName, step.Parent1Name, step.Parent2Name)
+// extendForwardPath membangun jalur maju untuk step: jalur elemen saat ini digabung dengan
+// jalur partner, lalu step ditambahkan. Hasilnya false jika anak step sudah dibuat di jalur
+// gabungan (step itu akan memakai elemen untuk membuat dirinya sendiri).
+func extendForwardPath(cg *loadrecipes.CompactGraph, currentPath, partnerPath []loadrecipes.CompactStep, step loadrecipes.CompactStep) ([]loadrecipes.CompactStep, bool) {
+	produced := loadrecipes.NewBitset(cg.Len())
+	path := make([]loadrecipes.CompactStep, 0, len(currentPath)+len(partnerPath)+1)
+	path = appendNewSteps(path, produced, currentPath)
+	path = appendNewSteps(path, produced, partnerPath)
+	if produced.Has(step.Child) || cg.IsBase(step.Child) {
+		return nil, false
 	}
-	return signature
+	return append(path, step), true
 }
 
 // reconstructRecipe menggabungkan jalur dari pencarian maju dan mundur saat bertemu.
-func reconstructRecipe(meetingElement string, pathForward []pathfinding.PathStep, pathBackwardDeconstruction []pathfinding.PathStep) []pathfinding.PathStep {
-	fullRecipe := make([]pathfinding.PathStep, 0, len(pathForward)+len(pathBackwardDeconstruction))
-	fullRecipe = append(fullRecipe, pathForward...)
+// Jalur mundur hanya memuat satu rantai dari target ke titik pertemuan, jadi bahan lain
+// setiap langkahnya diambil dari VisitedForward. Hasilnya nil jika ada bahan yang belum
+// dicapai pencarian maju. Pemanggil harus memegang shared.Mutex.
+func reconstructRecipe(shared *BiSSharedData, pathForward []loadrecipes.CompactStep, pathBackwardDeconstruction []loadrecipes.CompactStep) []loadrecipes.CompactStep {
+	cg := shared.Graph
+	produced := loadrecipes.NewBitset(cg.Len())
+	fullRecipe := make([]loadrecipes.CompactStep, 0, len(pathForward)+len(pathBackwardDeconstruction))
+	fullRecipe = appendNewSteps(fullRecipe, produced, pathForward)
 
 	for i := len(pathBackwardDeconstruction) - 1; i >= 0; i-- {
-		fullRecipe = append(fullRecipe, pathBackwardDeconstruction[i])
+		step := pathBackwardDeconstruction[i]
+		for _, parent := range [2]loadrecipes.ElementID{step.Pair.Mat1, step.Pair.Mat2} {
+			if cg.IsBase(parent) || produced.Has(parent) {
+				continue
+			}
+			if !shared.ForwardSeen.Has(parent) {
+				return nil
+			}
+			fullRecipe = appendNewSteps(fullRecipe, produced, shared.VisitedForward[parent])
+		}
+		fullRecipe = appendNewSteps(fullRecipe, produced, []loadrecipes.CompactStep{step})
 	}
 	return fullRecipe
 }
 
 // processMeetingPoint memproses titik pertemuan, merekonstruksi resep, dan menambahkannya jika unik.
-func processMeetingPoint(shared *BiSSharedData, meetingElement string, currentPathForward []pathfinding.PathStep, pathFromTargetToMeetingBackward []pathfinding.PathStep) { // Hapus nodesVisitedAtThisPoint
+// Hasilnya false jika resep belum bisa direkonstruksi karena ada bahan yang belum dicapai
+// pencarian maju; titik pertemuan seperti itu perlu dicoba lagi.
+func processMeetingPoint(shared *BiSSharedData, meetingElement loadrecipes.ElementID, currentPathForward []loadrecipes.CompactStep, pathFromTargetToMeetingBackward []loadrecipes.CompactStep) bool {
 	if atomic.LoadInt32(&shared.FoundRecipesCount) >= int32(shared.MaxRecipes) {
-		return 
+		return true
 	}
 
-	recipeSteps := reconstructRecipe(meetingElement, currentPathForward, pathFromTargetToMeetingBackward)
-	signature := createBiSPathSignature(recipeSteps)
-
 	shared.Mutex.Lock()
 	defer shared.Mutex.Unlock()
 
-	if !shared.RecipeSignatures[signature] {
-		if atomic.LoadInt32(&shared.FoundRecipesCount) < int32(shared.MaxRecipes) {
-			shared.RecipeSignatures[signature] = true
+	recipeSteps := reconstructRecipe(shared, currentPathForward, pathFromTargetToMeetingBackward)
+	if recipeSteps == nil {
+		return false
+	}
+	// Hanya resep yang lolos verifikasi yang diberi RecipeID; gabungan jalur maju dan mundur
+	// bisa saja tidak lengkap atau (pada graf bersiklus) memakai elemen untuk membuat dirinya.
+	path := pathfinding.StepsFromCompact(shared.Graph, recipeSteps)
+	verification := pathfinding.VerifyRecipe(shared.Source, shared.Graph.Name(shared.TargetElement), path)
+	if !verification.Valid {
+		return true
+	}
+	tree := pathfinding.NewRecipeTreeFromCompact(shared.Graph, recipeSteps)
+
+	if atomic.LoadInt32(&shared.FoundRecipesCount) < int32(shared.MaxRecipes) {
+		if shared.UniqueRecipes.Add(tree) {
 			shared.FoundRecipes = append(shared.FoundRecipes, pathfinding.Result{
-				Path:         recipeSteps,
-				NodesVisited: int(atomic.LoadInt64(&shared.NodesExplored)), 
+				Path:         path,
+				NodesVisited: int(atomic.LoadInt64(&shared.NodesExplored)),
+				RecipeID:     verification.RecipeID,
 			})
 			atomic.AddInt32(&shared.FoundRecipesCount, 1)
-			log.Printf("[BiS-INFO] Recipe %d found for %s via %s. Signature: %s. Steps: %d", atomic.LoadInt32(&shared.FoundRecipesCount), shared.TargetElement, meetingElement, signature, len(recipeSteps))
+			log.Printf("[BiS-INFO] Recipe %d found for %s via %s. Steps: %d", atomic.LoadInt32(&shared.FoundRecipesCount), shared.Graph.Name(shared.TargetElement), shared.Graph.Name(meetingElement), len(recipeSteps))
 
 			if atomic.LoadInt32(&shared.FoundRecipesCount) >= int32(shared.MaxRecipes) {
 				select {
 				case <-shared.StopSearch: 
 				default:
 					close(shared.StopSearch)
-					log.Printf("[BiS-INFO] Max recipes (%d) reached for %s. Signaling stop.", shared.MaxRecipes, shared.TargetElement)
+					log.Printf("[BiS-INFO] Max recipes (%d) reached for %s. Signaling stop.", shared.MaxRecipes, shared.Graph.Name(shared.TargetElement))
 				}
 			}
 		}
 	}
+	return true
 }
 
 // expandForwardWorker adalah goroutine untuk satu langkah ekspansi dari frontier maju.
@@ -125,7 +155,7 @@ func expandForwardWorker(
 	shared *BiSSharedData,
 	itemsToExpand []BiSQueueItem,
 	nextForwardQueueChan chan BiSQueueItem,
-	meetingCheckChan chan string, 
+	meetingCheckChan chan loadrecipes.ElementID,
 ) {
 	defer shared.Wg.Done()
 
@@ -136,62 +166,63 @@ func expandForwardWorker(
 		default:
 		}
 
-		currentElement := item.ElementName
+		currentElement := item.ElementID
 		currentPath := item.PathSoFar
-		
-		elementsToCombineWith := make([]string, 0)
-		for baseElem := range shared.Graph.BaseElements {
-			elementsToCombineWith = append(elementsToCombineWith, baseElem)
-		}
-		
+
+		// Elemen dasar selalu ada di ForwardSeen, jadi cukup salin bitset ini.
 		shared.Mutex.RLock()
-		for visitedElem := range shared.VisitedForward {
-			elementsToCombineWith = append(elementsToCombineWith, visitedElem)
-		}
+		elementsToCombineWith := shared.ForwardSeen.Clone()
 		shared.Mutex.RUnlock()
 
-		processedCombinations := make(map[loadrecipes.PairMats]bool)
-
-		for _, partnerElement := range elementsToCombineWith {
+		stopped := false
+		elementsToCombineWith.ForEach(func(partnerElement loadrecipes.ElementID) {
+			if stopped {
+				return
+			}
 			select {
 			case <-shared.StopSearch:
+				stopped = true
 				return
 			default:
 			}
 
-			pair := loadrecipes.ConstructPair(currentElement, partnerElement)
-			if processedCombinations[pair] {
-				continue
-			}
-			processedCombinations[pair] = true
-			atomic.AddInt64(&shared.NodesExplored, 1) 
+			pair := loadrecipes.ConstructCompactPair(currentElement, partnerElement)
+			atomic.AddInt64(&shared.NodesExplored, 1)
 
 			children, canCombine := shared.Graph.ParentPairToChild[pair]
 			if canCombine {
-				for _, childName := range children {
+				for _, childID := range children {
 					select {
 					case <-shared.StopSearch:
+						stopped = true
 						return
 					default:
 					}
 
-					newStep := pathfinding.PathStep{ChildName: childName, Parent1Name: pair.Mat1, Parent2Name: pair.Mat2}
-					newPath := make([]pathfinding.PathStep, len(currentPath))
-					copy(newPath, currentPath)
-					newPath = append(newPath, newStep)
-
-					shared.Mutex.Lock() 
-					if _, visited := shared.VisitedForward[childName]; !visited || len(newPath) < len(shared.VisitedForward[childName]) {
-						shared.VisitedForward[childName] = newPath
-						nextForwardQueueChan <- BiSQueueItem{ElementName: childName, PathSoFar: newPath}
-						
-						if _, met := shared.VisitedBackward[childName]; met {
-							meetingCheckChan <- childName 
+					newStep := loadrecipes.CompactStep{Child: childID, Pair: pair}
+
+					// Jalur anak harus ikut membuat partner, bukan hanya elemen saat ini.
+					shared.Mutex.Lock()
+					newPath, ok := extendForwardPath(shared.Graph, currentPath, shared.VisitedForward[partnerElement], newStep)
+					if !ok {
+						shared.Mutex.Unlock()
+						continue
+					}
+					if !shared.ForwardSeen.Has(childID) || len(newPath) < len(shared.VisitedForward[childID]) {
+						shared.ForwardSeen.Set(childID)
+						shared.VisitedForward[childID] = newPath
+						nextForwardQueueChan <- BiSQueueItem{ElementID: childID, PathSoFar: newPath}
+
+						if shared.BackwardSeen.Has(childID) {
+							meetingCheckChan <- childID
 						}
 					}
 					shared.Mutex.Unlock()
 				}
 			}
+		})
+		if stopped {
+			return
 		}
 	}
 }
@@ -201,7 +232,7 @@ func expandBackwardWorker(
 	shared *BiSSharedData,
 	itemsToExpand []BiSQueueItem,
 	nextBackwardQueueChan chan BiSQueueItem,
-	meetingCheckChan chan string, 
+	meetingCheckChan chan loadrecipes.ElementID,
 ) {
 	defer shared.Wg.Done()
 
@@ -212,52 +243,45 @@ func expandBackwardWorker(
 		default:
 		}
 
-		currentElement := item.ElementName 
-		currentPathDeconstruction := item.PathSoFar 
+		currentElement := item.ElementID
+		currentPathDeconstruction := item.PathSoFar
 
 		atomic.AddInt64(&shared.NodesExplored, 1)
 
-		parentPairs, hasRecipes := shared.Graph.ChildToParents[currentElement]
-		if !hasRecipes {
+		parentPairs := shared.Graph.ChildToParents[currentElement]
+		if len(parentPairs) == 0 {
 			continue
 		}
 
-		for _, pair := range parentPairs { 
+		for _, pair := range parentPairs {
 			select {
 			case <-shared.StopSearch:
 				return
 			default:
 			}
-			
-			deconstructionStep := pathfinding.PathStep{ChildName: currentElement, Parent1Name: pair.Mat1, Parent2Name: pair.Mat2}
-
-			newPathToParent1 := make([]pathfinding.PathStep, len(currentPathDeconstruction))
-			copy(newPathToParent1, currentPathDeconstruction)
-			newPathToParent1 = append(newPathToParent1, deconstructionStep) 
-
-			shared.Mutex.Lock()
-			if _, visited := shared.VisitedBackward[pair.Mat1]; !visited || len(newPathToParent1) < len(shared.VisitedBackward[pair.Mat1]) {
-				shared.VisitedBackward[pair.Mat1] = newPathToParent1
-				nextBackwardQueueChan <- BiSQueueItem{ElementName: pair.Mat1, PathSoFar: newPathToParent1}
-				if _, met := shared.VisitedForward[pair.Mat1]; met {
-					meetingCheckChan <- pair.Mat1
-				}
+			// Resep dengan bahan yang tidak bisa dibuat tidak akan bertemu pencarian maju.
+			if !shared.Graph.Craftability.Usable(currentElement, pair) {
+				continue
 			}
-			shared.Mutex.Unlock()
-			
-			newPathToParent2 := make([]pathfinding.PathStep, len(currentPathDeconstruction))
-			copy(newPathToParent2, currentPathDeconstruction)
-			newPathToParent2 = append(newPathToParent2, deconstructionStep) 
-
-			shared.Mutex.Lock()
-			if _, visited := shared.VisitedBackward[pair.Mat2]; !visited || len(newPathToParent2) < len(shared.VisitedBackward[pair.Mat2]) {
-				shared.VisitedBackward[pair.Mat2] = newPathToParent2
-				nextBackwardQueueChan <- BiSQueueItem{ElementName: pair.Mat2, PathSoFar: newPathToParent2}
-				if _, met := shared.VisitedForward[pair.Mat2]; met {
-					meetingCheckChan <- pair.Mat2
+
+			deconstructionStep := loadrecipes.CompactStep{Child: currentElement, Pair: pair}
+
+			for _, parent := range [2]loadrecipes.ElementID{pair.Mat1, pair.Mat2} {
+				newPathToParent := make([]loadrecipes.CompactStep, len(currentPathDeconstruction), len(currentPathDeconstruction)+1)
+				copy(newPathToParent, currentPathDeconstruction)
+				newPathToParent = append(newPathToParent, deconstructionStep)
+
+				shared.Mutex.Lock()
+				if !shared.BackwardSeen.Has(parent) || len(newPathToParent) < len(shared.VisitedBackward[parent]) {
+					shared.BackwardSeen.Set(parent)
+					shared.VisitedBackward[parent] = newPathToParent
+					nextBackwardQueueChan <- BiSQueueItem{ElementID: parent, PathSoFar: newPathToParent}
+					if shared.ForwardSeen.Has(parent) {
+						meetingCheckChan <- parent
+					}
 				}
+				shared.Mutex.Unlock()
 			}
-			shared.Mutex.Unlock()
 		}
 	}
 }
@@ -267,29 +291,42 @@ func expandBackwardWorker(
 // Mengembalikan MultipleResult, jumlah total node yang dieksplorasi, dan error.
 func BiSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElement string, maxRecipes int) (*pathfinding.MultipleResult, int, error) {
 	if _, exists := graph.AllElements[targetElement]; !exists {
-		return nil, 0, fmt.Errorf("elemen target '%s' tidak ditemukan dalam data", targetElement)
+		return nil, 0, pathfinding.UnknownElementError(targetElement)
 	}
 	if maxRecipes <= 0 {
-		return nil, 0, fmt.Errorf("maxRecipes harus integer positif")
+		return nil, 0, pathfinding.InvalidParamError("maxPaths", maxRecipes, "harus integer positif")
 	}
 
 	if graph.BaseElements[targetElement] {
 		return &pathfinding.MultipleResult{
 			Results: []pathfinding.Result{
-				{Path: []pathfinding.PathStep{}, NodesVisited: 1},
+				pathfinding.NewResult([]pathfinding.PathStep{}, 1),
 			},
 		}, 1, nil // 1 node (elemen dasar itu sendiri) dieksplorasi
 	}
 
+	cg, err := graph.Compact()
+	if err != nil {
+		return nil, 0, err
+	}
+	targetID, _ := cg.ID(targetElement)
+	if !cg.Craftability.CanMake(targetID) {
+		pathfinding.ObserveSearch(metricsAlgorithm, 0)
+		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, 0, pathfinding.NoRecipeError(targetElement, 0)
+	}
+
 	shared := &BiSSharedData{
-		Graph:             graph,
-		TargetElement:     targetElement,
+		Source:            graph,
+		Graph:             cg,
+		TargetElement:     targetID,
 		MaxRecipes:        maxRecipes,
 		// TimeoutDuration: Dihapus
-		VisitedForward:    make(map[string][]pathfinding.PathStep),
-		VisitedBackward:   make(map[string][]pathfinding.PathStep),
+		VisitedForward:    make([][]loadrecipes.CompactStep, cg.Len()),
+		VisitedBackward:   make([][]loadrecipes.CompactStep, cg.Len()),
+		ForwardSeen:       loadrecipes.NewBitset(cg.Len()),
+		BackwardSeen:      loadrecipes.NewBitset(cg.Len()),
 		FoundRecipes:      make([]pathfinding.Result, 0, maxRecipes),
-		RecipeSignatures:  make(map[string]bool),
+		UniqueRecipes:     pathfinding.NewRecipeSet(),
 		StopSearch:        make(chan struct{}),
 		NodesExplored:     0,
 		FoundRecipesCount: 0,
@@ -298,14 +335,16 @@ func BiSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElement strin
 	qForward := list.New()
 	qBackward := list.New()
 
-	for baseElem := range graph.BaseElements {
-		shared.VisitedForward[baseElem] = []pathfinding.PathStep{}
-		qForward.PushBack(BiSQueueItem{ElementName: baseElem, PathSoFar: []pathfinding.PathStep{}})
+	for _, baseID := range cg.BaseIDs {
+		shared.ForwardSeen.Set(baseID)
+		shared.VisitedForward[baseID] = []loadrecipes.CompactStep{}
+		qForward.PushBack(BiSQueueItem{ElementID: baseID, PathSoFar: []loadrecipes.CompactStep{}})
 		atomic.AddInt64(&shared.NodesExplored, 1)
 	}
 
-	shared.VisitedBackward[targetElement] = []pathfinding.PathStep{}
-	qBackward.PushBack(BiSQueueItem{ElementName: targetElement, PathSoFar: []pathfinding.PathStep{}})
+	shared.BackwardSeen.Set(targetID)
+	shared.VisitedBackward[targetID] = []loadrecipes.CompactStep{}
+	qBackward.PushBack(BiSQueueItem{ElementID: targetID, PathSoFar: []loadrecipes.CompactStep{}})
 	atomic.AddInt64(&shared.NodesExplored, 1)
 
 	iteration := 0
@@ -314,7 +353,10 @@ func BiSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElement strin
 	// Namun, menambahkan batas iterasi tetap merupakan praktik yang baik untuk mencegah loop tak terbatas dalam kasus yang sangat kompleks.
 	maxIterations := 200 // Batas iterasi yang lebih masuk akal untuk pencarian resep
 
-	for qForward.Len() > 0 && qBackward.Len() > 0 && atomic.LoadInt32(&shared.FoundRecipesCount) < int32(maxRecipes) && iteration < maxIterations {
+	// Pencarian maju tetap berjalan setelah antrian mundur habis selama masih ada titik
+	// pertemuan yang menunggu bahan, atau belum ada resep sama sekali.
+	var pendingMeetings []loadrecipes.ElementID
+	for qForward.Len() > 0 && (qBackward.Len() > 0 || len(pendingMeetings) > 0 || atomic.LoadInt32(&shared.FoundRecipesCount) == 0) && atomic.LoadInt32(&shared.FoundRecipesCount) < int32(maxRecipes) && iteration < maxIterations {
 		select {
 		case <-shared.StopSearch:
 			log.Println("[BiS-INFO] Pencarian dihentikan karena sinyal StopSearch.")
@@ -325,20 +367,18 @@ func BiSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElement strin
 		default:
 		}
 		iteration++
-		log.Printf("[BiS-DEBUG] Iterasi %d, Resep Ditemukan: %d, QF: %d, QB: %d, VF: %d, VB: %d, Nodes: %d\n",
-			iteration, atomic.LoadInt32(&shared.FoundRecipesCount), qForward.Len(), qBackward.Len(), len(shared.VisitedForward), len(shared.VisitedBackward), atomic.LoadInt64(&shared.NodesExplored))
 
 		currentForwardItems := make([]BiSQueueItem, 0, qForward.Len())
 		for qForward.Len() > 0 {
 			currentForwardItems = append(currentForwardItems, qForward.Remove(qForward.Front()).(BiSQueueItem))
 		}
 		
-		nextForwardQueueChan := make(chan BiSQueueItem, len(shared.Graph.AllElements)) 
-		meetingCheckChanForward := make(chan string, len(shared.Graph.AllElements)) 
+		nextForwardQueueChan := make(chan BiSQueueItem, shared.Graph.Len())
+		meetingCheckChanForward := make(chan loadrecipes.ElementID, shared.Graph.Len())
 
 		numForwardWorkers := len(currentForwardItems) 
 		if numForwardWorkers > 0 {
-			log.Printf("[BiS-DEBUG] Iterasi %d: Meluncurkan %d forward worker.", iteration, numForwardWorkers)
+			pathfinding.CountGoroutines(metricsAlgorithm, numForwardWorkers)
 			shared.Wg.Add(numForwardWorkers)
 			for _, item := range currentForwardItems {
 				itemCopy := item 
@@ -351,12 +391,12 @@ func BiSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElement strin
 			currentBackwardItems = append(currentBackwardItems, qBackward.Remove(qBackward.Front()).(BiSQueueItem))
 		}
 
-		nextBackwardQueueChan := make(chan BiSQueueItem, len(shared.Graph.AllElements))
-		meetingCheckChanBackward := make(chan string, len(shared.Graph.AllElements))
+		nextBackwardQueueChan := make(chan BiSQueueItem, shared.Graph.Len())
+		meetingCheckChanBackward := make(chan loadrecipes.ElementID, shared.Graph.Len())
 
 		numBackwardWorkers := len(currentBackwardItems)
 		if numBackwardWorkers > 0 {
-			log.Printf("[BiS-DEBUG] Iterasi %d: Meluncurkan %d backward worker.", iteration, numBackwardWorkers)
+			pathfinding.CountGoroutines(metricsAlgorithm, numBackwardWorkers)
 			shared.Wg.Add(numBackwardWorkers)
 			for _, item := range currentBackwardItems {
 				itemCopy := item
@@ -369,8 +409,6 @@ func BiSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElement strin
 		close(nextBackwardQueueChan)
 		close(meetingCheckChanForward)
 		close(meetingCheckChanBackward)
-		
-		log.Printf("[BiS-DEBUG] Iterasi %d: Semua worker selesai.", iteration)
 
 		for item := range nextForwardQueueChan {
 			qForward.PushBack(item)
@@ -379,22 +417,22 @@ func BiSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElement strin
 			qBackward.PushBack(item)
 		}
 
+		// Titik pertemuan yang bahannya belum dicapai pencarian maju dicoba lagi di iterasi berikutnya.
+		retry := pendingMeetings
+		pendingMeetings = nil
 		for meetingElem := range meetingCheckChanForward {
-			shared.Mutex.RLock()
-			pathFwd, okFwd := shared.VisitedForward[meetingElem]
-			pathBwd, okBwd := shared.VisitedBackward[meetingElem]
-			shared.Mutex.RUnlock()
-			if okFwd && okBwd {
-				processMeetingPoint(shared, meetingElem, pathFwd, pathBwd)
-			}
+			retry = append(retry, meetingElem)
 		}
 		for meetingElem := range meetingCheckChanBackward {
+			retry = append(retry, meetingElem)
+		}
+		for _, meetingElem := range retry {
 			shared.Mutex.RLock()
-			pathFwd, okFwd := shared.VisitedForward[meetingElem]
-			pathBwd, okBwd := shared.VisitedBackward[meetingElem]
+			pathFwd, okFwd := shared.VisitedForward[meetingElem], shared.ForwardSeen.Has(meetingElem)
+			pathBwd, okBwd := shared.VisitedBackward[meetingElem], shared.BackwardSeen.Has(meetingElem)
 			shared.Mutex.RUnlock()
-			if okFwd && okBwd {
-				processMeetingPoint(shared, meetingElem, pathFwd, pathBwd)
+			if okFwd && okBwd && !processMeetingPoint(shared, meetingElem, pathFwd, pathBwd) {
+				pendingMeetings = append(pendingMeetings, meetingElem)
 			}
 		}
 		
@@ -417,10 +455,15 @@ endSearch:
 	shared.Mutex.RUnlock()
 
 	totalNodesExplored := int(atomic.LoadInt64(&shared.NodesExplored))
+	pathfinding.ObserveSearch(metricsAlgorithm, totalNodesExplored)
+	pathfinding.ObserveIterations(metricsAlgorithm, iteration)
 
 	if len(finalResults) == 0 && !graph.BaseElements[targetElement] {
 		log.Printf("[BiS-WARN] Tidak ada resep ditemukan untuk %s setelah %d iterasi.", targetElement, iteration)
-		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, totalNodesExplored, fmt.Errorf("tidak ada jalur resep yang ditemukan untuk elemen '%s'", targetElement)
+		if iteration >= maxIterations {
+			return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, totalNodesExplored, pathfinding.BudgetExceededError(targetElement, maxIterations, totalNodesExplored)
+		}
+		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, totalNodesExplored, pathfinding.NoRecipeError(targetElement, totalNodesExplored)
 	}
 	if iteration >= maxIterations && atomic.LoadInt32(&shared.FoundRecipesCount) < int32(maxRecipes) {
 		log.Printf("[BiS-WARN] Pencarian mencapai batas iterasi maksimum (%d) untuk %s sebelum menemukan %d resep.", maxIterations, targetElement, maxRecipes)
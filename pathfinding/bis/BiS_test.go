@@ -0,0 +1,84 @@
+package bis
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+func TestBiSReportsNoRecipe(t *testing.T) {
+	graph, err := loadrecipes.LoadBiGraphFromReader(strings.NewReader(`[
+		{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+		{"name": "Steam", "tier": 1, "recipes": [["Cloud", "Fire"]]},
+		{"name": "Cloud", "tier": 1, "recipes": [["Steam", "Air"]]},
+		{"name": "Time", "tier": 1, "recipes": []}
+	]`), "cyclic", loadrecipes.LoadOptions{Policy: loadrecipes.NoFilterPolicy})
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	for _, target := range []string{"Steam", "Time"} {
+		if _, _, err := BiSFindMultiplePaths(graph, target, 3); !errors.Is(err, pathfinding.ErrNoRecipe) {
+			t.Errorf("BiSFindMultiplePaths(%s) error = %v, want ErrNoRecipe", target, err)
+		}
+	}
+	results, _, err := BiSFindMultiplePaths(graph, "Mud", 3)
+	if err != nil || len(results.Results) != 1 {
+		t.Errorf("BiSFindMultiplePaths(Mud) = %v, %v; want satu resep", results, err)
+	}
+}
+
+// TestBiSResultsAreValid memastikan setiap resep BiS lengkap: bahan milik partner dan milik
+// cabang lain jalur mundur ikut dibuat, sehingga semua hasil lolos VerifyRecipe.
+func TestBiSResultsAreValid(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	graph, err := loadrecipes.LoadBiGraph("../../elements_filtered.json")
+	if err != nil {
+		t.Fatalf("memuat dataset bawaan: %v", err)
+	}
+	for _, target := range []string{"Brick", "Wall", "Human", "Dragon", "Life"} {
+		t.Run(target, func(t *testing.T) {
+			results, _, err := BiSFindMultiplePaths(graph, target, 5)
+			if err != nil {
+				t.Fatalf("BiSFindMultiplePaths: %v", err)
+			}
+			if len(results.Results) == 0 {
+				t.Fatal("tidak ada resep ditemukan")
+			}
+			for i, result := range results.Results {
+				verification := pathfinding.VerifyRecipe(graph, target, result.Path)
+				if !verification.Valid {
+					t.Errorf("hasil %d tidak valid: %+v", i, verification.Issues)
+					continue
+				}
+				if verification.RecipeID != result.RecipeID {
+					t.Errorf("hasil %d: RecipeID = %s, want %s", i, result.RecipeID, verification.RecipeID)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBiS(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	graph, err := loadrecipes.LoadBiGraph("../../elements_filtered.json")
+	if err != nil {
+		b.Fatalf("memuat dataset bawaan: %v", err)
+	}
+	for _, target := range []string{"Brick", "Human", "Dragon"} {
+		b.Run(target, func(b *testing.B) {
+			for b.Loop() {
+				if _, _, err := BiSFindMultiplePaths(graph, target, 5); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -2,141 +2,169 @@ package dfs
 
 import (
 	"container/list"
-	"fmt"
 	"log"
 
 	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
 	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
 )
 
+// Nilai memo per elemen: belum diketahui, bisa dibuat, atau tidak bisa dibuat.
+const (
+	memoUnknown int8 = 0
+	memoCanMake int8 = 1
+	memoCannot  int8 = -1
+)
+
+// dfsStepTable menyimpan resep yang dipilih untuk tiap elemen, diindeks dengan ElementID.
+type dfsStepTable struct {
+	pairs []loadrecipes.CompactPair
+	has   loadrecipes.Bitset
+}
+
+func newDfsStepTable(n int) *dfsStepTable {
+	return &dfsStepTable{
+		pairs: make([]loadrecipes.CompactPair, n),
+		has:   loadrecipes.NewBitset(n),
+	}
+}
+
+func (t *dfsStepTable) set(id loadrecipes.ElementID, pair loadrecipes.CompactPair) {
+	t.pairs[id] = pair
+	t.has.Set(id)
+}
+
+func (t *dfsStepTable) get(id loadrecipes.ElementID) (loadrecipes.CompactPair, bool) {
+	if !t.has.Has(id) {
+		return loadrecipes.CompactPair{}, false
+	}
+	return t.pairs[id], true
+}
+
+// dfsMaxNodesVisited adalah batas node yang dikunjungi satu pencarian DFS. Dengan
+// Craftability setiap elemen dikunjungi paling banyak sekali, jadi batas ini hanya pengaman
+// untuk graf yang sangat besar.
+var dfsMaxNodesVisited = 1000000
+
+// dfsRecursiveHelperString mengembalikan apakah elemen bisa dibuat, dan jika bisa, mengisi
+// pathSteps dengan resep untuk elemen dan semua bahannya. Hanya resep yang Usable menurut
+// cg.Craftability yang ditelusuri, sehingga rekursi tidak pernah masuk siklus dan setiap
+// hasil aman disimpan di memo. Setelah visitedCounter mencapai dfsMaxNodesVisited, helper
+// selalu gagal; pemanggil membedakannya dari "tidak bisa dibuat" lewat visitedCounter.
 func dfsRecursiveHelperString(
-	elementName string,
-	graph *loadrecipes.BiGraphAlchemy,
-	pathSteps map[string]pathfinding.PathStep,
-	currentlySolving map[string]bool,
-	memo map[string]bool,
+	elementID loadrecipes.ElementID,
+	cg *loadrecipes.CompactGraph,
+	pathSteps *dfsStepTable,
+	memo []int8,
 	visitedCounter *int,
 ) bool {
-	if canBeMade, exists := memo[elementName]; exists {
-		return canBeMade
+	if memo[elementID] != memoUnknown {
+		return memo[elementID] == memoCanMake
+	}
+	if *visitedCounter >= dfsMaxNodesVisited {
+		return false
 	}
 	*visitedCounter++
 
 	// Cek Base Case (Elemen Dasar)
-	if graph.BaseElements[elementName] {
-		memo[elementName] = true
+	if cg.IsBase(elementID) {
+		memo[elementID] = memoCanMake
 		return true
 	}
 
-	if currentlySolving[elementName] {
-		// Tidak simpan di memo karena sebuah siklus di path sementara
-		return false
-	}
-
-	currentlySolving[elementName] = true
-	defer delete(currentlySolving, elementName)
-
-	// Cek apakah elemen punya resep (secara backward)
-	parentPairs, hasRecipes := graph.ChildToParents[elementName]
-	if !hasRecipes {
-		memo[elementName] = false
+	craft := cg.Craftability
+	if !craft.CanMake(elementID) {
+		memo[elementID] = memoCannot
 		return false
 	}
 
-	foundPath := false
-	for _, pair := range parentPairs {
-		canMakeP1 := dfsRecursiveHelperString(pair.Mat1, graph, pathSteps, currentlySolving, memo, visitedCounter)
-		if !canMakeP1 {
+	for _, pair := range cg.ChildToParents[elementID] {
+		if !craft.Usable(elementID, pair) {
 			continue
 		}
-
-		canMakeP2 := dfsRecursiveHelperString(pair.Mat2, graph, pathSteps, currentlySolving, memo, visitedCounter)
-		if !canMakeP2 {
-			continue
-		}
-
-		if canMakeP1 && canMakeP2 {
-			pathSteps[elementName] = pathfinding.PathStep{ChildName: elementName, Parent1Name: pair.Mat1, Parent2Name: pair.Mat2}
-			foundPath = true
-			break
+		// Bahan resep Usable selalu bisa dibuat, jadi gagal di sini berarti batas node tercapai.
+		if !dfsRecursiveHelperString(pair.Mat1, cg, pathSteps, memo, visitedCounter) ||
+			!dfsRecursiveHelperString(pair.Mat2, cg, pathSteps, memo, visitedCounter) {
+			return false
 		}
+		pathSteps.set(elementID, pair)
+		memo[elementID] = memoCanMake
+		return true
 	}
 
-	memo[elementName] = foundPath
-	return foundPath
+	memo[elementID] = memoCannot
+	return false
 }
 
 func DFSFindPathString(graph *loadrecipes.BiGraphAlchemy, targetElementName string) (*pathfinding.Result, error) {
 	if _, targetExists := graph.AllElements[targetElementName]; !targetExists {
-		return nil, fmt.Errorf("elemen target '%s' tidak ditemukan", targetElementName)
+		return nil, pathfinding.UnknownElementError(targetElementName)
 	}
 
 	if graph.BaseElements[targetElementName] {
-		return &pathfinding.Result{Path: []pathfinding.PathStep{}, NodesVisited: 1}, nil
+		result := pathfinding.NewResult([]pathfinding.PathStep{}, 1)
+		return &result, nil
+	}
+
+	cg, err := graph.Compact()
+	if err != nil {
+		return nil, err
 	}
+	targetID, _ := cg.ID(targetElementName)
 
-	pathSteps := make(map[string]pathfinding.PathStep)  
-	currentlySolving := make(map[string]bool)
-	memo := make(map[string]bool)          
-	visitedCount := 0                      
+	pathSteps := newDfsStepTable(cg.Len())
+	memo := make([]int8, cg.Len())
+	visitedCount := 0
 
-	success := dfsRecursiveHelperString(targetElementName, graph, pathSteps, currentlySolving, memo, &visitedCount)
+	success := dfsRecursiveHelperString(targetID, cg, pathSteps, memo, &visitedCount)
+	pathfinding.ObserveSearch(metricsAlgorithm, visitedCount)
 
 	if success {
-		finalPath := reconstructFullPathFromSteps(pathSteps, targetElementName, graph.BaseElements)
-		return &pathfinding.Result{Path: finalPath, NodesVisited: visitedCount}, nil
+		finalPath := reconstructFullPathFromSteps(pathSteps, targetID, cg)
+		result := pathfinding.NewResult(pathfinding.StepsFromCompact(cg, finalPath), visitedCount)
+		return &result, nil
 	}
 
-	nodesExploredFinal := visitedCount
-	if !memo[targetElementName] {
-		
-		
-		if _, inMemo := memo[targetElementName]; !inMemo && !graph.BaseElements[targetElementName] {
-			nodesExploredFinal++
-		}
-		log.Printf("INFO: Elemen '%s' ditandai tidak dapat dibuat (memo=false) oleh DFSFindPathString.\n", targetElementName)
+	if memo[targetID] != memoCannot {
+		return nil, pathfinding.BudgetExceededError(targetElementName, dfsMaxNodesVisited, visitedCount)
 	}
-
-	return nil, fmt.Errorf("tidak ditemukan jalur resep untuk elemen '%s' menggunakan DFSFindPathString (Nodes Explored: %d)", targetElementName, nodesExploredFinal)
+	log.Printf("INFO: Elemen '%s' ditandai tidak dapat dibuat (memo=false) oleh DFSFindPathString.\n", targetElementName)
+	return nil, pathfinding.NoRecipeError(targetElementName, visitedCount)
 }
 
 func reconstructFullPathFromSteps(
-	steps map[string]pathfinding.PathStep,
-	targetElementName string,
-	baseElements map[string]bool,
-) []pathfinding.PathStep {
-	var path []pathfinding.PathStep
-	queue := list.New() 
-	
-	if _, exists := steps[targetElementName]; !exists && !baseElements[targetElementName] {
-		if targetElementName != "" { 
-		}
-		return path 
+	steps *dfsStepTable,
+	targetID loadrecipes.ElementID,
+	cg *loadrecipes.CompactGraph,
+) []loadrecipes.CompactStep {
+	var path []loadrecipes.CompactStep
+	if _, exists := steps.get(targetID); !exists {
+		return path
 	}
 
-	queue.PushBack(targetElementName)
-	processedForThisPathReconstruction := make(map[string]bool) 
+	queue := list.New()
+	queue.PushBack(targetID)
+	processedForThisPathReconstruction := loadrecipes.NewBitset(cg.Len())
 
 	for queue.Len() > 0 {
-		currentElementName := queue.Remove(queue.Front()).(string)
+		currentID := queue.Remove(queue.Front()).(loadrecipes.ElementID)
 
-		if baseElements[currentElementName] || processedForThisPathReconstruction[currentElementName] {
+		if cg.IsBase(currentID) || processedForThisPathReconstruction.Has(currentID) {
 			continue
 		}
 
-		step, exists := steps[currentElementName]
+		pair, exists := steps.get(currentID)
 		if !exists {
-			continue 
+			continue
 		}
-		
-		path = append(path, step) 
-		processedForThisPathReconstruction[currentElementName] = true 
 
-		if !baseElements[step.Parent1Name] && !processedForThisPathReconstruction[step.Parent1Name] {
-			queue.PushBack(step.Parent1Name)
+		path = append(path, loadrecipes.CompactStep{Child: currentID, Pair: pair})
+		processedForThisPathReconstruction.Set(currentID)
+
+		if !cg.IsBase(pair.Mat1) && !processedForThisPathReconstruction.Has(pair.Mat1) {
+			queue.PushBack(pair.Mat1)
 		}
-		if !baseElements[step.Parent2Name] && !processedForThisPathReconstruction[step.Parent2Name] {
-			queue.PushBack(step.Parent2Name)
+		if !cg.IsBase(pair.Mat2) && !processedForThisPathReconstruction.Has(pair.Mat2) {
+			queue.PushBack(pair.Mat2)
 		}
 	}
 
@@ -145,6 +173,3 @@ func reconstructFullPathFromSteps(
 	}
 	return path
 }
-
-
-
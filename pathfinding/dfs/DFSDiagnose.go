@@ -0,0 +1,143 @@
+package dfs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// DiagnoseElement memakai cg.Craftability (hasil yang sama dengan DFSFindPathString) untuk
+// menjelaskan kenapa elemen tidak bisa dibuat, tanpa menjalankan pencarian lagi. Jika elemen
+// bisa dibuat, Craftable = true. NodesVisited adalah jumlah elemen yang diperiksa.
+func DiagnoseElement(graph *loadrecipes.BiGraphAlchemy, targetElementName string) (*pathfinding.Diagnosis, error) {
+	diagnosis := &pathfinding.Diagnosis{Element: targetElementName}
+
+	if _, targetExists := graph.AllElements[targetElementName]; !targetExists {
+		diagnosis.Reason = pathfinding.ReasonUnknownElement
+		diagnosis.Message = fmt.Sprintf("elemen '%s' tidak ditemukan dalam data", targetElementName)
+		return diagnosis, nil
+	}
+
+	cg, err := graph.Compact()
+	if err != nil {
+		return nil, err
+	}
+	targetID, _ := cg.ID(targetElementName)
+	diagnosis.NodesVisited = 1
+
+	craft := cg.Craftability
+	if craft.CanMake(targetID) {
+		diagnosis.Craftable = true
+		diagnosis.Reason = pathfinding.ReasonCraftable
+		diagnosis.Message = fmt.Sprintf("elemen '%s' dapat dibuat", targetElementName)
+		return diagnosis, nil
+	}
+
+	if len(cg.ChildToParents[targetID]) == 0 {
+		diagnosis.Reason = pathfinding.ReasonNoRecipes
+		diagnosis.Message = fmt.Sprintf("elemen '%s' tidak memiliki resep setelah scraping/filter", targetElementName)
+		return diagnosis, nil
+	}
+
+	for _, pair := range cg.ChildToParents[targetID] {
+		blocker, _ := firstBlockingIngredient(cg, pair)
+		diagnosis.BlockedRecipes = append(diagnosis.BlockedRecipes, pathfinding.BlockedRecipe{
+			Parent1Name: cg.Name(pair.Mat1),
+			Parent2Name: cg.Name(pair.Mat2),
+			BlockedBy:   cg.Name(blocker),
+		})
+	}
+
+	chain, end := blockingChain(cg, targetID)
+	diagnosis.BlockingChain = chain
+	diagnosis.NodesVisited = len(chain)
+	if end == chainEndsInCycle {
+		// Elemen terakhir rantai siklus sudah diperiksa sebelumnya.
+		diagnosis.NodesVisited--
+	}
+	diagnosis.Reason = pathfinding.ReasonBlockedIngredients
+	rootCause := chain[len(chain)-1]
+	var cause string
+	switch end {
+	case chainEndsInCycle:
+		cause = fmt.Sprintf("rantai berakhir di siklus pada '%s'", rootCause)
+	case chainEndsWithoutRecipes:
+		cause = fmt.Sprintf("'%s' tidak memiliki resep", rootCause)
+	default:
+		cause = fmt.Sprintf("'%s' tidak bisa dibuat dari elemen dasar", rootCause)
+	}
+	diagnosis.Message = fmt.Sprintf("semua resep '%s' bergantung pada elemen yang tidak bisa dibuat; %s: %s",
+		targetElementName, cause, strings.Join(chain, " -> "))
+	return diagnosis, nil
+}
+
+// firstBlockingIngredient mengembalikan bahan pertama dari pair yang tidak bisa dibuat.
+func firstBlockingIngredient(cg *loadrecipes.CompactGraph, pair loadrecipes.CompactPair) (loadrecipes.ElementID, bool) {
+	for _, ingredient := range [2]loadrecipes.ElementID{pair.Mat1, pair.Mat2} {
+		if !cg.Craftability.CanMake(ingredient) {
+			return ingredient, true
+		}
+	}
+	return pair.Mat1, false
+}
+
+// chainEnd adalah alasan blockingChain berhenti.
+type chainEnd int
+
+const (
+	// chainEndsWithoutRecipes: elemen terakhir tidak punya resep sama sekali.
+	chainEndsWithoutRecipes chainEnd = iota
+	// chainEndsInCycle: elemen terakhir sudah ada di rantai.
+	chainEndsInCycle
+	// chainEndsUnexplained: tidak ada resep elemen terakhir yang punya bahan penghalang.
+	// Tidak terjadi untuk elemen yang memang tidak craftable; hanya penjagaan.
+	chainEndsUnexplained
+)
+
+// blockingChain mengikuti bahan penghalang dari elemen target sampai ketemu elemen tanpa
+// resep, atau elemen yang sudah ada di rantai (siklus). Semua resep setiap elemen diperiksa;
+// penghalang yang belum ada di rantai diutamakan, supaya siklus hanya dilaporkan jika
+// memang tidak ada jalan lain.
+func blockingChain(cg *loadrecipes.CompactGraph, targetID loadrecipes.ElementID) ([]string, chainEnd) {
+	inChain := loadrecipes.NewBitset(cg.Len())
+	chain := []string{}
+	current := targetID
+	for {
+		chain = append(chain, cg.Name(current))
+		inChain.Set(current)
+
+		recipes := cg.ChildToParents[current]
+		if len(recipes) == 0 {
+			return chain, chainEndsWithoutRecipes
+		}
+		var next, cycleAt loadrecipes.ElementID
+		foundNext, foundCycle := false, false
+		for _, pair := range recipes {
+			for _, ingredient := range [2]loadrecipes.ElementID{pair.Mat1, pair.Mat2} {
+				if cg.Craftability.CanMake(ingredient) {
+					continue
+				}
+				if !inChain.Has(ingredient) {
+					next, foundNext = ingredient, true
+					break
+				}
+				if !foundCycle {
+					cycleAt, foundCycle = ingredient, true
+				}
+			}
+			if foundNext {
+				break
+			}
+		}
+		switch {
+		case foundNext:
+			current = next
+		case foundCycle:
+			return append(chain, cg.Name(cycleAt)), chainEndsInCycle
+		default:
+			return chain, chainEndsUnexplained
+		}
+	}
+}
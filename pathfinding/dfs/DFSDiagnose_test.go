@@ -0,0 +1,76 @@
+package dfs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// diagnoseDataset: resep pertama Selfish memakai dirinya sendiri, resep keduanya terhalang
+// Lonely yang tidak punya resep. Ping dan Pong hanya bisa dibuat dari satu sama lain.
+const diagnoseDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Earth", "Water"]]},
+	{"name": "Lonely", "tier": 1, "recipes": []},
+	{"name": "Selfish", "tier": 2, "recipes": [["Selfish", "Air"], ["Lonely", "Air"]]},
+	{"name": "Ping", "tier": 1, "recipes": [["Pong", "Air"]]},
+	{"name": "Pong", "tier": 1, "recipes": [["Ping", "Air"]]}
+]`
+
+func TestDiagnoseElement(t *testing.T) {
+	graph, err := loadrecipes.LoadBiGraphFromReader(strings.NewReader(diagnoseDataset), "diagnose", loadrecipes.LoadOptions{Policy: loadrecipes.NoFilterPolicy})
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+
+	tests := []struct {
+		element     string
+		wantReason  string
+		wantChain   []string
+		wantMessage string
+		wantNodes   int
+	}{
+		{element: "Mud", wantReason: pathfinding.ReasonCraftable, wantMessage: "dapat dibuat", wantNodes: 1},
+		{element: "Unobtainium", wantReason: pathfinding.ReasonUnknownElement, wantMessage: "tidak ditemukan"},
+		{element: "Lonely", wantReason: pathfinding.ReasonNoRecipes, wantMessage: "'Lonely' tidak memiliki resep", wantNodes: 1},
+		{
+			element:     "Selfish",
+			wantReason:  pathfinding.ReasonBlockedIngredients,
+			wantChain:   []string{"Selfish", "Lonely"},
+			wantMessage: "'Lonely' tidak memiliki resep",
+			wantNodes:   2,
+		},
+		{
+			element:     "Ping",
+			wantReason:  pathfinding.ReasonBlockedIngredients,
+			wantChain:   []string{"Ping", "Pong", "Ping"},
+			wantMessage: "siklus pada 'Ping'",
+			wantNodes:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.element, func(t *testing.T) {
+			diagnosis, err := DiagnoseElement(graph, tt.element)
+			if err != nil {
+				t.Fatalf("DiagnoseElement: %v", err)
+			}
+			if diagnosis.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", diagnosis.Reason, tt.wantReason)
+			}
+			if diagnosis.Craftable != (tt.wantReason == pathfinding.ReasonCraftable) {
+				t.Errorf("Craftable = %v", diagnosis.Craftable)
+			}
+			if tt.wantChain != nil && !reflect.DeepEqual(diagnosis.BlockingChain, tt.wantChain) {
+				t.Errorf("BlockingChain = %v, want %v", diagnosis.BlockingChain, tt.wantChain)
+			}
+			if !strings.Contains(diagnosis.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want berisi %q", diagnosis.Message, tt.wantMessage)
+			}
+			if diagnosis.NodesVisited != tt.wantNodes {
+				t.Errorf("NodesVisited = %d, want %d", diagnosis.NodesVisited, tt.wantNodes)
+			}
+		})
+	}
+}
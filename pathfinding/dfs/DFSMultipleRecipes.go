@@ -1,11 +1,9 @@
 package dfs
 
 import (
-	"fmt"
+	"errors"
 	"log"
 	"math/rand"
-	"sort"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -14,8 +12,11 @@ import (
 	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
 )
 
+// metricsAlgorithm adalah label algoritma DFS di metrik pencarian.
+const metricsAlgorithm = "dfs"
+
 type workerResult struct {
-	path         []pathfinding.PathStep
+	path         []loadrecipes.CompactStep
 	nodesVisited int
 	err          error
 }
@@ -31,18 +32,31 @@ type workerConfig struct {
 
 func DFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName string, maxRecipes int) (*pathfinding.MultipleResult, int, error) {
 	if _, targetExists := graph.AllElements[targetElementName]; !targetExists {
-		return nil, 0, fmt.Errorf("elemen target '%s' tidak ditemukan dalam data", targetElementName)
+		return nil, 0, pathfinding.UnknownElementError(targetElementName)
 	}
 	if maxRecipes <= 0 {
-		return nil, 0, fmt.Errorf("parameter maxRecipes harus positif")
+		return nil, 0, pathfinding.InvalidParamError("maxPaths", maxRecipes, "harus integer positif")
 	}
 	if graph.BaseElements[targetElementName] {
-		return &pathfinding.MultipleResult{Results: []pathfinding.Result{{Path: []pathfinding.PathStep{}, NodesVisited: 1}}}, 1, nil
+		return &pathfinding.MultipleResult{Results: []pathfinding.Result{pathfinding.NewResult([]pathfinding.PathStep{}, 1)}}, 1, nil
 	}
 
-	initialRecipesForTarget, hasRecipes := graph.ChildToParents[targetElementName]
-	if !hasRecipes {
-		return nil, 0, fmt.Errorf("elemen target '%s' tidak memiliki resep", targetElementName)
+	cg, err := graph.Compact()
+	if err != nil {
+		return nil, 0, err
+	}
+	targetID, _ := cg.ID(targetElementName)
+
+	// Hanya resep Usable yang dibagikan ke worker: bahannya pasti bisa dibuat tanpa melewati
+	// target lagi, jadi worker tidak perlu mencoba resep yang pasti gagal.
+	var initialRecipesForTarget []loadrecipes.CompactPair
+	for _, pair := range cg.ChildToParents[targetID] {
+		if cg.Craftability.Usable(targetID, pair) {
+			initialRecipesForTarget = append(initialRecipesForTarget, pair)
+		}
+	}
+	if len(initialRecipesForTarget) == 0 {
+		return nil, 0, pathfinding.NoRecipeError(targetElementName, 0)
 	}
 
 	var wg sync.WaitGroup
@@ -61,9 +75,6 @@ func DFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 
 	resultsProcessingChan := make(chan workerResult, numWorkers)
 
-	sharedOverallCanBeMadeMemo := make(map[string]bool)
-	var sharedMemoMutex sync.Mutex
-
 	var pathsFoundCounter int32
 	var totalNodesVisitedByWorkers int64
 
@@ -80,16 +91,14 @@ func DFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 		workerCount++
 
 		go dfsWorkerFindOnePathWithInitialRecipe(
-			graph,
-			targetElementName,
+			cg,
+			targetID,
 			initialRecipe,
 			maxRecipes,
 			&pathsFoundCounter,
 			&totalNodesVisitedByWorkers,
 			resultsProcessingChan,
 			&wg,
-			sharedOverallCanBeMadeMemo,
-			&sharedMemoMutex,
 			doneChan,
 			0,
 			time.Now().UnixNano()+int64(workerCount),
@@ -106,7 +115,7 @@ func DFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 
 		explorationDepth := 1 + (i % 5)
 
-		var initialRecipe loadrecipes.PairMats
+		var initialRecipe loadrecipes.CompactPair
 		if i < len(initialRecipesForTarget)*2 {
 			recipeIndex := i % len(initialRecipesForTarget)
 			initialRecipe = initialRecipesForTarget[recipeIndex]
@@ -118,16 +127,14 @@ func DFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 		randomSeed := time.Now().UnixNano() + int64(i*1000)
 
 		go dfsWorkerFindOnePathWithInitialRecipe(
-			graph,
-			targetElementName,
+			cg,
+			targetID,
 			initialRecipe,
 			maxRecipes,
 			&pathsFoundCounter,
 			&totalNodesVisitedByWorkers,
 			resultsProcessingChan,
 			&wg,
-			sharedOverallCanBeMadeMemo,
-			&sharedMemoMutex,
 			doneChan,
 			explorationDepth,
 			randomSeed,
@@ -138,25 +145,31 @@ func DFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 		wg.Wait()
 		close(resultsProcessingChan)
 	}()
+	pathfinding.CountGoroutines(metricsAlgorithm, workerCount+1)
 
 	var collectedUniquePathResults []pathfinding.Result
-	pathSignatures := make(map[string]bool)
+	uniqueRecipes := pathfinding.NewRecipeSet()
 	var accumulatedNodesForUniquePaths int
+	var budgetErr error
 
 	for workerRes := range resultsProcessingChan {
+		if errors.Is(workerRes.err, pathfinding.ErrBudgetExceeded) {
+			budgetErr = workerRes.err
+			continue
+		}
 		if workerRes.err != nil {
 			log.Printf("Error dari worker untuk target %s: %v", targetElementName, workerRes.err)
 			continue
 		}
 
 		if len(workerRes.path) > 0 {
-			pathSignature := generatePathSignature(workerRes.path)
+			tree := pathfinding.NewRecipeTreeFromCompact(cg, workerRes.path)
 
-			if !pathSignatures[pathSignature] {
-				pathSignatures[pathSignature] = true
+			if uniqueRecipes.Add(tree) {
 				collectedUniquePathResults = append(collectedUniquePathResults, pathfinding.Result{
-					Path:         workerRes.path,
+					Path:         pathfinding.StepsFromCompact(cg, workerRes.path),
 					NodesVisited: workerRes.nodesVisited,
+					RecipeID:     tree.ID(),
 				})
 				accumulatedNodesForUniquePaths += workerRes.nodesVisited
 
@@ -171,30 +184,33 @@ func DFSFindMultiplePaths(graph *loadrecipes.BiGraphAlchemy, targetElementName s
 						for range resultsProcessingChan {
 						}
 					}()
+					pathfinding.CountGoroutines(metricsAlgorithm, 1)
 					break
 				}
 			}
 		}
 	}
 
+	pathfinding.ObserveSearch(metricsAlgorithm, accumulatedNodesForUniquePaths)
+	if len(collectedUniquePathResults) == 0 && budgetErr != nil {
+		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, accumulatedNodesForUniquePaths, budgetErr
+	}
 	if len(collectedUniquePathResults) == 0 && !graph.BaseElements[targetElementName] {
-		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, accumulatedNodesForUniquePaths, fmt.Errorf("tidak ada jalur resep unik yang ditemukan untuk elemen '%s' setelah semua worker selesai", targetElementName)
+		return &pathfinding.MultipleResult{Results: []pathfinding.Result{}}, accumulatedNodesForUniquePaths, pathfinding.NoRecipeError(targetElementName, accumulatedNodesForUniquePaths)
 	}
 
 	return &pathfinding.MultipleResult{Results: collectedUniquePathResults}, accumulatedNodesForUniquePaths, nil
 }
 
 func dfsWorkerFindOnePathWithInitialRecipe(
-	graph *loadrecipes.BiGraphAlchemy,
-	targetElementName string,
-	initialRecipeForTargetElement loadrecipes.PairMats,
+	cg *loadrecipes.CompactGraph,
+	targetID loadrecipes.ElementID,
+	initialRecipeForTargetElement loadrecipes.CompactPair,
 	maxRecipesGlobalLimit int,
 	pathsFoundGlobalCounter *int32,
 	overallNodesVisitedCounter *int64,
 	resultsChan chan<- workerResult,
 	wg *sync.WaitGroup,
-	sharedOverallCanBeMadeMemo map[string]bool,
-	sharedMemoMutex *sync.Mutex,
 	doneChan <-chan struct{},
 	explorationDepth int,
 	randomSeed int64,
@@ -203,9 +219,8 @@ func dfsWorkerFindOnePathWithInitialRecipe(
 
 	localRNG := rand.New(rand.NewSource(randomSeed))
 
-	pathStepsForThisWorker := make(map[string]pathfinding.PathStep)
-	currentlySolvingForThisWorker := make(map[string]bool)
-	memoForThisWorkerBranch := make(map[string]bool)
+	pathStepsForThisWorker := newDfsStepTable(cg.Len())
+	memoForThisWorkerBranch := make([]int8, cg.Len())
 
 	var nodesVisitedByThisWorker int
 
@@ -218,70 +233,49 @@ func dfsWorkerFindOnePathWithInitialRecipe(
 		}
 	}
 
-	canMakeP1 := dfsRecursiveHelperForWorkerPathEnhanced(
-		initialRecipeForTargetElement.Mat1,
-		graph,
-		pathStepsForThisWorker,
-		currentlySolvingForThisWorker,
-		memoForThisWorkerBranch,
-		sharedOverallCanBeMadeMemo,
-		sharedMemoMutex,
-		&nodesVisitedByThisWorker,
-		doneChan,
-		pathsFoundGlobalCounter,
-		maxRecipesGlobalLimit,
-		explorationDepth,
-		localRNG,
-		0,
-	)
-
-	if !canMakeP1 {
-		resultsChan <- workerResult{path: nil, nodesVisited: nodesVisitedByThisWorker, err: nil}
-		return
-	}
-
-	canMakeP2 := dfsRecursiveHelperForWorkerPathEnhanced(
-		initialRecipeForTargetElement.Mat2,
-		graph,
-		pathStepsForThisWorker,
-		currentlySolvingForThisWorker,
-		memoForThisWorkerBranch,
-		sharedOverallCanBeMadeMemo,
-		sharedMemoMutex,
-		&nodesVisitedByThisWorker,
-		doneChan,
-		pathsFoundGlobalCounter,
-		maxRecipesGlobalLimit,
-		explorationDepth,
-		localRNG,
-		0,
-	)
-
-	if !canMakeP2 {
-		resultsChan <- workerResult{path: nil, nodesVisited: nodesVisitedByThisWorker, err: nil}
+	for _, ingredient := range [2]loadrecipes.ElementID{initialRecipeForTargetElement.Mat1, initialRecipeForTargetElement.Mat2} {
+		canMake := dfsRecursiveHelperForWorkerPathEnhanced(
+			ingredient,
+			cg,
+			pathStepsForThisWorker,
+			memoForThisWorkerBranch,
+			&nodesVisitedByThisWorker,
+			doneChan,
+			pathsFoundGlobalCounter,
+			maxRecipesGlobalLimit,
+			explorationDepth,
+			localRNG,
+			0,
+		)
+		if canMake {
+			continue
+		}
+		var err error
+		if nodesVisitedByThisWorker >= dfsMaxNodesVisited {
+			err = pathfinding.BudgetExceededError(cg.Name(targetID), dfsMaxNodesVisited, nodesVisitedByThisWorker)
+		}
+		atomic.AddInt64(overallNodesVisitedCounter, int64(nodesVisitedByThisWorker))
+		resultsChan <- workerResult{path: nil, nodesVisited: nodesVisitedByThisWorker, err: err}
 		return
 	}
 
-	pathStepsForThisWorker[targetElementName] = pathfinding.PathStep{
-		ChildName:   targetElementName,
-		Parent1Name: initialRecipeForTargetElement.Mat1,
-		Parent2Name: initialRecipeForTargetElement.Mat2,
-	}
+	pathStepsForThisWorker.set(targetID, initialRecipeForTargetElement)
 
-	reconstructedPath := reconstructFullPathFromSteps(pathStepsForThisWorker, targetElementName, graph.BaseElements)
+	reconstructedPath := reconstructFullPathFromSteps(pathStepsForThisWorker, targetID, cg)
 
+	atomic.AddInt64(overallNodesVisitedCounter, int64(nodesVisitedByThisWorker))
 	resultsChan <- workerResult{path: reconstructedPath, nodesVisited: nodesVisitedByThisWorker, err: nil}
 	atomic.AddInt32(pathsFoundGlobalCounter, 1)
 }
 
+// dfsRecursiveHelperForWorkerPathEnhanced sama dengan dfsRecursiveHelperString, tetapi urutan
+// resep dan bahan diacak dekat target supaya worker menemukan tree yang berbeda-beda. Helper
+// gagal jika pencarian dibatalkan atau batas node tercapai.
 func dfsRecursiveHelperForWorkerPathEnhanced(
-	elementName string,
-	graph *loadrecipes.BiGraphAlchemy,
-	pathStepsThisBranch map[string]pathfinding.PathStep,
-	currentlySolvingThisBranch map[string]bool,
-	memoForThisWorkerBranch map[string]bool,
-	sharedOverallCanBeMadeMemo map[string]bool,
-	sharedMemoMutex *sync.Mutex,
+	elementID loadrecipes.ElementID,
+	cg *loadrecipes.CompactGraph,
+	pathStepsThisBranch *dfsStepTable,
+	memoForThisWorkerBranch []int8,
 	nodesVisitedCounter *int,
 	doneChan <-chan struct{},
 	pathsFoundGlobalCounter *int32,
@@ -299,41 +293,26 @@ func dfsRecursiveHelperForWorkerPathEnhanced(
 		}
 	}
 
-	if canBeMade, exists := memoForThisWorkerBranch[elementName]; exists {
-		return canBeMade
+	if memoForThisWorkerBranch[elementID] != memoUnknown {
+		return memoForThisWorkerBranch[elementID] == memoCanMake
 	}
-
-	if graph.BaseElements[elementName] {
-		(*nodesVisitedCounter)++
-		memoForThisWorkerBranch[elementName] = true
-		return true
-	}
-
-	if currentlySolvingThisBranch[elementName] {
+	if *nodesVisitedCounter >= dfsMaxNodesVisited {
 		return false
 	}
-	currentlySolvingThisBranch[elementName] = true
-	defer delete(currentlySolvingThisBranch, elementName)
+	(*nodesVisitedCounter)++
 
-	sharedMemoMutex.Lock()
-	if knownGlobalStatus, exists := sharedOverallCanBeMadeMemo[elementName]; exists && !knownGlobalStatus {
-		sharedMemoMutex.Unlock()
-		memoForThisWorkerBranch[elementName] = false
-		return false
+	if cg.IsBase(elementID) {
+		memoForThisWorkerBranch[elementID] = memoCanMake
+		return true
 	}
-	sharedMemoMutex.Unlock()
-
-	(*nodesVisitedCounter)++
 
-	recipesForCurrentElement, hasRecipes := graph.ChildToParents[elementName]
-	if !hasRecipes {
-		sharedMemoMutex.Lock()
-		sharedOverallCanBeMadeMemo[elementName] = false
-		sharedMemoMutex.Unlock()
-		memoForThisWorkerBranch[elementName] = false
+	craft := cg.Craftability
+	if !craft.CanMake(elementID) {
+		memoForThisWorkerBranch[elementID] = memoCannot
 		return false
 	}
 
+	recipesForCurrentElement := cg.ChildToParents[elementID]
 	shuffledRecipeIndices := make([]int, len(recipesForCurrentElement))
 	for i := range shuffledRecipeIndices {
 		shuffledRecipeIndices[i] = i
@@ -349,72 +328,28 @@ func dfsRecursiveHelperForWorkerPathEnhanced(
 
 	for _, index := range shuffledRecipeIndices {
 		recipePair := recipesForCurrentElement[index]
+		if !craft.Usable(elementID, recipePair) {
+			continue
+		}
 
 		parent1, parent2 := recipePair.Mat1, recipePair.Mat2
 		if explorationDepth > 0 && currentDepth <= explorationDepth && localRNG.Float64() < 0.3 {
 			parent1, parent2 = parent2, parent1
 		}
 
-		canMakeP1 := dfsRecursiveHelperForWorkerPathEnhanced(
-			parent1, graph, pathStepsThisBranch, currentlySolvingThisBranch, memoForThisWorkerBranch,
-			sharedOverallCanBeMadeMemo, sharedMemoMutex, nodesVisitedCounter, doneChan,
-			pathsFoundGlobalCounter, maxRecipesGlobalLimit, explorationDepth, localRNG, currentDepth+1)
-		if !canMakeP1 {
-			continue
-		}
-
-		canMakeP2 := dfsRecursiveHelperForWorkerPathEnhanced(
-			parent2, graph, pathStepsThisBranch, currentlySolvingThisBranch, memoForThisWorkerBranch,
-			sharedOverallCanBeMadeMemo, sharedMemoMutex, nodesVisitedCounter, doneChan,
-			pathsFoundGlobalCounter, maxRecipesGlobalLimit, explorationDepth, localRNG, currentDepth+1)
-		if !canMakeP2 {
-			continue
+		for _, parent := range [2]loadrecipes.ElementID{parent1, parent2} {
+			if !dfsRecursiveHelperForWorkerPathEnhanced(
+				parent, cg, pathStepsThisBranch, memoForThisWorkerBranch, nodesVisitedCounter, doneChan,
+				pathsFoundGlobalCounter, maxRecipesGlobalLimit, explorationDepth, localRNG, currentDepth+1) {
+				return false
+			}
 		}
 
-		pathStepsThisBranch[elementName] = pathfinding.PathStep{ChildName: elementName, Parent1Name: parent1, Parent2Name: parent2}
-		memoForThisWorkerBranch[elementName] = true
+		pathStepsThisBranch.set(elementID, recipePair)
+		memoForThisWorkerBranch[elementID] = memoCanMake
 		return true
 	}
 
-	memoForThisWorkerBranch[elementName] = false
+	memoForThisWorkerBranch[elementID] = memoCannot
 	return false
 }
-
-func generatePathSignature(path []pathfinding.PathStep) string {
-	if len(path) == 0 {
-		return "base_element_or_empty_path"
-	}
-
-	pathCopy := make([]pathfinding.PathStep, len(path))
-	copy(pathCopy, path)
-
-	sort.Slice(pathCopy, func(i, j int) bool {
-		if pathCopy[i].ChildName != pathCopy[j].ChildName {
-			return pathCopy[i].ChildName < pathCopy[j].ChildName
-		}
-		p1_i, p2_i := pathCopy[i].Parent1Name, pathCopy[i].Parent2Name
-		if p1_i > p2_i {
-			p1_i, p2_i = p2_i, p1_i
-		}
-
-		p1_j, p2_j := pathCopy[j].Parent1Name, pathCopy[j].Parent2Name
-		if p1_j > p2_j {
-			p1_j, p2_j = p2_j, p1_j
-		}
-
-		if p1_i != p1_j {
-			return p1_i < p1_j
-		}
-		return p2_i < p2_j
-	})
-
-	var signatureParts []string
-	for _, step := range pathCopy {
-		parent1, parent2 := step.Parent1Name, step.Parent2Name
-		if parent1 > parent2 {
-			parent1, parent2 = parent2, parent1
-		}
-		signatureParts = append(signatureParts, fmt.Sprintf("%s=(%s+%s)", step.ChildName, parent1, parent2))
-	}
-	return strings.Join(signatureParts, ";")
-}
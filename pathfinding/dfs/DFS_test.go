@@ -0,0 +1,107 @@
+package dfs
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// cyclicDataset meniru hasil scrape tanpa filter: Mud, Brick, dan Wall saling bergantung,
+// Steam dan Cloud hanya bisa dibuat dari satu sama lain.
+const cyclicDataset = `[
+	{"name": "Mud", "tier": 1, "recipes": [["Brick", "Water"], ["Earth", "Water"]]},
+	{"name": "Brick", "tier": 2, "recipes": [["Wall", "Fire"], ["Mud", "Fire"]]},
+	{"name": "Wall", "tier": 3, "recipes": [["Brick", "Brick"]]},
+	{"name": "Steam", "tier": 1, "recipes": [["Cloud", "Fire"]]},
+	{"name": "Cloud", "tier": 1, "recipes": [["Steam", "Air"]]}
+]`
+
+func loadCyclicGraph(t *testing.T) *loadrecipes.BiGraphAlchemy {
+	t.Helper()
+	graph, err := loadrecipes.LoadBiGraphFromReader(strings.NewReader(cyclicDataset), "cyclic", loadrecipes.LoadOptions{Policy: loadrecipes.NoFilterPolicy})
+	if err != nil {
+		t.Fatalf("memuat dataset: %v", err)
+	}
+	return graph
+}
+
+func TestDFSFindPathStringCyclic(t *testing.T) {
+	graph := loadCyclicGraph(t)
+
+	result, err := DFSFindPathString(graph, "Wall")
+	if err != nil {
+		t.Fatalf("DFSFindPathString(Wall): %v", err)
+	}
+	if verification := pathfinding.VerifyRecipe(graph, "Wall", result.Path); !verification.Valid {
+		t.Errorf("resep Wall tidak valid: %+v", verification.Issues)
+	}
+
+	_, err = DFSFindPathString(graph, "Steam")
+	if !errors.Is(err, pathfinding.ErrNoRecipe) {
+		t.Errorf("DFSFindPathString(Steam) error = %v, want ErrNoRecipe", err)
+	}
+}
+
+func TestDFSFindPathStringBudget(t *testing.T) {
+	graph := loadCyclicGraph(t)
+	defer func(limit int) { dfsMaxNodesVisited = limit }(dfsMaxNodesVisited)
+	dfsMaxNodesVisited = 2
+
+	_, err := DFSFindPathString(graph, "Wall")
+	if !errors.Is(err, pathfinding.ErrBudgetExceeded) {
+		t.Errorf("error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestDFSFindMultiplePathsCyclic(t *testing.T) {
+	graph := loadCyclicGraph(t)
+
+	results, _, err := DFSFindMultiplePaths(graph, "Wall", 3)
+	if err != nil {
+		t.Fatalf("DFSFindMultiplePaths(Wall): %v", err)
+	}
+	if len(results.Results) == 0 {
+		t.Fatal("tidak ada resep untuk Wall")
+	}
+	for _, result := range results.Results {
+		if verification := pathfinding.VerifyRecipe(graph, "Wall", result.Path); !verification.Valid {
+			t.Errorf("resep Wall tidak valid: %+v", verification.Issues)
+		}
+	}
+
+	_, _, err = DFSFindMultiplePaths(graph, "Cloud", 3)
+	if !errors.Is(err, pathfinding.ErrNoRecipe) {
+		t.Errorf("DFSFindMultiplePaths(Cloud) error = %v, want ErrNoRecipe", err)
+	}
+}
+
+func BenchmarkDFS(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	graph, err := loadrecipes.LoadBiGraph("../../elements_filtered.json")
+	if err != nil {
+		b.Fatalf("memuat dataset bawaan: %v", err)
+	}
+	for _, target := range []string{"Brick", "Human", "Dragon"} {
+		b.Run(target, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := DFSFindPathString(graph, target); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(target+"/multiple", func(b *testing.B) {
+			for b.Loop() {
+				if _, _, err := DFSFindMultiplePaths(graph, target, 5); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
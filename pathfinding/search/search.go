@@ -0,0 +1,66 @@
+// Package search memilih algoritma pencarian resep berdasarkan nama, dipakai bersama oleh
+// endpoint HTTP dan CLI.
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/bfs"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/bis"
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding/dfs"
+)
+
+// Nama algoritma yang bisa dipakai di Find.
+const (
+	AlgorithmBFS = "bfs"
+	AlgorithmDFS = "dfs"
+	AlgorithmBiS = "bis"
+)
+
+// Algorithms adalah semua nama algoritma yang didukung Find.
+var Algorithms = []string{AlgorithmBFS, AlgorithmDFS, AlgorithmBiS}
+
+// UnknownAlgorithmError dikembalikan Find untuk nama algoritma yang tidak dikenal.
+type UnknownAlgorithmError struct {
+	Algorithm string
+}
+
+func (e *UnknownAlgorithmError) Error() string {
+	return fmt.Sprintf("algoritma '%s' tidak dikenal (pilihan: %s)", e.Algorithm, strings.Join(Algorithms, ", "))
+}
+
+// Find menjalankan algoritma pencarian dan mengembalikan paling banyak maxPaths resep.
+// DFS dengan maxPaths 1 memakai pencarian single recipe, sama seperti /api/pathfinding/dfs-single.
+func Find(graph *loadrecipes.BiGraphAlchemy, algorithm, target string, maxPaths int) ([]pathfinding.Result, error) {
+	switch algorithm {
+	case AlgorithmBFS:
+		result, err := bfs.BFSFindMultiplePaths(graph, target, maxPaths)
+		if err != nil {
+			return nil, err
+		}
+		return result.Results, nil
+	case AlgorithmDFS:
+		if maxPaths <= 1 {
+			result, err := dfs.DFSFindPathString(graph, target)
+			if err != nil {
+				return nil, err
+			}
+			return []pathfinding.Result{*result}, nil
+		}
+		result, _, err := dfs.DFSFindMultiplePaths(graph, target, maxPaths)
+		if err != nil {
+			return nil, err
+		}
+		return result.Results, nil
+	case AlgorithmBiS:
+		result, _, err := bis.BiSFindMultiplePaths(graph, target, maxPaths)
+		if err != nil {
+			return nil, err
+		}
+		return result.Results, nil
+	}
+	return nil, &UnknownAlgorithmError{Algorithm: algorithm}
+}
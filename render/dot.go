@@ -0,0 +1,55 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// WriteDOT menulis resep-resep target sebagai satu graf Graphviz DOT. Setiap resep menjadi
+// subgraph cluster tersendiri; node elemen berbentuk box dan setiap kombinasi menjadi node
+// "+" kecil yang menerima dua bahan dan menunjuk ke hasilnya.
+func WriteDOT(w io.Writer, target string, results []pathfinding.Result) error {
+	graphs, err := newRecipeGraphs(target, results)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", strconv.Quote("resep "+target))
+	fmt.Fprintln(bw, "  rankdir=BT;")
+	fmt.Fprintln(bw, "  node [fontname=\"sans-serif\"];")
+	for i, result := range results {
+		graph := graphs[i]
+		prefix := fmt.Sprintf("r%d_", i)
+
+		fmt.Fprintf(bw, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(bw, "    label=%s;\n", strconv.Quote(recipeLabel(i, result)))
+		for _, row := range graph.rows() {
+			for _, name := range row {
+				attrs := "shape=box, style=\"rounded,filled\", fillcolor=\"#eef4ff\""
+				if _, crafted := graph.Steps[name]; !crafted {
+					attrs = "shape=box, style=\"rounded,filled\", fillcolor=\"#fff7e0\""
+				}
+				if name == target {
+					attrs += ", penwidth=2"
+				}
+				fmt.Fprintf(bw, "    %s [label=%s, %s];\n", strconv.Quote(prefix+name), strconv.Quote(name), attrs)
+			}
+		}
+		for _, child := range graph.sortedChildren() {
+			step := graph.Steps[child]
+			combo := strconv.Quote(prefix + "+" + child)
+			fmt.Fprintf(bw, "    %s [label=\"+\", shape=circle, width=0.3, fixedsize=true];\n", combo)
+			fmt.Fprintf(bw, "    %s -> %s;\n", strconv.Quote(prefix+step.Parent1Name), combo)
+			fmt.Fprintf(bw, "    %s -> %s;\n", strconv.Quote(prefix+step.Parent2Name), combo)
+			fmt.Fprintf(bw, "    %s -> %s;\n", combo, strconv.Quote(prefix+child))
+		}
+		fmt.Fprintln(bw, "  }")
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
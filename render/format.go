@@ -0,0 +1,67 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// Format hasil pencarian selain JSON yang bisa ditulis WriteResults.
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	// FormatGuide adalah panduan langkah demi langkah dalam Markdown, FormatGuideText dalam teks biasa.
+	FormatGuide     = "guide"
+	FormatGuideText = "guide-text"
+)
+
+// Formats adalah semua format yang didukung WriteResults.
+var Formats = []string{FormatDOT, FormatMermaid, FormatGuide, FormatGuideText}
+
+// ContentTypes memetakan format ke Content-Type respons HTTP.
+var ContentTypes = map[string]string{
+	FormatDOT:       "text/vnd.graphviz; charset=utf-8",
+	FormatMermaid:   "text/plain; charset=utf-8",
+	FormatGuide:     "text/markdown; charset=utf-8",
+	FormatGuideText: "text/plain; charset=utf-8",
+}
+
+// Options mengatur WriteResults.
+type Options struct {
+	// Inventory berisi elemen yang sudah dimiliki pemain; dipakai format guide.
+	Inventory []string
+}
+
+// UnknownFormatError dikembalikan WriteResults untuk format yang tidak dikenal.
+type UnknownFormatError struct {
+	Format string
+}
+
+func (e *UnknownFormatError) Error() string {
+	return fmt.Sprintf("format '%s' tidak dikenal (pilihan: json, %s)", e.Format, strings.Join(Formats, ", "))
+}
+
+// WriteResults menulis semua resep target dalam satu dokumen berformat format.
+func WriteResults(w io.Writer, format, target string, results []pathfinding.Result, opts Options) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, target, results)
+	case FormatMermaid:
+		return WriteMermaid(w, target, results)
+	case FormatGuide:
+		return WriteGuide(w, target, results, opts.Inventory, true)
+	case FormatGuideText:
+		return WriteGuide(w, target, results, opts.Inventory, false)
+	}
+	return &UnknownFormatError{Format: format}
+}
+
+// recipeLabel adalah judul satu resep di dokumen multi-resep.
+func recipeLabel(index int, result pathfinding.Result) string {
+	if result.RecipeID == "" {
+		return fmt.Sprintf("Resep %d", index+1)
+	}
+	return fmt.Sprintf("Resep %d (%s)", index+1, result.RecipeID)
+}
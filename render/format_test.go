@@ -0,0 +1,127 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// wallResults adalah dua resep Wall yang hanya berbeda di cara membuat Stone.
+var wallResults = []pathfinding.Result{
+	pathfinding.NewResult([]pathfinding.PathStep{
+		{ChildName: "Pressure", Parent1Name: "Air", Parent2Name: "Air"},
+		{ChildName: "Stone", Parent1Name: "Earth", Parent2Name: "Pressure"},
+		{ChildName: "Wall", Parent1Name: "Stone", Parent2Name: "Stone"},
+	}, 0),
+	pathfinding.NewResult([]pathfinding.PathStep{
+		{ChildName: "Lava", Parent1Name: "Earth", Parent2Name: "Fire"},
+		{ChildName: "Stone", Parent1Name: "Air", Parent2Name: "Lava"},
+		{ChildName: "Wall", Parent1Name: "Stone", Parent2Name: "Stone"},
+	}, 0),
+}
+
+func TestWriteResultsOneBlockPerRecipe(t *testing.T) {
+	tests := []struct {
+		format string
+		// blockStart menandai awal blok satu resep; blocks dipotong di penanda ini.
+		blockStart string
+		// want berisi potongan teks yang harus ada di blok resep ke-i.
+		want [][]string
+	}{
+		{
+			format:     FormatDOT,
+			blockStart: "  subgraph cluster_",
+			want: [][]string{
+				{`"r0_Pressure" -> "r0_+Stone"`, `"r0_Air" -> "r0_+Pressure"`, `"r0_Stone" -> "r0_+Wall"`},
+				{`"r1_Lava" -> "r1_+Stone"`, `"r1_Fire" -> "r1_+Lava"`, `"r1_Stone" -> "r1_+Wall"`},
+			},
+		},
+		{
+			format:     FormatMermaid,
+			blockStart: "  subgraph r",
+			want: [][]string{
+				{`["Pressure"]`, `["Stone"]`, `["Wall"]`},
+				{`["Lava"]`, `["Stone"]`, `["Wall"]`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteResults(&buf, tt.format, "Wall", wallResults, Options{}); err != nil {
+				t.Fatalf("WriteResults: %v", err)
+			}
+			blocks := strings.Split(buf.String(), tt.blockStart)[1:]
+			if len(blocks) != len(wallResults) {
+				t.Fatalf("jumlah blok = %d, want %d:\n%s", len(blocks), len(wallResults), buf.String())
+			}
+			for i, block := range blocks {
+				if !strings.Contains(block, wallResults[i].RecipeID) {
+					t.Errorf("blok %d tidak berisi RecipeID %s", i, wallResults[i].RecipeID)
+				}
+				for _, want := range tt.want[i] {
+					if !strings.Contains(block, want) {
+						t.Errorf("blok %d tidak berisi %q:\n%s", i, want, block)
+					}
+				}
+				// Setiap resep punya tiga step, jadi tiga node "+".
+				combos := strings.Count(block, `[label="+"`) + strings.Count(block, "((+))")
+				if combos != 3 {
+					t.Errorf("blok %d berisi %d node +, want 3", i, combos)
+				}
+			}
+			if strings.Contains(blocks[0], "Lava") || strings.Contains(blocks[1], "Pressure") {
+				t.Errorf("step satu resep muncul di blok resep lain:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestWriteResultsRejectsConflictingSteps(t *testing.T) {
+	mixed := []pathfinding.Result{{Path: []pathfinding.PathStep{
+		{ChildName: "Pressure", Parent1Name: "Air", Parent2Name: "Air"},
+		{ChildName: "Stone", Parent1Name: "Earth", Parent2Name: "Pressure"},
+		{ChildName: "Lava", Parent1Name: "Earth", Parent2Name: "Fire"},
+		{ChildName: "Stone", Parent1Name: "Air", Parent2Name: "Lava"},
+		{ChildName: "Wall", Parent1Name: "Stone", Parent2Name: "Stone"},
+	}}}
+
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteResults(&buf, format, "Wall", mixed, Options{})
+			var conflict *ConflictingStepError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("error = %v, want ConflictingStepError", err)
+			}
+			if conflict.Element != "Stone" {
+				t.Errorf("Element = %s, want Stone", conflict.Element)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("output ditulis walaupun resep ditolak:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestNewRecipeGraphMergesIdenticalSteps(t *testing.T) {
+	steps := []pathfinding.PathStep{
+		{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Water"},
+		{ChildName: "Mud", Parent1Name: "Water", Parent2Name: "Earth"},
+		{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+	}
+	graph, err := newRecipeGraph("Brick", steps)
+	if err != nil {
+		t.Fatalf("newRecipeGraph: %v", err)
+	}
+	if len(graph.Steps) != 2 {
+		t.Errorf("jumlah step = %d, want 2", len(graph.Steps))
+	}
+	if graph.Height["Brick"] != 2 {
+		t.Errorf("Height[Brick] = %d, want 2", graph.Height["Brick"])
+	}
+}
@@ -0,0 +1,171 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// guideRound adalah sekumpulan step yang bahannya sudah tersedia sebelum round dimulai, jadi
+// boleh dikerjakan dalam urutan apa pun.
+type guideRound []pathfinding.PathStep
+
+// craftingGuide adalah satu resep yang sudah diurutkan menjadi round.
+type craftingGuide struct {
+	// Owned berisi elemen inventory yang dipakai resep ini (termasuk target jika sudah dimiliki).
+	Owned []string
+	// Starting berisi bahan awal yang tidak ada di inventory (biasanya elemen dasar).
+	Starting []string
+	Rounds   []guideRound
+}
+
+// newCraftingGuide mengurutkan step resep secara topologis: round sebuah step adalah 1 + round
+// terbesar dari step yang membuat bahannya. Elemen di inventory tidak perlu dibuat, jadi step
+// untuk elemen itu (beserta step di bawahnya yang tidak dipakai cabang lain) dilewati.
+func newCraftingGuide(graph *recipeGraph, inventory map[string]bool) *craftingGuide {
+	target := graph.Target
+	guide := &craftingGuide{}
+	round := make(map[string]int)
+	seen := make(map[string]bool)
+	visiting := make(map[string]bool)
+
+	var visit func(name string) int
+	visit = func(name string) int {
+		if seen[name] {
+			return round[name]
+		}
+		if visiting[name] {
+			return 0
+		}
+		step, crafted := graph.Steps[name]
+		switch {
+		case inventory[name]:
+			guide.Owned = append(guide.Owned, name)
+		case !crafted:
+			guide.Starting = append(guide.Starting, name)
+		default:
+			visiting[name] = true
+			round[name] = 1 + max(visit(step.Parent1Name), visit(step.Parent2Name))
+			visiting[name] = false
+			for len(guide.Rounds) < round[name] {
+				guide.Rounds = append(guide.Rounds, nil)
+			}
+			guide.Rounds[round[name]-1] = append(guide.Rounds[round[name]-1], step)
+		}
+		seen[name] = true
+		return round[name]
+	}
+	visit(target)
+
+	sort.Strings(guide.Owned)
+	sort.Strings(guide.Starting)
+	for _, steps := range guide.Rounds {
+		sort.Slice(steps, func(i, j int) bool { return steps[i].ChildName < steps[j].ChildName })
+	}
+	return guide
+}
+
+// stepCount mengembalikan jumlah kombinasi di semua round.
+func (g *craftingGuide) stepCount() int {
+	count := 0
+	for _, steps := range g.Rounds {
+		count += len(steps)
+	}
+	return count
+}
+
+// guideInstruction adalah satu baris instruksi, mis. "combine Water + Fire → Steam". Bahan
+// yang ada di inventory diberi tanda "(already owned)".
+func guideInstruction(step pathfinding.PathStep, owned map[string]bool) string {
+	ingredient := func(name string) string {
+		if owned[name] {
+			return name + " (already owned)"
+		}
+		return name
+	}
+	return fmt.Sprintf("combine %s + %s → %s", ingredient(step.Parent1Name), ingredient(step.Parent2Name), step.ChildName)
+}
+
+// WriteGuide menulis resep-resep target sebagai panduan langkah demi langkah. Step dikelompokkan
+// per round (step dalam satu round tidak saling bergantung) dan diberi nomor urut. Elemen di
+// inventory ditandai sudah dimiliki dan tidak perlu dibuat. Jika markdown false, output berupa
+// teks biasa satu instruksi per baris.
+func WriteGuide(w io.Writer, target string, results []pathfinding.Result, inventory []string, markdown bool) error {
+	graphs, err := newRecipeGraphs(target, results)
+	if err != nil {
+		return err
+	}
+	owned := make(map[string]bool, len(inventory))
+	for _, name := range inventory {
+		owned[name] = true
+	}
+
+	bw := bufio.NewWriter(w)
+	if markdown {
+		fmt.Fprintf(bw, "# How to make %s\n", target)
+	} else {
+		fmt.Fprintf(bw, "How to make %s\n", target)
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(bw)
+		fmt.Fprintln(bw, "No recipe found.")
+	}
+
+	for i, result := range results {
+		guide := newCraftingGuide(graphs[i], owned)
+		title := fmt.Sprintf("Recipe %d", i+1)
+		if result.RecipeID != "" {
+			title += " (" + result.RecipeID + ")"
+		}
+		title += ": " + plural(guide.stepCount(), "step") + " in " + plural(len(guide.Rounds), "round")
+
+		fmt.Fprintln(bw)
+		if markdown {
+			fmt.Fprintf(bw, "## %s\n\n", title)
+		} else {
+			fmt.Fprintf(bw, "%s\n%s\n", title, strings.Repeat("=", len([]rune(title))))
+		}
+		if owned[target] {
+			fmt.Fprintf(bw, "You already own %s.\n", target)
+			continue
+		}
+		listPrefix := ""
+		if markdown {
+			listPrefix = "- "
+		}
+		if len(guide.Starting) > 0 {
+			fmt.Fprintf(bw, "%sStart with: %s\n", listPrefix, strings.Join(guide.Starting, ", "))
+		}
+		if len(guide.Owned) > 0 {
+			fmt.Fprintf(bw, "%sAlready owned: %s\n", listPrefix, strings.Join(guide.Owned, ", "))
+		}
+
+		number := 0
+		for r, steps := range guide.Rounds {
+			if markdown {
+				fmt.Fprintf(bw, "\n### Round %d\n\n", r+1)
+			}
+			for _, step := range steps {
+				number++
+				if markdown {
+					fmt.Fprintf(bw, "%d. %s\n", number, guideInstruction(step, owned))
+				} else {
+					fmt.Fprintf(bw, "%d. Round %d: %s\n", number, r+1, guideInstruction(step, owned))
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// plural menulis jumlah beserta kata benda bahasa Inggris, mis. "1 step" atau "3 steps".
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+var update = flag.Bool("update", false, "tulis ulang file golden di testdata")
+
+// wallGuideSteps sengaja tidak urut: Wall butuh Brick dan Stone, yang masing-masing butuh
+// Mud dan Lava. Panduan harus mengurutkannya menjadi tiga round.
+var wallGuideSteps = []pathfinding.PathStep{
+	{ChildName: "Wall", Parent1Name: "Brick", Parent2Name: "Stone"},
+	{ChildName: "Brick", Parent1Name: "Mud", Parent2Name: "Fire"},
+	{ChildName: "Stone", Parent1Name: "Lava", Parent2Name: "Air"},
+	{ChildName: "Mud", Parent1Name: "Earth", Parent2Name: "Water"},
+	{ChildName: "Lava", Parent1Name: "Earth", Parent2Name: "Fire"},
+}
+
+func TestWriteGuideGolden(t *testing.T) {
+	tests := []struct {
+		golden    string
+		inventory []string
+		markdown  bool
+	}{
+		{golden: "guide.md", markdown: true},
+		{golden: "guide.txt", markdown: false},
+		{golden: "guide_inventory.md", inventory: []string{"Mud"}, markdown: true},
+		{golden: "guide_owned_target.txt", inventory: []string{"Wall"}, markdown: false},
+	}
+
+	results := []pathfinding.Result{pathfinding.NewResult(wallGuideSteps, 0)}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteGuide(&buf, "Wall", results, tt.inventory, tt.markdown); err != nil {
+				t.Fatalf("WriteGuide: %v", err)
+			}
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatalf("menulis golden: %v", err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("membaca golden: %v", err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("output berbeda dari %s:\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+func TestCraftingGuideRounds(t *testing.T) {
+	graph, err := newRecipeGraph("Wall", wallGuideSteps)
+	if err != nil {
+		t.Fatalf("newRecipeGraph: %v", err)
+	}
+	guide := newCraftingGuide(graph, nil)
+
+	want := [][]string{{"Lava", "Mud"}, {"Brick", "Stone"}, {"Wall"}}
+	if len(guide.Rounds) != len(want) {
+		t.Fatalf("jumlah round = %d, want %d", len(guide.Rounds), len(want))
+	}
+	// Setiap bahan harus elemen awal atau dibuat di round sebelumnya.
+	madeBefore := make(map[string]bool)
+	for _, name := range guide.Starting {
+		madeBefore[name] = true
+	}
+	for r, steps := range guide.Rounds {
+		if len(steps) != len(want[r]) {
+			t.Errorf("round %d berisi %d step, want %d", r+1, len(steps), len(want[r]))
+			continue
+		}
+		for i, step := range steps {
+			if step.ChildName != want[r][i] {
+				t.Errorf("round %d step %d = %s, want %s", r+1, i+1, step.ChildName, want[r][i])
+			}
+			for _, ingredient := range []string{step.Parent1Name, step.Parent2Name} {
+				if !madeBefore[ingredient] {
+					t.Errorf("round %d: %s dipakai sebelum dibuat", r+1, ingredient)
+				}
+			}
+		}
+		for _, step := range steps {
+			madeBefore[step.ChildName] = true
+		}
+	}
+}
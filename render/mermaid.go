@@ -0,0 +1,50 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// WriteMermaid menulis resep-resep target sebagai flowchart Mermaid (bisa langsung ditempel
+// di Markdown GitHub dalam blok ```mermaid). Setiap resep menjadi subgraph tersendiri dan
+// setiap kombinasi menjadi node "+" bulat.
+func WriteMermaid(w io.Writer, target string, results []pathfinding.Result) error {
+	graphs, err := newRecipeGraphs(target, results)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart BT")
+	for i, result := range results {
+		graph := graphs[i]
+		ids := make(map[string]string, len(graph.Height))
+
+		fmt.Fprintf(bw, "  subgraph r%d[%s]\n", i, mermaidLabel(recipeLabel(i, result)))
+		for _, row := range graph.rows() {
+			for _, name := range row {
+				ids[name] = fmt.Sprintf("r%d_e%d", i, len(ids))
+				fmt.Fprintf(bw, "    %s[%s]\n", ids[name], mermaidLabel(name))
+			}
+		}
+		for c, child := range graph.sortedChildren() {
+			step := graph.Steps[child]
+			combo := fmt.Sprintf("r%d_c%d", i, c)
+			fmt.Fprintf(bw, "    %s((+))\n", combo)
+			fmt.Fprintf(bw, "    %s --> %s\n", ids[step.Parent1Name], combo)
+			fmt.Fprintf(bw, "    %s --> %s\n", ids[step.Parent2Name], combo)
+			fmt.Fprintf(bw, "    %s --> %s\n", combo, ids[child])
+		}
+		fmt.Fprintln(bw, "  end")
+	}
+	return bw.Flush()
+}
+
+// mermaidLabel mengutip label node Mermaid; tanda kutip di-escape sebagai entity.
+func mermaidLabel(text string) string {
+	return "\"" + strings.ReplaceAll(text, "\"", "#quot;") + "\""
+}
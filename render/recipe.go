@@ -0,0 +1,175 @@
+// Package render mengubah pathfinding.Result menjadi format yang bisa dibagikan di luar
+// frontend: gambar SVG, Graphviz DOT, flowchart Mermaid, dan panduan langkah demi langkah.
+package render
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// recipeGraph adalah satu resep dalam bentuk DAG: setiap elemen muncul sekali, dan elemen
+// yang dipakai di beberapa cabang berbagi node yang sama (bentuk tree bisa ratusan node
+// untuk elemen tier tinggi).
+type recipeGraph struct {
+	Target string
+	// Steps berisi step yang benar-benar dipakai untuk membuat Target, satu per child.
+	Steps map[string]pathfinding.PathStep
+	// Height adalah panjang rantai step terpanjang di bawah elemen; 0 untuk bahan awal.
+	Height map[string]int
+	// Consumers berisi elemen yang memakai elemen ini sebagai bahan, urut nama.
+	Consumers map[string][]string
+}
+
+// ConflictingStepError dikembalikan jika sebuah resep membuat satu elemen dengan dua step
+// berbeda. Setiap elemen digambar sebagai satu node, jadi resep seperti itu tidak bisa
+// dirender tanpa menghilangkan salah satu step.
+type ConflictingStepError struct {
+	Element string
+	Steps   [2]pathfinding.PathStep
+}
+
+func (e *ConflictingStepError) Error() string {
+	return fmt.Sprintf("resep membuat '%s' dengan dua step berbeda (%s + %s dan %s + %s)", e.Element,
+		e.Steps[0].Parent1Name, e.Steps[0].Parent2Name, e.Steps[1].Parent1Name, e.Steps[1].Parent2Name)
+}
+
+// sameStep mengecek apakah dua step membuat child yang sama dari pasangan bahan yang sama,
+// tanpa memperhatikan urutan bahan.
+func sameStep(a, b pathfinding.PathStep) bool {
+	if a.ChildName != b.ChildName {
+		return false
+	}
+	return (a.Parent1Name == b.Parent1Name && a.Parent2Name == b.Parent2Name) ||
+		(a.Parent1Name == b.Parent2Name && a.Parent2Name == b.Parent1Name)
+}
+
+// newRecipeGraph membangun recipeGraph dari step sebuah Result. Step yang identik hanya dipakai
+// sekali; dua step berbeda untuk child yang sama menghasilkan ConflictingStepError. Step yang
+// tidak mengarah ke target diabaikan, dan siklus diputus sehingga elemen di dalam siklus
+// dianggap bahan awal.
+func newRecipeGraph(target string, steps []pathfinding.PathStep) (*recipeGraph, error) {
+	byChild := make(map[string]pathfinding.PathStep, len(steps))
+	for _, step := range steps {
+		existing, exists := byChild[step.ChildName]
+		if !exists {
+			byChild[step.ChildName] = step
+			continue
+		}
+		if !sameStep(existing, step) {
+			return nil, &ConflictingStepError{Element: step.ChildName, Steps: [2]pathfinding.PathStep{existing, step}}
+		}
+	}
+
+	g := &recipeGraph{
+		Target:    target,
+		Steps:     make(map[string]pathfinding.PathStep),
+		Height:    make(map[string]int),
+		Consumers: make(map[string][]string),
+	}
+	visiting := make(map[string]bool)
+	var visit func(name string) int
+	visit = func(name string) int {
+		if height, done := g.Height[name]; done {
+			return height
+		}
+		if visiting[name] {
+			return 0
+		}
+		step, ok := byChild[name]
+		if !ok {
+			g.Height[name] = 0
+			return 0
+		}
+		visiting[name] = true
+		height := 1 + max(visit(step.Parent1Name), visit(step.Parent2Name))
+		visiting[name] = false
+		g.Steps[name] = step
+		g.Height[name] = height
+		for _, parent := range uniqueParents(step) {
+			g.Consumers[parent] = append(g.Consumers[parent], name)
+		}
+		return height
+	}
+	visit(target)
+	for parent := range g.Consumers {
+		sort.Strings(g.Consumers[parent])
+	}
+	return g, nil
+}
+
+// newRecipeGraphs membangun recipeGraph untuk setiap Result. Semua graf dibangun sebelum
+// dokumen ditulis, sehingga resep yang tidak valid tidak meninggalkan output setengah jadi.
+func newRecipeGraphs(target string, results []pathfinding.Result) ([]*recipeGraph, error) {
+	graphs := make([]*recipeGraph, len(results))
+	for i, result := range results {
+		graph, err := newRecipeGraph(target, result.Path)
+		if err != nil {
+			return nil, fmt.Errorf("resep %d: %w", i+1, err)
+		}
+		graphs[i] = graph
+	}
+	return graphs, nil
+}
+
+// uniqueParents mengembalikan bahan step tanpa duplikat (resep X + X hanya punya satu bahan).
+func uniqueParents(step pathfinding.PathStep) []string {
+	if step.Parent1Name == step.Parent2Name {
+		return []string{step.Parent1Name}
+	}
+	return []string{step.Parent1Name, step.Parent2Name}
+}
+
+// rows mengelompokkan elemen per baris: baris 0 berisi Target, baris terakhir berisi bahan
+// awal terdalam. Urutan di tiap baris mengikuti rata-rata posisi elemen yang memakainya
+// (heuristik barycenter) agar garis antar baris tidak banyak bersilangan.
+func (g *recipeGraph) rows() [][]string {
+	maxHeight := g.Height[g.Target]
+	byRow := make([][]string, maxHeight+1)
+	for name, height := range g.Height {
+		row := maxHeight - height
+		byRow[row] = append(byRow[row], name)
+	}
+
+	position := make(map[string]float64, len(g.Height))
+	for row := range byRow {
+		names := byRow[row]
+		barycenter := make(map[string]float64, len(names))
+		for _, name := range names {
+			consumers := g.Consumers[name]
+			if len(consumers) == 0 {
+				continue
+			}
+			sum := 0.0
+			for _, consumer := range consumers {
+				sum += position[consumer]
+			}
+			barycenter[name] = sum / float64(len(consumers))
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if barycenter[names[i]] != barycenter[names[j]] {
+				return barycenter[names[i]] < barycenter[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		for i, name := range names {
+			// Posisi dinormalisasi ke [0, 1] supaya baris dengan jumlah elemen berbeda bisa dibandingkan.
+			position[name] = (float64(i) + 0.5) / float64(len(names))
+		}
+	}
+	return byRow
+}
+
+// sortedChildren mengembalikan elemen yang punya step, urut baris lalu posisi seperti rows.
+func (g *recipeGraph) sortedChildren() []string {
+	children := make([]string, 0, len(g.Steps))
+	for _, row := range g.rows() {
+		for _, name := range row {
+			if _, ok := g.Steps[name]; ok {
+				children = append(children, name)
+			}
+		}
+	}
+	return children
+}
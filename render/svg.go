@@ -0,0 +1,173 @@
+package render
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/pathfinding"
+)
+
+// Ukuran layout SVG dalam pixel.
+const (
+	svgNodeWidth    = 120
+	svgNodeHeight   = 78
+	svgIconSize     = 40
+	svgColumnWidth  = 140
+	svgRowHeight    = 150
+	svgMargin       = 30
+	svgHeaderHeight = 40
+	svgComboRadius  = 10
+	svgComboOffset  = 28
+)
+
+// SVGOptions mengatur WriteSVG.
+type SVGOptions struct {
+	// Icons berisi ikon elemen dengan nama file "<Nama Elemen>.svg" atau ".png" (seperti img/).
+	// Jika nil atau ikon tidak ada, node hanya berisi nama elemen.
+	Icons fs.FS
+}
+
+type svgPoint struct {
+	X, Y int
+}
+
+// WriteSVG menggambar resep target sebagai dokumen SVG mandiri: setiap elemen satu node
+// (dengan ikon ter-embed sebagai data URI), setiap kombinasi satu node "+" yang menerima dua
+// bahan dan menghasilkan child. Elemen target berada di atas, bahan awal di bawah.
+func WriteSVG(w io.Writer, target string, result pathfinding.Result, opts SVGOptions) error {
+	graph, err := newRecipeGraph(target, result.Path)
+	if err != nil {
+		return err
+	}
+	rows := graph.rows()
+
+	maxColumns := 1
+	for _, row := range rows {
+		maxColumns = max(maxColumns, len(row))
+	}
+	width := 2*svgMargin + maxColumns*svgColumnWidth
+	height := 2*svgMargin + svgHeaderHeight + (len(rows)-1)*svgRowHeight + svgNodeHeight
+
+	centers := make(map[string]svgPoint, len(graph.Height))
+	for rowIndex, row := range rows {
+		rowStart := (width - len(row)*svgColumnWidth) / 2
+		for i, name := range row {
+			centers[name] = svgPoint{
+				X: rowStart + i*svgColumnWidth + svgColumnWidth/2,
+				Y: svgMargin + svgHeaderHeight + rowIndex*svgRowHeight + svgNodeHeight/2,
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\">\n", width, height, width, height)
+	fmt.Fprintf(bw, "  <title>%s</title>\n", escapeXML("Resep "+target))
+	fmt.Fprintln(bw, `  <defs><marker id="arrow" viewBox="0 0 8 8" refX="8" refY="4" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L8,4 L0,8 z" fill="#7a7a7a"/></marker></defs>`)
+	fmt.Fprintf(bw, "  <rect width=\"%d\" height=\"%d\" fill=\"#ffffff\"/>\n", width, height)
+	fmt.Fprintf(bw, "  <text x=\"%d\" y=\"%d\" font-size=\"18\" font-weight=\"bold\" fill=\"#222222\">%s</text>\n", svgMargin, svgMargin+14, escapeXML(target))
+	subtitle := fmt.Sprintf("%d langkah", len(graph.Steps))
+	if result.RecipeID != "" {
+		subtitle = "resep " + result.RecipeID + " · " + subtitle
+	}
+	fmt.Fprintf(bw, "  <text x=\"%d\" y=\"%d\" font-size=\"12\" fill=\"#777777\">%s</text>\n", svgMargin, svgMargin+32, escapeXML(subtitle))
+
+	children := graph.sortedChildren()
+
+	// Edge digambar lebih dulu supaya berada di bawah node.
+	for _, child := range children {
+		step := graph.Steps[child]
+		childCenter := centers[child]
+		combo := svgPoint{X: childCenter.X, Y: childCenter.Y + svgNodeHeight/2 + svgComboOffset}
+		for _, parent := range uniqueParents(step) {
+			parentCenter := centers[parent]
+			fmt.Fprintf(bw, "  <line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#9a9a9a\" stroke-width=\"1.5\"/>\n",
+				parentCenter.X, parentCenter.Y-svgNodeHeight/2, combo.X, combo.Y+svgComboRadius)
+		}
+		fmt.Fprintf(bw, "  <line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#7a7a7a\" stroke-width=\"1.5\" marker-end=\"url(#arrow)\"/>\n",
+			combo.X, combo.Y-svgComboRadius, childCenter.X, childCenter.Y+svgNodeHeight/2)
+	}
+	for _, child := range children {
+		step := graph.Steps[child]
+		childCenter := centers[child]
+		label := "+"
+		if step.Parent1Name == step.Parent2Name {
+			label = "×2"
+		}
+		fmt.Fprintf(bw, "  <g><title>%s</title><circle cx=\"%d\" cy=\"%d\" r=\"%d\" fill=\"#ffffff\" stroke=\"#7a7a7a\"/><text x=\"%d\" y=\"%d\" font-size=\"12\" text-anchor=\"middle\" fill=\"#444444\">%s</text></g>\n",
+			escapeXML(step.Parent1Name+" + "+step.Parent2Name+" = "+child),
+			childCenter.X, childCenter.Y+svgNodeHeight/2+svgComboOffset, svgComboRadius,
+			childCenter.X, childCenter.Y+svgNodeHeight/2+svgComboOffset+4, label)
+	}
+
+	icons := newIconCache(opts.Icons)
+	for _, row := range rows {
+		for _, name := range row {
+			center := centers[name]
+			fill, stroke, strokeWidth := "#eef4ff", "#8aa4c8", 1
+			if _, crafted := graph.Steps[name]; !crafted {
+				fill, stroke = "#fff7e0", "#d6b35c"
+			}
+			if name == target {
+				stroke, strokeWidth = "#d9822b", 3
+			}
+			left, top := center.X-svgNodeWidth/2, center.Y-svgNodeHeight/2
+			fmt.Fprintf(bw, "  <g><title>%s</title>", escapeXML(name))
+			fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"8\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%d\"/>", left, top, svgNodeWidth, svgNodeHeight, fill, stroke, strokeWidth)
+			textY := center.Y + 5
+			if href := icons.dataURI(name); href != "" {
+				fmt.Fprintf(bw, "<image x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" xlink:href=\"%s\"/>", center.X-svgIconSize/2, top+8, svgIconSize, svgIconSize, href)
+				textY = top + 8 + svgIconSize + 16
+			}
+			fontSize := 12
+			if len(name) > 16 {
+				fontSize = 10
+			}
+			fmt.Fprintf(bw, "<text x=\"%d\" y=\"%d\" font-size=\"%d\" text-anchor=\"middle\" fill=\"#222222\">%s</text></g>\n", center.X, textY, fontSize, escapeXML(name))
+		}
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// iconCache membaca ikon elemen sekali per WriteSVG.
+type iconCache struct {
+	icons fs.FS
+	uris  map[string]string
+}
+
+func newIconCache(icons fs.FS) *iconCache {
+	return &iconCache{icons: icons, uris: make(map[string]string)}
+}
+
+// dataURI mengembalikan ikon elemen sebagai data URI base64, atau "" jika tidak ada.
+func (c *iconCache) dataURI(name string) string {
+	if c.icons == nil {
+		return ""
+	}
+	if uri, cached := c.uris[name]; cached {
+		return uri
+	}
+	uri := ""
+	for _, candidate := range [][2]string{{".svg", "image/svg+xml"}, {".png", "image/png"}} {
+		data, err := fs.ReadFile(c.icons, name+candidate[0])
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		uri = "data:" + candidate[1] + ";base64," + base64.StdEncoding.EncodeToString(data)
+		break
+	}
+	c.uris[name] = uri
+	return uri
+}
+
+// escapeXML meng-escape teks untuk dipakai di isi elemen atau nilai atribut XML.
+func escapeXML(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package scrape
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// DefaultAuditPath adalah file audit filter default yang ditulis bersama output scraping.
+const DefaultAuditPath = "filter_audit.json"
+
+// Aturan filter yang dicatat di FilterAudit.
+const (
+	// RuleMalformedRecipe: resep bukan pasangan dua parent.
+	RuleMalformedRecipe = "malformed_recipe"
+	// RuleUnknownParent: parent tidak pernah ditemukan saat scraping (tidak punya tier).
+	RuleUnknownParent = "unknown_parent"
+	// RuleTier: tier salah satu parent tidak lebih kecil dari tier child.
+	RuleTier = "tier"
+	// RuleInvalidParent: parent sudah dihapus di iterasi sebelumnya.
+	RuleInvalidParent = "invalid_parent"
+	// RuleNoRecipesScraped: elemen non-dasar yang sejak parsing tidak punya resep.
+	RuleNoRecipesScraped = "no_recipes_scraped"
+	// RuleNoRecipesLeft: elemen non-dasar yang semua resepnya dihapus filter.
+	RuleNoRecipesLeft = "no_recipes_left"
+)
+
+// RemovedRecipe adalah satu resep yang dihapus filter. Iteration 0 adalah filter tier
+// tahap awal; iterasi 1, 2, ... adalah loop filter parent/elemen.
+type RemovedRecipe struct {
+	Element   string   `json:"element"`
+	Recipe    []string `json:"recipe"`
+	Rule      string   `json:"rule"`
+	Iteration int      `json:"iteration"`
+	Detail    string   `json:"detail,omitempty"`
+}
+
+// RemovedElement adalah satu elemen yang dihapus karena tidak punya resep tersisa.
+type RemovedElement struct {
+	Element   string `json:"element"`
+	Tier      int    `json:"tier"`
+	Rule      string `json:"rule"`
+	Iteration int    `json:"iteration"`
+}
+
+// FilterAudit mencatat semua yang dihapus FilterElementsWithAudit, sehingga resep yang
+// "hilang" bisa dibedakan antara sengaja difilter atau memang tidak ter-parse.
+type FilterAudit struct {
+	Iterations      int              `json:"iterations"`
+	RemovedRecipes  []RemovedRecipe  `json:"removedRecipes"`
+	RemovedElements []RemovedElement `json:"removedElements"`
+}
+
+func newFilterAudit() *FilterAudit {
+	return &FilterAudit{RemovedRecipes: []RemovedRecipe{}, RemovedElements: []RemovedElement{}}
+}
+
+// sort mengurutkan entri audit berdasarkan iterasi lalu nama elemen agar output deterministik.
+func (a *FilterAudit) sort() {
+	sort.Slice(a.RemovedRecipes, func(i, j int) bool {
+		ri, rj := a.RemovedRecipes[i], a.RemovedRecipes[j]
+		if ri.Iteration != rj.Iteration {
+			return ri.Iteration < rj.Iteration
+		}
+		if ri.Element != rj.Element {
+			return ri.Element < rj.Element
+		}
+		return recipeKey(ri.Recipe) < recipeKey(rj.Recipe)
+	})
+	sort.Slice(a.RemovedElements, func(i, j int) bool {
+		ei, ej := a.RemovedElements[i], a.RemovedElements[j]
+		if ei.Iteration != ej.Iteration {
+			return ei.Iteration < ej.Iteration
+		}
+		return ei.Element < ej.Element
+	})
+}
+
+// ForElement mengembalikan resep elemen name yang dihapus, beserta entri elemennya
+// (nil jika elemen tidak dihapus).
+func (a *FilterAudit) ForElement(name string) ([]RemovedRecipe, *RemovedElement) {
+	recipes := []RemovedRecipe{}
+	for _, removed := range a.RemovedRecipes {
+		if removed.Element == name {
+			recipes = append(recipes, removed)
+		}
+	}
+	for i := range a.RemovedElements {
+		if a.RemovedElements[i].Element == name {
+			return recipes, &a.RemovedElements[i]
+		}
+	}
+	return recipes, nil
+}
+
+// WriteJSON menulis audit sebagai JSON ter-indentasi.
+func (a *FilterAudit) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(a)
+}
@@ -0,0 +1,190 @@
+package scrape
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RecipeChange berisi resep yang ditambah/dihapus untuk satu elemen yang ada di kedua dataset.
+type RecipeChange struct {
+	Element string     `json:"element"`
+	Added   [][]string `json:"added,omitempty"`
+	Removed [][]string `json:"removed,omitempty"`
+}
+
+type TierChange struct {
+	Element string `json:"element"`
+	OldTier int    `json:"oldTier"`
+	NewTier int    `json:"newTier"`
+}
+
+type ImageChange struct {
+	Element string `json:"element"`
+	OldURL  string `json:"oldUrl"`
+	NewURL  string `json:"newUrl"`
+}
+
+// Changelog adalah perbedaan antara dua dataset hasil scraping.
+type Changelog struct {
+	AddedElements   []string       `json:"addedElements"`
+	RemovedElements []string       `json:"removedElements"`
+	RecipeChanges   []RecipeChange `json:"recipeChanges"`
+	TierChanges     []TierChange   `json:"tierChanges"`
+	ImageChanges    []ImageChange  `json:"imageChanges"`
+}
+
+// ReadElementsFile membaca dataset JSON (format output scraper).
+func ReadElementsFile(path string) ([]Element, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var elements []Element
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, fmt.Errorf("gagal parse '%s': %w", path, err)
+	}
+	return elements, nil
+}
+
+// recipeKey membuat kunci resep yang tidak bergantung urutan parent.
+func recipeKey(recipe []string) string {
+	sorted := append([]string{}, recipe...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x1f")
+}
+
+// recipeSet mengubah daftar resep menjadi map kunci -> resep (urutan parent dinormalisasi).
+func recipeSet(recipes [][]string) map[string][]string {
+	set := make(map[string][]string, len(recipes))
+	for _, recipe := range recipes {
+		normalized := append([]string{}, recipe...)
+		sort.Strings(normalized)
+		set[recipeKey(recipe)] = normalized
+	}
+	return set
+}
+
+// sortedRecipes mengembalikan nilai map resep dalam urutan kunci.
+func sortedRecipes(set map[string][]string, keys []string) [][]string {
+	sort.Strings(keys)
+	recipes := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		recipes = append(recipes, set[key])
+	}
+	return recipes
+}
+
+// DiffElements membandingkan dataset lama dan baru. Semua daftar diurutkan berdasarkan nama.
+func DiffElements(oldElements, newElements []Element) *Changelog {
+	changelog := &Changelog{
+		AddedElements:   []string{},
+		RemovedElements: []string{},
+		RecipeChanges:   []RecipeChange{},
+		TierChanges:     []TierChange{},
+		ImageChanges:    []ImageChange{},
+	}
+
+	oldByName := make(map[string]Element, len(oldElements))
+	for _, elem := range oldElements {
+		oldByName[elem.Name] = elem
+	}
+	newByName := make(map[string]Element, len(newElements))
+	for _, elem := range newElements {
+		newByName[elem.Name] = elem
+	}
+
+	for name := range newByName {
+		if _, exists := oldByName[name]; !exists {
+			changelog.AddedElements = append(changelog.AddedElements, name)
+		}
+	}
+	for name, oldElem := range oldByName {
+		newElem, exists := newByName[name]
+		if !exists {
+			changelog.RemovedElements = append(changelog.RemovedElements, name)
+			continue
+		}
+
+		oldRecipes, newRecipes := recipeSet(oldElem.Recipes), recipeSet(newElem.Recipes)
+		var addedKeys, removedKeys []string
+		for key := range newRecipes {
+			if _, exists := oldRecipes[key]; !exists {
+				addedKeys = append(addedKeys, key)
+			}
+		}
+		for key := range oldRecipes {
+			if _, exists := newRecipes[key]; !exists {
+				removedKeys = append(removedKeys, key)
+			}
+		}
+		if len(addedKeys) > 0 || len(removedKeys) > 0 {
+			changelog.RecipeChanges = append(changelog.RecipeChanges, RecipeChange{
+				Element: name,
+				Added:   sortedRecipes(newRecipes, addedKeys),
+				Removed: sortedRecipes(oldRecipes, removedKeys),
+			})
+		}
+
+		if oldElem.Tier != newElem.Tier {
+			changelog.TierChanges = append(changelog.TierChanges, TierChange{Element: name, OldTier: oldElem.Tier, NewTier: newElem.Tier})
+		}
+		if oldElem.ImageURL != newElem.ImageURL {
+			changelog.ImageChanges = append(changelog.ImageChanges, ImageChange{Element: name, OldURL: oldElem.ImageURL, NewURL: newElem.ImageURL})
+		}
+	}
+
+	sort.Strings(changelog.AddedElements)
+	sort.Strings(changelog.RemovedElements)
+	sort.Slice(changelog.RecipeChanges, func(i, j int) bool { return changelog.RecipeChanges[i].Element < changelog.RecipeChanges[j].Element })
+	sort.Slice(changelog.TierChanges, func(i, j int) bool { return changelog.TierChanges[i].Element < changelog.TierChanges[j].Element })
+	sort.Slice(changelog.ImageChanges, func(i, j int) bool { return changelog.ImageChanges[i].Element < changelog.ImageChanges[j].Element })
+	return changelog
+}
+
+// IsEmpty mengembalikan true jika kedua dataset identik.
+func (c *Changelog) IsEmpty() bool {
+	return len(c.AddedElements) == 0 && len(c.RemovedElements) == 0 && len(c.RecipeChanges) == 0 &&
+		len(c.TierChanges) == 0 && len(c.ImageChanges) == 0
+}
+
+// WriteText menulis changelog dalam bentuk teks yang mudah dibaca.
+func (c *Changelog) WriteText(w io.Writer) error {
+	if c.IsEmpty() {
+		_, err := fmt.Fprintln(w, "Tidak ada perubahan.")
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Elemen ditambah (%d):\n", len(c.AddedElements))
+	for _, name := range c.AddedElements {
+		fmt.Fprintf(&b, "  + %s\n", name)
+	}
+	fmt.Fprintf(&b, "Elemen dihapus (%d):\n", len(c.RemovedElements))
+	for _, name := range c.RemovedElements {
+		fmt.Fprintf(&b, "  - %s\n", name)
+	}
+	fmt.Fprintf(&b, "Perubahan resep (%d elemen):\n", len(c.RecipeChanges))
+	for _, change := range c.RecipeChanges {
+		fmt.Fprintf(&b, "  %s\n", change.Element)
+		for _, recipe := range change.Added {
+			fmt.Fprintf(&b, "    + %s\n", strings.Join(recipe, " + "))
+		}
+		for _, recipe := range change.Removed {
+			fmt.Fprintf(&b, "    - %s\n", strings.Join(recipe, " + "))
+		}
+	}
+	fmt.Fprintf(&b, "Perubahan tier (%d):\n", len(c.TierChanges))
+	for _, change := range c.TierChanges {
+		fmt.Fprintf(&b, "  %s: %d -> %d\n", change.Element, change.OldTier, change.NewTier)
+	}
+	fmt.Fprintf(&b, "Perubahan URL gambar (%d):\n", len(c.ImageChanges))
+	for _, change := range c.ImageChanges {
+		fmt.Fprintf(&b, "  %s:\n    - %s\n    + %s\n", change.Element, change.OldURL, change.NewURL)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,170 @@
+package scrape
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// changelogBase adalah snapshot lama yang dipakai semua kasus TestDiffElements.
+var changelogBase = []Element{
+	{Name: "Mud", Tier: 1, Recipes: [][]string{{"Earth", "Water"}}, ImageURL: "mud.svg"},
+	{Name: "Brick", Tier: 2, Recipes: [][]string{{"Mud", "Fire"}, {"Mud", "Air"}}},
+	{Name: "Steam", Tier: 1, Recipes: [][]string{{"Fire", "Water"}}},
+}
+
+// withElement mengembalikan salinan changelogBase dengan elemen name diganti oleh change
+// (nil berarti elemen dihapus).
+func withElement(name string, change *Element) []Element {
+	var elements []Element
+	for _, element := range changelogBase {
+		if element.Name != name {
+			elements = append(elements, element)
+		} else if change != nil {
+			elements = append(elements, *change)
+		}
+	}
+	return elements
+}
+
+func TestDiffElements(t *testing.T) {
+	tests := []struct {
+		name string
+		new  []Element
+		want Changelog
+	}{
+		{
+			name: "sama",
+			new:  changelogBase,
+		},
+		{
+			name: "urutan parent dan elemen berbeda",
+			new: []Element{
+				{Name: "Steam", Tier: 1, Recipes: [][]string{{"Water", "Fire"}}},
+				{Name: "Brick", Tier: 2, Recipes: [][]string{{"Air", "Mud"}, {"Fire", "Mud"}}},
+				{Name: "Mud", Tier: 1, Recipes: [][]string{{"Water", "Earth"}}, ImageURL: "mud.svg"},
+			},
+		},
+		{
+			name: "elemen ditambah",
+			new:  append(withElement("", nil), Element{Name: "Wall", Tier: 3, Recipes: [][]string{{"Brick", "Brick"}}}),
+			want: Changelog{AddedElements: []string{"Wall"}},
+		},
+		{
+			name: "elemen dihapus",
+			new:  withElement("Steam", nil),
+			want: Changelog{RemovedElements: []string{"Steam"}},
+		},
+		{
+			name: "resep ditambah",
+			new:  withElement("Mud", &Element{Name: "Mud", Tier: 1, Recipes: [][]string{{"Earth", "Water"}, {"Water", "Soil"}}, ImageURL: "mud.svg"}),
+			want: Changelog{RecipeChanges: []RecipeChange{{Element: "Mud", Added: [][]string{{"Soil", "Water"}}, Removed: [][]string{}}}},
+		},
+		{
+			name: "resep dihapus",
+			new:  withElement("Brick", &Element{Name: "Brick", Tier: 2, Recipes: [][]string{{"Fire", "Mud"}}}),
+			want: Changelog{RecipeChanges: []RecipeChange{{Element: "Brick", Added: [][]string{}, Removed: [][]string{{"Air", "Mud"}}}}},
+		},
+		{
+			name: "resep diganti",
+			new:  withElement("Steam", &Element{Name: "Steam", Tier: 1, Recipes: [][]string{{"Air", "Water"}}}),
+			want: Changelog{RecipeChanges: []RecipeChange{{Element: "Steam", Added: [][]string{{"Air", "Water"}}, Removed: [][]string{{"Fire", "Water"}}}}},
+		},
+		{
+			name: "tier dan gambar berubah",
+			new:  withElement("Mud", &Element{Name: "Mud", Tier: 2, Recipes: [][]string{{"Earth", "Water"}}, ImageURL: "mud-v2.svg"}),
+			want: Changelog{
+				TierChanges:  []TierChange{{Element: "Mud", OldTier: 1, NewTier: 2}},
+				ImageChanges: []ImageChange{{Element: "Mud", OldURL: "mud.svg", NewURL: "mud-v2.svg"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffElements(changelogBase, tt.new)
+			want := emptyChangelog()
+			if tt.want.AddedElements != nil {
+				want.AddedElements = tt.want.AddedElements
+			}
+			if tt.want.RemovedElements != nil {
+				want.RemovedElements = tt.want.RemovedElements
+			}
+			if tt.want.RecipeChanges != nil {
+				want.RecipeChanges = tt.want.RecipeChanges
+			}
+			if tt.want.TierChanges != nil {
+				want.TierChanges = tt.want.TierChanges
+			}
+			if tt.want.ImageChanges != nil {
+				want.ImageChanges = tt.want.ImageChanges
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("DiffElements = %+v, want %+v", *got, want)
+			}
+			if got.IsEmpty() != reflect.DeepEqual(want, emptyChangelog()) {
+				t.Errorf("IsEmpty() = %v", got.IsEmpty())
+			}
+		})
+	}
+}
+
+// emptyChangelog adalah Changelog tanpa perubahan seperti yang dibuat DiffElements.
+func emptyChangelog() Changelog {
+	return Changelog{
+		AddedElements:   []string{},
+		RemovedElements: []string{},
+		RecipeChanges:   []RecipeChange{},
+		TierChanges:     []TierChange{},
+		ImageChanges:    []ImageChange{},
+	}
+}
+
+func TestChangelogWriteText(t *testing.T) {
+	tests := []struct {
+		name string
+		new  []Element
+		want string
+	}{
+		{
+			name: "sama",
+			new:  changelogBase,
+			want: "Tidak ada perubahan.\n",
+		},
+		{
+			name: "berubah",
+			new: []Element{
+				{Name: "Mud", Tier: 2, Recipes: [][]string{{"Earth", "Water"}, {"Soil", "Water"}}, ImageURL: "mud-v2.svg"},
+				{Name: "Brick", Tier: 2, Recipes: [][]string{{"Mud", "Fire"}}},
+				{Name: "Wall", Tier: 3, Recipes: [][]string{{"Brick", "Brick"}}},
+			},
+			want: `Elemen ditambah (1):
+  + Wall
+Elemen dihapus (1):
+  - Steam
+Perubahan resep (2 elemen):
+  Brick
+    - Air + Mud
+  Mud
+    + Soil + Water
+Perubahan tier (1):
+  Mud: 1 -> 2
+Perubahan URL gambar (1):
+  Mud:
+    - mud.svg
+    + mud-v2.svg
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := DiffElements(changelogBase, tt.new).WriteText(&b); err != nil {
+				t.Fatalf("WriteText: %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("WriteText =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
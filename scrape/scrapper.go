@@ -9,11 +9,14 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/andybalholm/brotli"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
 )
 
 type Element struct {
@@ -21,181 +24,466 @@ type Element struct {
 	Recipes  [][]string `json:"recipes"`
 	Tier     int        `json:"tier"`
 	ImageURL string     `json:"imageUrl,omitempty"` 
+	// Pack adalah expansion pack elemen; kosong berarti base game.
+	Pack string `json:"pack,omitempty"`
+}
+
+// KnownPacks adalah nama expansion pack yang dikenali parser, dicocokkan dengan
+// judul link/ikon di baris elemen atau judul section di atas tabel.
+var KnownPacks = []string{"Myths and Monsters"}
+
+// detectPack mengembalikan pack pertama di KnownPacks yang disebut di text, atau "".
+func detectPack(text string) string {
+	lower := strings.ToLower(text)
+	for _, pack := range KnownPacks {
+		if strings.Contains(lower, strings.ToLower(pack)) {
+			return pack
+		}
+	}
+	return ""
 }
 
-// Helper function untuk memfilter resep berdasarkan keberadaan parent di validElements
-func filterRecipesWithValidParents(recipes [][]string, validElements map[string]bool) [][]string {
+// Helper function untuk memfilter resep berdasarkan keberadaan parent di validElements.
+// Resep yang dihapus dikembalikan sebagai entri audit untuk iterasi yang diberikan.
+func filterRecipesWithValidParents(recipes [][]string, childName string, validElements map[string]bool, iteration int) ([][]string, []RemovedRecipe) {
 	newRecipes := make([][]string, 0, len(recipes))
+	var removed []RemovedRecipe
 	for _, recipe := range recipes {
-		if len(recipe) == 2 {
-			parent1 := recipe[0]
-			parent2 := recipe[1]
-			if validElements[parent1] && validElements[parent2] {
-				newRecipes = append(newRecipes, recipe)
-			}
+		if len(recipe) != 2 {
+			removed = append(removed, RemovedRecipe{Element: childName, Recipe: recipe, Rule: RuleMalformedRecipe, Iteration: iteration})
+			continue
+		}
+		parent1 := recipe[0]
+		parent2 := recipe[1]
+		if validElements[parent1] && validElements[parent2] {
+			newRecipes = append(newRecipes, recipe)
+			continue
 		}
+		invalidParent := parent1
+		if validElements[parent1] {
+			invalidParent = parent2
+		}
+		removed = append(removed, RemovedRecipe{
+			Element: childName, Recipe: recipe, Rule: RuleInvalidParent, Iteration: iteration,
+			Detail: fmt.Sprintf("parent '%s' sudah dihapus", invalidParent),
+		})
 	}
-	return newRecipes
+	return newRecipes, removed
 }
 
-// Helper function untuk memfilter resep berdasarkan aturan tier
-func filterRecipesByTier(recipes [][]string, childName string, elementTiers map[string]int) [][]string {
+// Helper function untuk memfilter resep berdasarkan aturan tier.
+// Resep yang dihapus dikembalikan sebagai entri audit dengan iterasi 0.
+func filterRecipesByTier(recipes [][]string, childName string, elementTiers map[string]int) ([][]string, []RemovedRecipe) {
+	var removed []RemovedRecipe
 	childTier, ok := elementTiers[childName]
-	if !ok { 
-		return [][]string{}
+	if !ok {
+		for _, recipe := range recipes {
+			removed = append(removed, RemovedRecipe{
+				Element: childName, Recipe: recipe, Rule: RuleTier,
+				Detail: fmt.Sprintf("tier elemen '%s' tidak diketahui", childName),
+			})
+		}
+		return [][]string{}, removed
 	}
 
 	newRecipes := make([][]string, 0, len(recipes))
 	for _, recipe := range recipes {
-		if len(recipe) == 2 {
-			parent1 := recipe[0]
-			parent2 := recipe[1]
-			parent1Tier, p1Ok := elementTiers[parent1]
-			parent2Tier, p2Ok := elementTiers[parent2]
-
-			if p1Ok && p2Ok && parent1Tier < childTier && parent2Tier < childTier {
-				newRecipes = append(newRecipes, recipe)
+		if len(recipe) != 2 {
+			removed = append(removed, RemovedRecipe{Element: childName, Recipe: recipe, Rule: RuleMalformedRecipe})
+			continue
+		}
+		parent1 := recipe[0]
+		parent2 := recipe[1]
+		parent1Tier, p1Ok := elementTiers[parent1]
+		parent2Tier, p2Ok := elementTiers[parent2]
+
+		switch {
+		case !p1Ok || !p2Ok:
+			unknownParent := parent1
+			if p1Ok {
+				unknownParent = parent2
 			}
+			removed = append(removed, RemovedRecipe{
+				Element: childName, Recipe: recipe, Rule: RuleUnknownParent,
+				Detail: fmt.Sprintf("parent '%s' tidak ditemukan saat scraping", unknownParent),
+			})
+		case parent1Tier < childTier && parent2Tier < childTier:
+			newRecipes = append(newRecipes, recipe)
+		default:
+			removed = append(removed, RemovedRecipe{
+				Element: childName, Recipe: recipe, Rule: RuleTier,
+				Detail: fmt.Sprintf("%s (tier %d) + %s (tier %d) -> %s (tier %d)",
+					parent1, parent1Tier, parent2, parent2Tier, childName, childTier),
+			})
 		}
 	}
-	return newRecipes
+	return newRecipes, removed
 }
 
 
-func Scrapping() {
-	url := "https://little-alchemy.fandom.com/wiki/Elements_(Little_Alchemy_2)"
-	fmt.Println("Memulai scraping dari:", url)
+// DefaultSourceURL adalah halaman wiki Little Alchemy 2 yang di-scrape secara default.
+const DefaultSourceURL = "https://little-alchemy.fandom.com/wiki/Elements_(Little_Alchemy_2)"
+
+// DefaultOutputPath adalah file output default hasil scraping.
+const DefaultOutputPath = "elements_with_images.json"
+
+// Layout tabel wiki yang didukung parser.
+const (
+	// LayoutLA2: satu tabel per tier, nama + ikon di kolom 1, resep (li) di kolom 2.
+	LayoutLA2 = "la2"
+	// LayoutLA1: tabel tidak dikelompokkan per tier; tier dihitung dari kedalaman resep.
+	LayoutLA1 = "la1"
+)
+
+// DefaultBaseElements adalah elemen dasar Little Alchemy 1 dan 2.
+var DefaultBaseElements = []string{"Air", "Earth", "Fire", "Water"}
 
+// Options mengatur sumber dan tujuan proses scraping.
+// Jika InputFile diisi, HTML dibaca dari file lokal dan SourceURL diabaikan.
+// Output selalu hasil scraping yang hanya di-merge; filter resep diterapkan FilterPolicy saat
+// dataset dimuat. Jika AuditPath diisi, loadrecipes.FilterReport (resep yang akan dibuang loader
+// dengan AuditPolicy) ditulis ke file tersebut sebagai laporan.
+type Options struct {
+	SourceURL  string
+	InputFile  string
+	OutputPath string
+	AuditPath  string
+	// AuditPolicy adalah FilterPolicy untuk audit dalam format ParseFilterPolicy; kosong
+	// berarti loadrecipes.DefaultFilterPolicy.
+	AuditPolicy  string
+	Pretty       bool
+	Layout       string
+	BaseElements []string
+}
+
+// DefaultOptions mengembalikan Options yang sama dengan perilaku Scrapping() sebelumnya.
+func DefaultOptions() Options {
+	return Options{SourceURL: DefaultSourceURL, OutputPath: DefaultOutputPath, AuditPath: DefaultAuditPath, Pretty: true, Layout: LayoutLA2, BaseElements: DefaultBaseElements}
+}
+
+// FetchHTML mengunduh halaman dari url dan mengembalikan body yang sudah didekompresi.
+func FetchHTML(url string) ([]byte, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("GET", url, nil); if err != nil { log.Fatalf("FATAL: Error request: %s", err) }
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error request: %w", err)
+	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36")
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.9")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.9,id;q=0.8"); req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-	req.Header.Set("DNT", "1"); req.Header.Set("Upgrade-Insecure-Requests", "1")
-	res, err := client.Do(req); if err != nil { log.Fatalf("FATAL: Error GET: %s", err) }; defer res.Body.Close()
-	if res.StatusCode != 200 { log.Printf("WARNING: Status %d", res.StatusCode) }
-	var reader io.ReadCloser = res.Body; encoding := res.Header.Get("Content-Encoding")
-	switch strings.ToLower(encoding) {
-	case "br": brotliReader := brotli.NewReader(res.Body); reader = io.NopCloser(brotliReader)
-	case "gzip": gzipReader, err := gzip.NewReader(res.Body); if err != nil { log.Fatalf("FATAL: gzip reader: %s", err) }; reader = gzipReader
-	}; defer reader.Close()
-	bodyBytes, err := io.ReadAll(reader); if err != nil { log.Fatalf("FATAL: read body: %s", err) }
-	fmt.Printf("Body response berhasil dibaca (%d bytes).\n", len(bodyBytes))
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9,id;q=0.8")
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	req.Header.Set("DNT", "1")
+	req.Header.Set("Upgrade-Insecure-Requests", "1")
 
-	fmt.Println("Memulai parsing HTML dengan goquery...")
-	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bodyBytes))
-	if err != nil { log.Fatalf("FATAL: Error parsing HTML: %s", err) }
-	fmt.Println("Parsing HTML selesai.")
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error GET: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		log.Printf("WARNING: Status %d", res.StatusCode)
+	}
 
-	var initialScrapedElements []Element
-	baseElements := map[string]bool{"Air": true, "Earth": true, "Fire": true, "Water": true}
+	var reader io.ReadCloser = res.Body
+	switch strings.ToLower(res.Header.Get("Content-Encoding")) {
+	case "br":
+		reader = io.NopCloser(brotli.NewReader(res.Body))
+	case "gzip":
+		gzipReader, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("gzip reader: %w", err)
+		}
+		reader = gzipReader
+	}
+	defer reader.Close()
 
-	fmt.Println("Mencari dan memproses tabel elemen (scrape awal)...")
+	bodyBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return bodyBytes, nil
+}
+
+// ParseElements mem-parse HTML halaman elemen dan mengembalikan entri elemen mentah
+// (belum di-merge dan belum difilter). Tier diambil dari urutan tabel.
+func ParseElements(r io.Reader) ([]Element, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	var initialScrapedElements []Element
 	tables := doc.Find("table.list-table")
 	tables.Each(func(tableIndex int, table *goquery.Selection) {
 		currentTier := tableIndex + 1
-		tbodies := table.Find("tbody")
-		tbodies.Each(func(tbodyIndex int, tbody *goquery.Selection) {
-			rows := tbody.Find("tr")
-			rows.Each(func(rowIndex int, row *goquery.Selection) {
-				td1 := row.Find("td:nth-child(1)")
-
-				elementLink := td1.Find("a[title]").First() // Link yang berisi nama elemen
-				elementName, nameExists := elementLink.Attr("title")
-				elementName = strings.TrimSpace(elementName)
-
-				if nameExists && elementName != "" {
-					var imageURL string
-					imageTag := td1.Find("span.icon-hover a.image img.mw-file-element").First()
-					if imageTag.Length() > 0 {
-						src, srcExists := imageTag.Attr("data-src")
-						if srcExists && src != "" {
-							imageURL = src
-						} else {
-							src, srcExists = imageTag.Attr("src")
-							if srcExists {
-								imageURL = src
-							}
-						}
-						if strings.Contains(imageURL, "/scale-to-width-down/") {
-							parts := strings.Split(imageURL, "/scale-to-width-down/")
-							if len(parts) > 0 {
-								baseImageURL := parts[0]
-								if strings.Contains(parts[1], "?") {
-									queryParams := parts[1][strings.Index(parts[1], "?"):]
-									imageURL = baseImageURL + queryParams
-								} else {
-									imageURL = baseImageURL
-								}
-							}
-						}
+		sectionPack := detectPack(table.PrevAllFiltered("h2, h3").First().Text())
+		table.Find("tbody").Each(func(tbodyIndex int, tbody *goquery.Selection) {
+			tbody.Find("tr").Each(func(rowIndex int, row *goquery.Selection) {
+				if element, ok := parseElementRow(row, currentTier); ok {
+					if element.Pack == "" {
+						element.Pack = sectionPack
 					}
+					initialScrapedElements = append(initialScrapedElements, element)
+				}
+			})
+		})
+	})
 
-					currentElement := Element{
-						Name:     elementName,
-						Recipes:  [][]string{},
-						Tier:     currentTier,
-						ImageURL: imageURL, 
-					}
+	if tables.Length() == 0 {
+		return nil, fmt.Errorf("tidak ada tabel elemen (table.list-table) di HTML")
+	}
+	return initialScrapedElements, nil
+}
+
+// ParseElementsLayout mem-parse HTML sesuai layout tabel wiki (LayoutLA2 atau LayoutLA1).
+func ParseElementsLayout(r io.Reader, layout string, baseElements []string) ([]Element, error) {
+	switch layout {
+	case "", LayoutLA2:
+		return ParseElements(r)
+	case LayoutLA1:
+		return parseElementsLA1(r, baseElements)
+	default:
+		return nil, fmt.Errorf("layout '%s' tidak dikenal", layout)
+	}
+}
+
+// parseElementsLA1 mem-parse halaman elemen Little Alchemy 1. Halaman ini tidak
+// mengelompokkan tabel per tier, jadi tier diisi dari kedalaman resep (AssignTiersByDepth).
+// Resep dibaca dari li jika ada; jika tidak, link di kolom resep dipasangkan berurutan.
+func parseElementsLA1(r io.Reader, baseElements []string) ([]Element, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
 
-					recipeCell := row.Find("td:nth-child(2)")
-					if recipeCell.Length() > 0 {
-						lis := recipeCell.Find("li")
-						lis.Each(func(liIndex int, li *goquery.Selection) {
-							recipeParentLinks := li.Find("a[title]")
-							var singleRecipePair []string
-							recipeParentLinks.Each(func(j int, link *goquery.Selection) {
-								parentName, _ := link.Attr("title")
-								parentName = strings.TrimSpace(parentName)
-								if parentName != "" {
-									singleRecipePair = append(singleRecipePair, parentName)
-								}
-							})
-							if len(singleRecipePair) == 2 && singleRecipePair[0] != "" && singleRecipePair[1] != "" {
-								currentElement.Recipes = append(currentElement.Recipes, singleRecipePair)
-							}
-						})
+	var initialScrapedElements []Element
+	tables := doc.Find("table.list-table, table.wikitable")
+	tables.Each(func(tableIndex int, table *goquery.Selection) {
+		table.Find("tr").Each(func(rowIndex int, row *goquery.Selection) {
+			element, ok := parseElementRow(row, 0)
+			if !ok {
+				return
+			}
+			recipeCell := row.Find("td:nth-child(2)")
+			if len(element.Recipes) == 0 && recipeCell.Find("li").Length() == 0 {
+				var names []string
+				recipeCell.Find("a[title]").Each(func(j int, link *goquery.Selection) {
+					if title := strings.TrimSpace(link.AttrOr("title", "")); title != "" {
+						names = append(names, title)
 					}
-					initialScrapedElements = append(initialScrapedElements, currentElement)
+				})
+				for i := 0; i+1 < len(names); i += 2 {
+					element.Recipes = append(element.Recipes, []string{names[i], names[i+1]})
 				}
-			})
+			}
+			initialScrapedElements = append(initialScrapedElements, element)
 		})
 	})
-	fmt.Printf("Scraping awal selesai. Ditemukan %d entri elemen.\n", len(initialScrapedElements))
 
-	log.Println("Memproses elemen unik dan membuat map tier...")
+	if tables.Length() == 0 {
+		return nil, fmt.Errorf("tidak ada tabel elemen (table.list-table / table.wikitable) di HTML")
+	}
+	AssignTiersByDepth(initialScrapedElements, baseElements)
+	return initialScrapedElements, nil
+}
+
+// AssignTiersByDepth mengisi Tier tiap elemen dengan kedalaman resep minimal + 1
+// (elemen dasar = tier 1). Elemen yang tidak bisa dicapai dari elemen dasar mendapat tier 0.
+func AssignTiersByDepth(elements []Element, baseElements []string) {
+	depth := make(map[string]int)
+	for _, base := range baseElements {
+		depth[base] = 0
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, elem := range elements {
+			for _, recipe := range elem.Recipes {
+				if len(recipe) != 2 {
+					continue
+				}
+				d1, ok1 := depth[recipe[0]]
+				d2, ok2 := depth[recipe[1]]
+				if !ok1 || !ok2 {
+					continue
+				}
+				candidate := 1 + max(d1, d2)
+				if current, ok := depth[elem.Name]; !ok || candidate < current {
+					depth[elem.Name] = candidate
+					changed = true
+				}
+			}
+		}
+	}
+	for i := range elements {
+		if d, ok := depth[elements[i].Name]; ok {
+			elements[i].Tier = d + 1
+		} else {
+			elements[i].Tier = 0
+		}
+	}
+}
+
+// parseElementRow membaca satu baris tabel: nama + gambar di kolom 1, resep di kolom 2.
+func parseElementRow(row *goquery.Selection, tier int) (Element, bool) {
+	td1 := row.Find("td:nth-child(1)")
+
+	elementLink := td1.Find("a[title]").First() // Link yang berisi nama elemen
+	elementName, nameExists := elementLink.Attr("title")
+	elementName = strings.TrimSpace(elementName)
+	if !nameExists || elementName == "" {
+		return Element{}, false
+	}
+
+	currentElement := Element{
+		Name:     elementName,
+		Recipes:  [][]string{},
+		Tier:     tier,
+		ImageURL: parseImageURL(td1),
+	}
+
+	// Elemen pack ditandai dengan ikon/link pack di kolom nama.
+	td1.Find("a[title], img[alt]").Each(func(i int, marker *goquery.Selection) {
+		if currentElement.Pack == "" {
+			currentElement.Pack = detectPack(marker.AttrOr("title", "") + " " + marker.AttrOr("alt", ""))
+		}
+	})
+
+	recipeCell := row.Find("td:nth-child(2)")
+	if recipeCell.Length() > 0 {
+		recipeCell.Find("li").Each(func(liIndex int, li *goquery.Selection) {
+			var singleRecipePair []string
+			li.Find("a[title]").Each(func(j int, link *goquery.Selection) {
+				parentName, _ := link.Attr("title")
+				parentName = strings.TrimSpace(parentName)
+				if parentName != "" {
+					singleRecipePair = append(singleRecipePair, parentName)
+				}
+			})
+			if len(singleRecipePair) == 2 && singleRecipePair[0] != "" && singleRecipePair[1] != "" {
+				currentElement.Recipes = append(currentElement.Recipes, singleRecipePair)
+			}
+		})
+	}
+	return currentElement, true
+}
+
+// parseImageURL mengambil URL ikon elemen dan membuang bagian /scale-to-width-down/.
+func parseImageURL(td1 *goquery.Selection) string {
+	var imageURL string
+	imageTag := td1.Find("span.icon-hover a.image img.mw-file-element").First()
+	if imageTag.Length() == 0 {
+		return ""
+	}
+	src, srcExists := imageTag.Attr("data-src")
+	if srcExists && src != "" {
+		imageURL = src
+	} else {
+		src, srcExists = imageTag.Attr("src")
+		if srcExists {
+			imageURL = src
+		}
+	}
+	if strings.Contains(imageURL, "/scale-to-width-down/") {
+		parts := strings.Split(imageURL, "/scale-to-width-down/")
+		baseImageURL := parts[0]
+		if len(parts) > 1 && strings.Contains(parts[1], "?") {
+			queryParams := parts[1][strings.Index(parts[1], "?"):]
+			imageURL = baseImageURL + queryParams
+		} else {
+			imageURL = baseImageURL
+		}
+	}
+	return imageURL
+}
+
+// mergeDuplicateElements menggabungkan entri elemen yang muncul lebih dari sekali (misalnya di
+// beberapa tabel): tier terkecil dipakai dan resep duplikat (urutan parent diabaikan) dibuang.
+func mergeDuplicateElements(initialScrapedElements []Element) map[string]Element {
 	elementsMap := make(map[string]Element)
-	elementTiers := make(map[string]int)
 	for _, elem := range initialScrapedElements {
 		if existingElem, exists := elementsMap[elem.Name]; !exists {
 			elementsMap[elem.Name] = elem
-			elementTiers[elem.Name] = elem.Tier
 		} else {
 			if elem.Tier < existingElem.Tier {
 				existingElem.Tier = elem.Tier
-				elementTiers[elem.Name] = elem.Tier
+			}
+			// Elemen yang juga muncul di base game dianggap milik base game.
+			if elem.Pack == "" {
+				existingElem.Pack = ""
 			}
 			for _, newRecipe := range elem.Recipes {
 				isDuplicateRecipe := false
 				for _, existingRecipe := range existingElem.Recipes {
 					if (existingRecipe[0] == newRecipe[0] && existingRecipe[1] == newRecipe[1]) ||
 						(existingRecipe[0] == newRecipe[1] && existingRecipe[1] == newRecipe[0]) {
-						isDuplicateRecipe = true; break
+						isDuplicateRecipe = true
+						break
 					}
 				}
-				if !isDuplicateRecipe { existingElem.Recipes = append(existingElem.Recipes, newRecipe) }
+				if !isDuplicateRecipe {
+					existingElem.Recipes = append(existingElem.Recipes, newRecipe)
+				}
 			}
 			elementsMap[elem.Name] = existingElem
 		}
 	}
+	return elementsMap
+}
+
+// sortedElements mengembalikan isi map elemen diurutkan berdasarkan nama.
+func sortedElements(elementsMap map[string]Element) []Element {
+	elements := make([]Element, 0, len(elementsMap))
+	for _, elem := range elementsMap {
+		elements = append(elements, elem)
+	}
+	sort.Slice(elements, func(i, j int) bool {
+		return elements[i].Name < elements[j].Name
+	})
+	return elements
+}
+
+// MergeElements hanya menggabungkan entri duplikat tanpa menerapkan filter apa pun.
+// Hasilnya bisa dimuat dengan loadrecipes.LoadBiGraphWithOptions memakai FilterPolicy pilihan.
+func MergeElements(initialScrapedElements []Element) []Element {
+	return sortedElements(mergeDuplicateElements(initialScrapedElements))
+}
+
+// FilterElements me-merge entri duplikat lalu menerapkan filter tier (parent < child)
+// dan filter iteratif parent-valid / elemen-tanpa-resep sampai stabil.
+// Hasil diurutkan berdasarkan nama agar output stabil.
+func FilterElements(initialScrapedElements []Element, baseElementNames []string) []Element {
+	elements, _ := FilterElementsWithAudit(initialScrapedElements, baseElementNames)
+	return elements
+}
+
+// FilterElementsWithAudit sama dengan FilterElements, tetapi juga mengembalikan FilterAudit
+// berisi setiap resep dan elemen yang dihapus beserta aturan dan iterasinya.
+func FilterElementsWithAudit(initialScrapedElements []Element, baseElementNames []string) ([]Element, *FilterAudit) {
+	audit := newFilterAudit()
+	baseElements := make(map[string]bool, len(baseElementNames))
+	for _, name := range baseElementNames {
+		baseElements[name] = true
+	}
+
+	log.Println("Memproses elemen unik dan membuat map tier...")
+	elementsMap := mergeDuplicateElements(initialScrapedElements)
+	elementTiers := make(map[string]int, len(elementsMap))
+	for name, elem := range elementsMap {
+		elementTiers[name] = elem.Tier
+	}
 	log.Printf("Ditemukan %d elemen unik. Map tier dibuat.", len(elementsMap))
+	scrapedRecipeCounts := make(map[string]int, len(elementsMap))
+	for name, elem := range elementsMap {
+		scrapedRecipeCounts[name] = len(elem.Recipes)
+	}
 
 	log.Println("Filter Tahap Awal: Menerapkan filter tier (parent < child)...")
 	recipesRemovedByTierFilter := 0
 	for name, elem := range elementsMap {
-		originalRecipeCount := len(elem.Recipes)
-		elem.Recipes = filterRecipesByTier(elem.Recipes, name, elementTiers)
-		recipesRemovedByTierFilter += (originalRecipeCount - len(elem.Recipes))
+		var removed []RemovedRecipe
+		elem.Recipes, removed = filterRecipesByTier(elem.Recipes, name, elementTiers)
+		recipesRemovedByTierFilter += len(removed)
+		audit.RemovedRecipes = append(audit.RemovedRecipes, removed...)
 		elementsMap[name] = elem
 	}
 	log.Printf("%d resep dihapus oleh filter tier awal.", recipesRemovedByTierFilter)
@@ -203,43 +491,169 @@ func Scrapping() {
 	log.Println("Memulai loop filter iteratif...")
 	iteration := 0
 	for {
-		iteration++; log.Printf("--- Iterasi Filter ke-%d ---", iteration)
+		iteration++
+		log.Printf("--- Iterasi Filter ke-%d ---", iteration)
 		currentElementCount := len(elementsMap)
-		validElementNames := make(map[string]bool); for name := range elementsMap { validElementNames[name] = true }
-		recipesRemovedInvalidParent := 0; tempElementMapForRecipeFilter := make(map[string]Element)
+		validElementNames := make(map[string]bool)
+		for name := range elementsMap {
+			validElementNames[name] = true
+		}
+		recipesRemovedInvalidParent := 0
+		tempElementMapForRecipeFilter := make(map[string]Element)
 		for name, elem := range elementsMap {
-			originalRecipeCount := len(elem.Recipes); elem.Recipes = filterRecipesWithValidParents(elem.Recipes, validElementNames)
-			recipesRemovedInvalidParent += (originalRecipeCount - len(elem.Recipes)); tempElementMapForRecipeFilter[name] = elem
+			var removed []RemovedRecipe
+			elem.Recipes, removed = filterRecipesWithValidParents(elem.Recipes, name, validElementNames, iteration)
+			recipesRemovedInvalidParent += len(removed)
+			audit.RemovedRecipes = append(audit.RemovedRecipes, removed...)
+			tempElementMapForRecipeFilter[name] = elem
 		}
 		elementsMap = tempElementMapForRecipeFilter
-		if recipesRemovedInvalidParent > 0 { log.Printf("  Iterasi %d - Fungsi 3: %d resep dihapus (parent tidak valid).", iteration, recipesRemovedInvalidParent) }
-		elementsRemovedNoRecipe := 0; tempElementMapForElementFilter := make(map[string]Element)
+		if recipesRemovedInvalidParent > 0 {
+			log.Printf("  Iterasi %d - Fungsi 3: %d resep dihapus (parent tidak valid).", iteration, recipesRemovedInvalidParent)
+		}
+		elementsRemovedNoRecipe := 0
+		tempElementMapForElementFilter := make(map[string]Element)
 		for name, elem := range elementsMap {
-			if baseElements[name] || len(elem.Recipes) > 0 { tempElementMapForElementFilter[name] = elem
-			} else { elementsRemovedNoRecipe++ }
+			if baseElements[name] || len(elem.Recipes) > 0 {
+				tempElementMapForElementFilter[name] = elem
+			} else {
+				elementsRemovedNoRecipe++
+				rule := RuleNoRecipesLeft
+				if scrapedRecipeCounts[name] == 0 {
+					rule = RuleNoRecipesScraped
+				}
+				audit.RemovedElements = append(audit.RemovedElements, RemovedElement{
+					Element: name, Tier: elem.Tier, Rule: rule, Iteration: iteration,
+				})
+			}
 		}
 		elementsMap = tempElementMapForElementFilter
-		if elementsRemovedNoRecipe > 0 { log.Printf("  Iterasi %d - Fungsi 2: %d elemen (non-base) dihapus (tanpa resep).", iteration, elementsRemovedNoRecipe) }
+		if elementsRemovedNoRecipe > 0 {
+			log.Printf("  Iterasi %d - Fungsi 2: %d elemen (non-base) dihapus (tanpa resep).", iteration, elementsRemovedNoRecipe)
+		}
 		if len(elementsMap) == currentElementCount && recipesRemovedInvalidParent == 0 && elementsRemovedNoRecipe == 0 {
-			log.Printf("Loop filter stabil setelah %d iterasi.", iteration); break
-		}
-		if iteration > 10 { log.Println("PERINGATAN: Loop filter mencapai batas iterasi maksimum (10). Menghentikan."); break }
-	}
-	finalFilteredElements := make([]Element, 0, len(elementsMap))
-	for _, elem := range elementsMap { finalFilteredElements = append(finalFilteredElements, elem) }
-
-	if len(finalFilteredElements) > 0 {
-		fmt.Println("Memulai proses marshaling data yang sudah difilter ke JSON...")
-		jsonData, err := json.MarshalIndent(finalFilteredElements, "", "  ")
-		if err != nil { log.Fatalf("FATAL: Error marshaling JSON: %s", err) }
-		fmt.Println("Marshal JSON berhasil.")
-		outputFileName := "elements_with_images.json" // Ubah nama file output
-		fmt.Printf("Menulis data JSON ke file '%s'...\n", outputFileName)
-		err = os.WriteFile(outputFileName, jsonData, 0644)
-		if err != nil { log.Fatalf("FATAL: Error menulis file JSON: %s", err) }
-		fmt.Printf("Berhasil menulis data ke '%s'. Jumlah elemen: %d\n", outputFileName, len(finalFilteredElements))
+			log.Printf("Loop filter stabil setelah %d iterasi.", iteration)
+			break
+		}
+		if iteration > 10 {
+			log.Println("PERINGATAN: Loop filter mencapai batas iterasi maksimum (10). Menghentikan.")
+			break
+		}
+	}
+
+	finalFilteredElements := sortedElements(elementsMap)
+	audit.Iterations = iteration
+	audit.sort()
+	log.Printf("Audit filter: %d resep dan %d elemen dihapus.", len(audit.RemovedRecipes), len(audit.RemovedElements))
+	return finalFilteredElements, audit
+}
+
+// WriteJSON menulis elemen sebagai JSON, indented jika pretty = true.
+func WriteJSON(w io.Writer, elements []Element, pretty bool) error {
+	encoder := json.NewEncoder(w)
+	if pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(elements)
+}
+
+// Run menjalankan seluruh pipeline scraping: ambil HTML (URL atau file lokal),
+// parse, merge, lalu tulis JSON ke opts.OutputPath.
+func Run(opts Options) ([]Element, error) {
+	var html io.Reader
+	if opts.InputFile != "" {
+		fmt.Println("Membaca HTML dari file lokal:", opts.InputFile)
+		file, err := os.Open(opts.InputFile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		html = file
 	} else {
-		fmt.Println("Tidak ada elemen yang tersisa setelah filter, file JSON tidak dibuat.")
+		sourceURL := opts.SourceURL
+		if sourceURL == "" {
+			sourceURL = DefaultSourceURL
+		}
+		fmt.Println("Memulai scraping dari:", sourceURL)
+		bodyBytes, err := FetchHTML(sourceURL)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Body response berhasil dibaca (%d bytes).\n", len(bodyBytes))
+		html = bytes.NewReader(bodyBytes)
+	}
+
+	fmt.Println("Memulai parsing HTML dengan goquery...")
+	baseElements := opts.BaseElements
+	if len(baseElements) == 0 {
+		baseElements = DefaultBaseElements
+	}
+	initialScrapedElements, err := ParseElementsLayout(html, opts.Layout, baseElements)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Scraping awal selesai. Ditemukan %d entri elemen.\n", len(initialScrapedElements))
+
+	// Filter tidak diterapkan di sini supaya FilterPolicy di loader menjadi satu-satunya
+	// tempat aturan resep; audit dibuat oleh loader sendiri, jadi isinya sama dengan resep
+	// yang benar-benar dibuang saat file output dimuat.
+	mergedElements := MergeElements(initialScrapedElements)
+	if opts.AuditPath != "" {
+		policy, err := loadrecipes.ParseFilterPolicy(opts.AuditPolicy)
+		if err != nil {
+			return nil, err
+		}
+		audit := loadrecipes.NewFilterReport(toElementInputs(mergedElements), loadrecipes.LoadOptions{BaseElements: baseElements, Policy: policy})
+		if err := writeAuditFile(opts.AuditPath, audit); err != nil {
+			return nil, fmt.Errorf("error menulis file audit: %w", err)
+		}
+		fmt.Printf("Audit filter ditulis ke '%s'.\n", opts.AuditPath)
+	}
+	if len(mergedElements) == 0 {
+		return nil, fmt.Errorf("tidak ada elemen yang ter-parse, file JSON tidak dibuat")
 	}
-	fmt.Println("\nScraping dan Filtering Selesai.")
-}
\ No newline at end of file
+
+	outputFileName := opts.OutputPath
+	if outputFileName == "" {
+		outputFileName = DefaultOutputPath
+	}
+	fmt.Printf("Menulis data JSON ke file '%s'...\n", outputFileName)
+	out, err := os.Create(outputFileName)
+	if err != nil {
+		return nil, fmt.Errorf("error menulis file JSON: %w", err)
+	}
+	defer out.Close()
+	if err := WriteJSON(out, mergedElements, opts.Pretty); err != nil {
+		return nil, fmt.Errorf("error marshaling JSON: %w", err)
+	}
+	fmt.Printf("Berhasil menulis data ke '%s'. Jumlah elemen: %d\n", outputFileName, len(mergedElements))
+	return mergedElements, nil
+}
+
+// toElementInputs mengubah Element menjadi input loader.
+func toElementInputs(elements []Element) []loadrecipes.ElementInput {
+	inputs := make([]loadrecipes.ElementInput, len(elements))
+	for i, element := range elements {
+		inputs[i] = loadrecipes.ElementInput(element)
+	}
+	return inputs
+}
+
+// writeAuditFile menulis FilterReport loader sebagai JSON ke path.
+func writeAuditFile(path string, audit *loadrecipes.FilterReport) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return audit.WriteJSON(out)
+}
+
+// Scrapping menjalankan Run dengan DefaultOptions.
+func Scrapping() error {
+	_, err := Run(DefaultOptions())
+	if err == nil {
+		fmt.Println("\nScraping dan Filtering Selesai.")
+	}
+	return err
+}
@@ -0,0 +1,270 @@
+package scrape
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Starath/Tubes2_BE_SayMyName/loadrecipes"
+)
+
+// parsedElement adalah bagian Element yang dicek test parser.
+type parsedElement struct {
+	Tier    int
+	Pack    string
+	Recipes [][]string
+}
+
+func parseFixture(t *testing.T, layout string) []Element {
+	t.Helper()
+	file, err := os.Open("testdata/" + layout + ".html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	elements, err := ParseElementsLayout(file, layout, DefaultBaseElements)
+	if err != nil {
+		t.Fatalf("ParseElementsLayout(%s): %v", layout, err)
+	}
+	return elements
+}
+
+func TestParseElementsLayout(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   map[string]parsedElement
+	}{
+		{
+			layout: LayoutLA2,
+			want: map[string]parsedElement{
+				"Air":     {Tier: 1, Recipes: [][]string{}},
+				"Mud":     {Tier: 2, Recipes: [][]string{{"Earth", "Water"}}},
+				"Steam":   {Tier: 2, Recipes: [][]string{{"Fire", "Water"}, {"Air", "Fire"}}},
+				"Empty":   {Tier: 2, Recipes: [][]string{}},
+				"Brick":   {Tier: 3, Recipes: [][]string{{"Mud", "Fire"}, {"Steam", "Brick"}}},
+				"Vampire": {Tier: 3, Pack: "Myths and Monsters", Recipes: [][]string{{"Mud", "Air"}}},
+				"Haunt":   {Tier: 4, Pack: "Myths and Monsters", Recipes: [][]string{{"Ghost", "Air"}}},
+			},
+		},
+		{
+			layout: LayoutLA1,
+			want: map[string]parsedElement{
+				"Air":    {Tier: 1, Recipes: [][]string{}},
+				"Mud":    {Tier: 2, Recipes: [][]string{{"Earth", "Water"}}},
+				"Brick":  {Tier: 3, Recipes: [][]string{{"Mud", "Fire"}}},
+				"Wall":   {Tier: 4, Recipes: [][]string{{"Brick", "Brick"}}},
+				"Swamp":  {Tier: 3, Recipes: [][]string{{"Mud", "Water"}, {"Earth", "Mud"}}},
+				"Orphan": {Tier: 0, Recipes: [][]string{{"Unknown", "Air"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			// Elemen duplikat (Steam di la2) di-merge dulu, seperti di Run.
+			got := make(map[string]parsedElement)
+			for _, element := range MergeElements(parseFixture(t, tt.layout)) {
+				got[element.Name] = parsedElement{Tier: element.Tier, Pack: element.Pack, Recipes: element.Recipes}
+			}
+			for name, want := range tt.want {
+				if !reflect.DeepEqual(got[name], want) {
+					t.Errorf("%s = %+v, want %+v", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseElementsImageURL(t *testing.T) {
+	for _, element := range parseFixture(t, LayoutLA2) {
+		if strings.Contains(element.ImageURL, "/scale-to-width-down/") || !strings.HasSuffix(element.ImageURL, element.Name+".svg/revision/latest?cb=1") {
+			t.Errorf("%s: ImageURL = %q", element.Name, element.ImageURL)
+		}
+	}
+}
+
+func TestParseElementsLayoutErrors(t *testing.T) {
+	if _, err := ParseElementsLayout(strings.NewReader("<html></html>"), LayoutLA2, DefaultBaseElements); err == nil {
+		t.Error("HTML tanpa tabel tidak ditolak")
+	}
+	if _, err := ParseElementsLayout(strings.NewReader("<html></html>"), "la3", DefaultBaseElements); err == nil {
+		t.Error("layout tidak dikenal tidak ditolak")
+	}
+}
+
+func TestFilterElementsWithAudit(t *testing.T) {
+	elements, audit := FilterElementsWithAudit(parseFixture(t, LayoutLA2), DefaultBaseElements)
+
+	var names []string
+	for _, element := range elements {
+		names = append(names, element.Name)
+	}
+	wantNames := []string{"Air", "Brick", "Earth", "Fire", "Mud", "Steam", "Vampire", "Water"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("elemen tersisa = %v, want %v", names, wantNames)
+	}
+
+	wantRecipes := []struct {
+		element   string
+		rule      string
+		iteration int
+	}{
+		{element: "Brick", rule: RuleTier, iteration: 0},
+		{element: "Ghost", rule: RuleUnknownParent, iteration: 0},
+		{element: "Haunt", rule: RuleInvalidParent, iteration: 2},
+	}
+	if len(audit.RemovedRecipes) != len(wantRecipes) {
+		t.Fatalf("RemovedRecipes = %+v, want %d entri", audit.RemovedRecipes, len(wantRecipes))
+	}
+	for i, want := range wantRecipes {
+		got := audit.RemovedRecipes[i]
+		if got.Element != want.element || got.Rule != want.rule || got.Iteration != want.iteration {
+			t.Errorf("RemovedRecipes[%d] = %+v, want %s/%s/iterasi %d", i, got, want.element, want.rule, want.iteration)
+		}
+	}
+
+	wantElements := []RemovedElement{
+		{Element: "Empty", Tier: 2, Rule: RuleNoRecipesScraped, Iteration: 1},
+		{Element: "Ghost", Tier: 3, Rule: RuleNoRecipesLeft, Iteration: 1},
+		{Element: "Haunt", Tier: 4, Rule: RuleNoRecipesLeft, Iteration: 2},
+	}
+	if !reflect.DeepEqual(audit.RemovedElements, wantElements) {
+		t.Errorf("RemovedElements = %+v, want %+v", audit.RemovedElements, wantElements)
+	}
+	if audit.Iterations != 3 {
+		t.Errorf("Iterations = %d, want 3", audit.Iterations)
+	}
+
+	recipes, removed := audit.ForElement("Haunt")
+	if len(recipes) != 1 || removed == nil || removed.Rule != RuleNoRecipesLeft {
+		t.Errorf("ForElement(Haunt) = %+v, %+v", recipes, removed)
+	}
+}
+
+// loadElements memuat elements dengan DefaultLoadOptions seperti dataset dari file.
+func loadElements(t *testing.T, elements []Element) *loadrecipes.BiGraphAlchemy {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, elements, false); err != nil {
+		t.Fatal(err)
+	}
+	graph, err := loadrecipes.LoadBiGraphFromReader(&buf, "fixture", loadrecipes.DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat elemen: %v", err)
+	}
+	return graph
+}
+
+// craftableRecipes mengembalikan resep setiap elemen yang bisa dibuat, hanya yang kedua
+// bahannya juga bisa dibuat.
+func craftableRecipes(t *testing.T, graph *loadrecipes.BiGraphAlchemy) map[string][]string {
+	t.Helper()
+	cg, err := graph.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipes := make(map[string][]string)
+	for id := 0; id < cg.Len(); id++ {
+		child := loadrecipes.ElementID(id)
+		if cg.IsBase(child) || !cg.Craftability.CanMake(child) {
+			continue
+		}
+		for _, pair := range cg.ChildToParents[child] {
+			if cg.Craftability.CanMake(pair.Mat1) && cg.Craftability.CanMake(pair.Mat2) {
+				recipes[cg.Name(child)] = append(recipes[cg.Name(child)], cg.Name(pair.Mat1)+"+"+cg.Name(pair.Mat2))
+			}
+		}
+		sort.Strings(recipes[cg.Name(child)])
+	}
+	return recipes
+}
+
+// TestDefaultFilterPolicyMatchesFilterElements memuat hasil scrape tanpa filter dengan
+// DefaultFilterPolicy dan membandingkannya dengan hasil FilterElements. Loader tidak menghapus
+// elemen tanpa resep secara iteratif, jadi elemen itu (dan resep yang memakainya) tetap ada;
+// bagian yang bisa dibuat harus sama persis.
+func TestDefaultFilterPolicyMatchesFilterElements(t *testing.T) {
+	scraped := parseFixture(t, LayoutLA2)
+	raw := loadElements(t, MergeElements(scraped))
+	filtered := loadElements(t, FilterElements(scraped, DefaultBaseElements))
+
+	if got, want := craftableRecipes(t, raw), craftableRecipes(t, filtered); !reflect.DeepEqual(got, want) || len(want) == 0 {
+		t.Errorf("resep yang bisa dibuat berbeda:\nscrape mentah: %v\nhasil filter:  %v", got, want)
+	}
+
+	// Elemen yang dihapus scraper tetap dimuat, tetapi tidak bisa dibuat.
+	for _, name := range []string{"Empty", "Ghost", "Haunt"} {
+		if !raw.AllElements[name] || filtered.AllElements[name] {
+			t.Errorf("%s: ada di scrape mentah = %v, di hasil filter = %v; want true, false", name, raw.AllElements[name], filtered.AllElements[name])
+		}
+	}
+	if len(raw.ChildToParents["Haunt"]) == 0 {
+		t.Error("resep Haunt (bahannya Ghost) seharusnya tetap dimuat dari scrape mentah")
+	}
+	if raw.RecipeCount() <= filtered.RecipeCount() {
+		t.Errorf("RecipeCount scrape mentah = %d, want lebih dari %d", raw.RecipeCount(), filtered.RecipeCount())
+	}
+}
+
+// TestRunAuditMatchesLoader menjalankan Run dari fixture HTML dan memastikan audit berisi tepat
+// resep yang dibuang loader saat file output dimuat: setiap resep hasil scrape ada di graf
+// atau di audit, tidak pernah di keduanya.
+func TestRunAuditMatchesLoader(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{
+		InputFile:    "testdata/la2.html",
+		OutputPath:   filepath.Join(dir, "elements.json"),
+		AuditPath:    filepath.Join(dir, "audit.json"),
+		Layout:       LayoutLA2,
+		BaseElements: DefaultBaseElements,
+	}
+	elements, err := Run(opts)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	auditFile, err := os.ReadFile(opts.AuditPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var audit loadrecipes.FilterReport
+	if err := json.Unmarshal(auditFile, &audit); err != nil {
+		t.Fatalf("membaca audit: %v", err)
+	}
+	graph, err := loadrecipes.LoadBiGraphWithOptions(opts.OutputPath, loadrecipes.DefaultLoadOptions())
+	if err != nil {
+		t.Fatalf("memuat output: %v", err)
+	}
+
+	rejected := make(map[string]string)
+	for _, recipe := range audit.RejectedRecipes {
+		rejected[recipe.Element+": "+strings.Join(recipe.Recipe, " + ")] = recipe.Rule
+	}
+	for _, element := range elements {
+		for _, recipe := range element.Recipes {
+			key := element.Name + ": " + strings.Join(recipe, " + ")
+			_, inAudit := rejected[key]
+			inGraph := len(recipe) == 2 && slices.Contains(graph.ChildToParents[element.Name], loadrecipes.ConstructPair(recipe[0], recipe[1]))
+			if inAudit == inGraph {
+				t.Errorf("%s: di audit = %v, di graf = %v; want tepat satu", key, inAudit, inGraph)
+			}
+		}
+	}
+
+	// Brick = Steam + Brick ditolak aturan self loader (filter scraper menyebutnya tier).
+	wantRules := map[string]string{
+		"Brick: Steam + Brick": loadrecipes.RuleSelf,
+		"Ghost: Spirit + Air":  loadrecipes.RuleUnknown,
+	}
+	if !reflect.DeepEqual(rejected, wantRules) {
+		t.Errorf("resep di audit = %v, want %v", rejected, wantRules)
+	}
+	if audit.Policy != loadrecipes.DefaultFilterPolicy.String() {
+		t.Errorf("Policy = %q, want %q", audit.Policy, loadrecipes.DefaultFilterPolicy.String())
+	}
+}